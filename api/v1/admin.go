@@ -0,0 +1,43 @@
+package v1
+
+type GetUserListReq struct {
+	Keyword    string `json:"keyword"`    // 手机号或昵称
+	RoleType   *int   `json:"roleType"`   // 用户角色，不传或 -1 查询全部
+	CollegeId  uint   `json:"collegeId"`  // 学院ID
+	IsDisabled *int   `json:"isDisabled"` // 是否禁用，不传或 -1 查询全部
+	IsDeleted  *int   `json:"isDeleted"`  // 是否已注销，不传或 -1 查询全部
+	PageRequest
+}
+
+type UserManageData struct {
+	UserId     string `json:"userId"`
+	Phone      string `json:"phone"`
+	Nickname   string `json:"nickname"`
+	RoleType   int    `json:"roleType"`
+	Email      string `json:"email"`
+	CollegeId  uint   `json:"collegeId"`
+	StudentId  string `json:"studentId"`
+	IsDisabled int    `json:"isDisabled"`
+	IsDeleted  int    `json:"isDeleted"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type UserManageList struct {
+	UserList []*UserManageData `json:"userList"`
+	PageResponse
+}
+
+type UpdateUserRoleReq struct {
+	UserId    string `json:"userId" binding:"required"`
+	RoleType  int    `json:"roleType"`  // 目标角色
+	CollegeId uint   `json:"collegeId"` // 学校管理员必须绑定学院
+}
+
+type SetUserDisabledReq struct {
+	UserId   string `json:"userId" binding:"required"`
+	Disabled bool   `json:"disabled"`
+}
+
+type UserIdReq struct {
+	UserId string `json:"userId" binding:"required"`
+}
@@ -1,28 +1,76 @@
 package v1
 
 import (
+	"encoding/json"
 	"projectName/internal/model/vo"
 	"time"
 )
 
 // CreateArticleRequest 用于接收创建文章请求的数据
 type CreateArticleRequest struct {
-	Title           string       `json:"title" binding:"required"`        // 文章标题
-	Content         string       `json:"content" binding:"required"`      // 文章内容
-	ContentShort    string       `json:"contentShort"`                    // 文章摘要
-	AuthorID        string       `json:"authorId" binding:"required"`     // 作者ID
-	CategoryID      uint         `json:"categoryId"`                      // 文章分类ID
-	Importance      int          `json:"importance"`                      // 文章重要性
-	VisibleRange    string       `json:"visibleRange" binding:"required"` // 可见范围
-	CommentDisabled bool         `json:"commentDisabled"`                 // 是否禁用评论
-	SourceURI       string       `json:"sourceUri"`                       // 文章外链
-	UploadedFiles   []FileUpload `json:"uploadedFiles"`                   // 上传的文件列表
+	Title           string       `json:"title" binding:"required"`   // 文章标题
+	Content         string       `json:"content" binding:"required"` // 文章内容
+	ContentShort    string       `json:"contentShort"`               // 文章摘要
+	AuthorID        string       `json:"-"`                          // 作者ID，由服务端设置为当前登录用户
+	CategoryID      uint         `json:"categoryId"`                 // 文章分类ID
+	Importance      int          `json:"importance"`                 // 文章重要性
+	VisibleRange    Visibility   `json:"visibleRange"`               // 可见范围
+	CommentDisabled bool         `json:"commentDisabled"`            // 是否禁用评论
+	SourceURI       string       `json:"sourceUri"`                  // 文章外链
+	UploadedFiles   []FileUpload `json:"uploadedFiles"`              // 上传的文件列表
+	PublishAt       string       `json:"publishAt"`                  // 定时发布时间，格式 2006-01-02 15:04:05，为空时立即发布
+	Tags            []string     `json:"tags"`                       // 文章标签
+}
+
+// Visibility 文章可见范围，共享列表中的用户和学院不受可见类型限制
+type Visibility struct {
+	Scope            string   `json:"scope" binding:"omitempty,oneof=public login college role private"` // 可见类型：所有人、登录用户、同学院、指定角色及以上、仅自己
+	MinRole          int      `json:"minRole" binding:"omitempty,min=0,max=3"`                           // 可见类型为 role 时要求的最低角色
+	SharedUserIds    []string `json:"sharedUserIds,omitempty" binding:"omitempty,max=100"`               // 额外共享的用户ID，只返回给作者本人和管理员
+	SharedCollegeIds []uint   `json:"sharedCollegeIds,omitempty" binding:"omitempty,max=100"`            // 额外共享的学院ID，只返回给作者本人和管理员
+}
+
+// UnmarshalJSON 兼容直接传可见类型字符串的旧写法
+func (v *Visibility) UnmarshalJSON(data []byte) error {
+	var scope string
+	if err := json.Unmarshal(data, &scope); err == nil {
+		*v = Visibility{Scope: scope}
+		return nil
+	}
+	type visibility Visibility
+	return json.Unmarshal(data, (*visibility)(v))
 }
 
 // FileUpload 用于接收上传文件的信息
 type FileUpload struct {
-	FileName string `json:"fileName" ` // 文件名
-	FileURL  string `json:"fileUrl" `  // 文件URL
+	AttachmentId uint   `json:"attachmentId"` // 上传接口返回的附件ID，为 0 时表示外部链接
+	FileName     string `json:"fileName" `    // 文件名
+	FileURL      string `json:"fileUrl" `     // 文件URL
+}
+
+// SaveDraftRequest 保存草稿，字段均可为空，发布时再校验
+type SaveDraftRequest struct {
+	ArticleID       uint         `json:"articleId"`       // 草稿ID，为 0 时新建草稿
+	Title           string       `json:"title"`           // 文章标题
+	Content         string       `json:"content"`         // 文章内容
+	ContentShort    string       `json:"contentShort"`    // 文章摘要
+	CategoryID      uint         `json:"categoryId"`      // 文章分类ID
+	Importance      int          `json:"importance"`      // 文章重要性
+	VisibleRange    Visibility   `json:"visibleRange"`    // 可见范围
+	CommentDisabled bool         `json:"commentDisabled"` // 是否禁用评论
+	SourceURI       string       `json:"sourceUri"`       // 文章外链
+	UploadedFiles   []FileUpload `json:"uploadedFiles"`   // 上传的文件列表
+	PublishAt       string       `json:"publishAt"`       // 定时发布时间
+	Tags            []string     `json:"tags"`            // 文章标签
+}
+
+type PublishArticleRequest struct {
+	ArticleID uint `json:"articleId" binding:"required"` // 草稿ID
+}
+
+type PublishArticleResponseData struct {
+	ArticleID uint `json:"articleId"` // 文章ID
+	Status    int  `json:"status"`    // 发布后的状态：已发布、待审核或定时发布
 }
 
 type CreateArticleResponseData struct {
@@ -38,14 +86,22 @@ type ArticleData struct {
 	Category        string       `json:"category"`        // 文章分类
 	CategoryID      uint         `json:"categoryId"`      // 文章分类ID
 	Importance      int          `json:"importance"`      // 文章重要性
-	VisibleRange    string       `json:"visibleRange" `   // 可见范围
+	VisibleRange    Visibility   `json:"visibleRange" `   // 可见范围
 	CommentDisabled bool         `json:"commentDisabled"` // 是否禁用评论
 	SourceURI       string       `json:"sourceUri"`       // 文章外链
 	UploadedFiles   []FileUpload `json:"uploadedFiles"`   // 上传的文件列表
 	Status          int          `json:"status"`          // 文章状态
+	ReviewRemark    string       `json:"reviewRemark"`    // 审核意见（驳回原因）
+	PublishAt       string       `json:"publishAt"`       // 定时发布时间
 	CreatedAt       string       `json:"createdAt"`       // 文章创建时间
 	UpdatedAt       string       `json:"updateAt"`        // 文章更新时间
-	//Tags            []Tags       `json:"tags"`            //todo：文章标签
+	Comments        int64        `json:"comments"`        // 评论数
+	Tags            []string     `json:"tags"`            // 文章标签
+	Views           int64        `json:"views"`           // 浏览量
+	Likes           int64        `json:"likes"`           // 点赞数
+	Favorites       int64        `json:"favorites"`       // 收藏数
+	Liked           bool         `json:"liked"`           // 当前用户是否已点赞，仅文章详情返回
+	Favorited       bool         `json:"favorited"`       // 当前用户是否已收藏，仅文章详情返回
 }
 
 type CategoryList []vo.CategoryView
@@ -53,6 +109,79 @@ type CategoryData struct {
 	CategoryList
 }
 
+type CreateCategoryReq struct {
+	CategoryName string `json:"categoryName" binding:"required"` // 分类名称
+	ParentId     uint   `json:"parentId"`                        // 父分类ID，0 为根分类
+	NeedReview   bool   `json:"needReview"`                      // 该分类下的文章是否需要审核
+}
+
+type CreateCategoryResponseData struct {
+	CategoryId uint `json:"categoryId"` // 分类ID
+}
+
+type UpdateCategoryReq struct {
+	CategoryId   uint   `json:"categoryId" binding:"required"` // 分类ID
+	CategoryName string `json:"categoryName"`                  // 新分类名称，为空时不修改
+	ParentId     *uint  `json:"parentId"`                      // 新父分类ID，为空时不移动
+	NeedReview   *bool  `json:"needReview"`                    // 审核开关，为空时不修改
+}
+
+type DeleteCategoryReq struct {
+	CategoryId       uint `json:"categoryId" binding:"required"` // 分类ID
+	MoveToCategoryId uint `json:"moveToCategoryId"`              // 分类下仍有文章时，将文章移动到该分类
+}
+
+type ReviewArticleReq struct {
+	ArticleID uint   `json:"articleId" binding:"required"` // 文章ID
+	Approved  bool   `json:"approved"`                     // 是否通过
+	Reason    string `json:"reason"`                       // 驳回原因
+}
+
+type GetArticleRevisionListReq struct {
+	ArticleID uint `json:"articleId" binding:"required"` // 文章ID
+	PageRequest
+}
+
+type ArticleRevisionData struct {
+	Version      int        `json:"version"`      // 版本号
+	Title        string     `json:"title"`        // 该版本的标题
+	ContentShort string     `json:"contentShort"` // 该版本的摘要
+	CategoryID   uint       `json:"categoryId"`   // 该版本的分类ID
+	VisibleRange Visibility `json:"visibleRange"` // 该版本的可见范围
+	Editor       string     `json:"editor"`       // 修改人昵称
+	CreatedAt    string     `json:"createdAt"`    // 版本创建时间
+}
+
+type ArticleRevisionList struct {
+	RevisionList []*ArticleRevisionData `json:"revisionList"`
+	PageResponse
+}
+
+type GetArticleRevisionDiffReq struct {
+	ArticleID   uint `json:"articleId" binding:"required"` // 文章ID
+	FromVersion int  `json:"fromVersion"`                  // 起始版本号，0 表示当前内容
+	ToVersion   int  `json:"toVersion"`                    // 目标版本号，0 表示当前内容
+}
+
+// DiffItem 一段差异文本，type 为 equal、insert 或 delete
+type DiffItem struct {
+	Type string `json:"type"` // 差异类型
+	Text string `json:"text"` // 差异文本
+}
+
+type ArticleRevisionDiffData struct {
+	FromVersion int        `json:"fromVersion"` // 起始版本号
+	ToVersion   int        `json:"toVersion"`   // 目标版本号
+	FromTitle   string     `json:"fromTitle"`   // 起始版本标题
+	ToTitle     string     `json:"toTitle"`     // 目标版本标题
+	Diffs       []DiffItem `json:"diffs"`       // 正文按行比较的差异
+}
+
+type RollbackArticleReq struct {
+	ArticleID uint `json:"articleId" binding:"required"` // 文章ID
+	Version   int  `json:"version" binding:"required"`   // 回滚到的版本号
+}
+
 type GetArticleRequest struct {
 	ArticleID uint `json:"articleId"` // 文章ID
 }
@@ -89,7 +218,8 @@ type GetUserArticleListReq struct {
 	CategoryID uint   `json:"categoryId"` // 文章分类ID
 	CreatedAt  string `json:"createdAt"`  // 文章创建时间
 	CreatedEnd string `json:"CreatedEnd"` // 文章结束时间
-	Status     int    `json:"status"`     // 文章状态
+	Status     int    `json:"status"`     // 文章状态，-1 或 0 时不过滤（草稿通过 draft 查询）
+	Draft      bool   `json:"draft"`      // 为 true 时只查询草稿，否则不包含草稿
 	PageRequest
 }
 
@@ -100,36 +230,89 @@ type GetArticleListByEsReq struct {
 	Keywords        []string `json:"keywords"`        // 搜索的关键字数组
 	PhraseMatch     bool     `json:"phraseMatch"`     // 是否启用短语匹配
 	AdvSearch       bool     `json:"advSearch"`       // 是否启用高级搜索
-	Column          string   `json:"column"`          // 排序字段，通常是 "_score"
+	Column          string   `json:"column"`          // 排序字段：_score、created_at、updated_at、importance、views（热度）
 	Order           string   `json:"order"`           // 排序方式，"asc" 或 "desc"
 	Importance      string   `json:"importance"`      // 文章重要性
 	CreateTimeStart string   `json:"createTimeStart"` // 文章创建时间
 	CreateTimeEnd   string   `json:"createTimeEnd"`   // 文章结束时间
 	Categories      []int    `json:"categories"`      // 分类id，用于筛选
+	Tags            []string `json:"tags"`            // 标签，用于筛选，需同时包含全部标签
 }
 
 type SearchArticleResp struct {
 	PageResponse
-	Articles []ArticleSearchInfo `json:"articles"` // 文章列表
+	Articles  []ArticleSearchInfo `json:"articles"`  // 文章列表
+	TagFacets []TagFacet          `json:"tagFacets"` // 搜索结果中的标签分布
+}
+
+// TagFacet 标签聚合统计
+type TagFacet struct {
+	TagName string `json:"tagName"` // 标签名称
+	Count   int64  `json:"count"`   // 命中文章数
+}
+
+type TagData struct {
+	TagId        uint   `json:"tagId"`        // 标签ID
+	TagName      string `json:"tagName"`      // 标签名称
+	ArticleCount int64  `json:"articleCount"` // 关联文章数
+}
+
+type GetArticleListByTagReq struct {
+	TagName string `json:"tagName" binding:"required"` // 标签名称
+	PageRequest
+}
+
+// AttachmentHit 搜索命中的附件
+type AttachmentHit struct {
+	AttachmentId uint     `json:"attachment_id"`
+	FileName     string   `json:"file_name"`
+	Highlight    []string `json:"highlight"`
 }
 
 type ArticleSearchInfo struct {
-	ArticleID       uint      `json:"article_id"`
-	Title           string    `json:"title"`
-	Content         string    `json:"content"`
-	ContentShort    string    `json:"content_short"`
-	Author          string    `json:"author"`
-	Category        string    `json:"category"`
-	Importance      int       `json:"importance"`
-	VisibleRange    string    `json:"visible_range"`
-	CommentDisabled bool      `json:"comment_disabled"`
-	SourceURI       string    `json:"source_uri"`
-	Status          int       `json:"status"`
-	UploadedFile    bool      `json:"uploaded_file"`
-	CreatedAt       time.Time `json:"created_at"` // 使用 sql.NullTime
-	UpdatedAt       time.Time `json:"updated_at"` // 使用 sql.NullTime
-	Score           float64   `json:"score"`      // 评分（例如：基于ES的相关度评分）
-	//Tags            []string     `json:"tags"`             // 文章标签（可选）
-	//Comments        *int         `json:"comments"`         // 评论数（可选）
-	//Views           *int         `json:"views"`            // 浏览量（可选）
+	ArticleID       uint            `json:"article_id"`
+	Title           string          `json:"title"`
+	Content         string          `json:"content"`
+	ContentShort    string          `json:"content_short"`
+	Author          string          `json:"author"`
+	Category        string          `json:"category"`
+	Importance      int             `json:"importance"`
+	VisibleRange    string          `json:"visible_range"`
+	CommentDisabled bool            `json:"comment_disabled"`
+	SourceURI       string          `json:"source_uri"`
+	Status          int             `json:"status"`
+	UploadedFile    bool            `json:"uploaded_file"`
+	CreatedAt       time.Time       `json:"created_at"`      // 使用 sql.NullTime
+	UpdatedAt       time.Time       `json:"updated_at"`      // 使用 sql.NullTime
+	Score           float64         `json:"score"`           // 评分（例如：基于ES的相关度评分）
+	Tags            []string        `json:"tags"`            // 文章标签
+	Comments        int64           `json:"comments"`        // 评论数
+	AttachmentHits  []AttachmentHit `json:"attachment_hits"` // 命中的附件及高亮片段
+	Views           int64           `json:"views"`           // 浏览量
+}
+
+// EsSyncStatusData es同步任务的积压情况
+type EsSyncStatusData struct {
+	Pending         int64  `json:"pending"`         // 待同步的任务数
+	Retrying        int64  `json:"retrying"`        // 失败后等待重试的任务数
+	OldestCreatedAt string `json:"oldestCreatedAt"` // 最早的待同步任务创建时间
+	LagSeconds      int64  `json:"lagSeconds"`      // 同步延迟，即最早的待同步任务已等待的秒数
+}
+
+// SearchSuggestData 搜索联想结果
+type SearchSuggestData struct {
+	Titles      []SearchSuggestTitle `json:"titles"`      // 标题联想
+	Corrections []SearchCorrection   `json:"corrections"` // 拼写纠错（您是不是要找）
+}
+
+// SearchSuggestTitle 联想到的文章标题
+type SearchSuggestTitle struct {
+	ArticleID uint   `json:"articleId"`
+	Title     string `json:"title"`
+}
+
+// SearchCorrection 纠错后的搜索词
+type SearchCorrection struct {
+	Text        string `json:"text"`        // 纠错后的搜索词
+	Highlighted string `json:"highlighted"` // 纠正的部分用 <mark> 标记
 }
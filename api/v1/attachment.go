@@ -0,0 +1,14 @@
+package v1
+
+type UploadFileResponseData struct {
+	AttachmentId uint   `json:"attachmentId"` // 附件ID，新建或修改文章时通过 uploadedFiles 关联
+	FileName     string `json:"fileName"`     // 文件名
+	FileURL      string `json:"fileUrl"`      // 获取下载地址的接口
+	Size         int64  `json:"size"`         // 文件大小（字节）
+	MimeType     string `json:"mimeType"`     // 文件类型
+}
+
+type GetDownloadUrlResponseData struct {
+	DownloadURL string `json:"downloadUrl"` // 带签名的下载地址，过期后需重新获取
+	ExpiresAt   string `json:"expiresAt"`   // 下载地址过期时间
+}
@@ -0,0 +1,37 @@
+package v1
+
+type CreateCommentReq struct {
+	ArticleID uint   `json:"articleId" binding:"required"` // 文章ID
+	ParentId  uint   `json:"parentId"`                     // 被回复的评论ID，为 0 时发表顶层评论
+	Content   string `json:"content" binding:"required"`   // 评论内容
+}
+
+type CreateCommentResponseData struct {
+	CommentId uint `json:"commentId"` // 评论ID
+}
+
+type GetCommentListReq struct {
+	ArticleID uint `json:"articleId" binding:"required"` // 文章ID
+	PageRequest
+}
+
+type CommentData struct {
+	CommentId uint           `json:"commentId"` // 评论ID
+	ParentId  uint           `json:"parentId"`  // 被回复的评论ID
+	UserId    string         `json:"userId"`    // 评论人ID
+	Nickname  string         `json:"nickname"`  // 评论人昵称
+	ReplyTo   string         `json:"replyTo"`   // 被回复人昵称
+	Content   string         `json:"content"`   // 评论内容
+	CreatedAt string         `json:"createdAt"` // 评论时间
+	ReplyList []*CommentData `json:"replyList"` // 回复列表，仅顶层评论有
+}
+
+// CommentList 分页信息按顶层评论统计
+type CommentList struct {
+	CommentList []*CommentData `json:"commentList"`
+	PageResponse
+}
+
+type DeleteCommentReq struct {
+	CommentId uint `json:"commentId" binding:"required"` // 评论ID
+}
@@ -20,6 +20,9 @@ var (
 	ErrLogoutFail      = newError(1008, "用户退出失败")
 	ErrCancelFail      = newError(1009, "用户注销失败")
 	ErrEmailFormat     = newError(1010, "邮箱格式错误")
+	ErrTokenRevoked    = newError(1011, "登录已失效，请重新登录")
+	ErrRefreshToken    = newError(1012, "刷新token无效或已过期")
+	ErrRefreshReused   = newError(1013, "刷新token重复使用，已强制下线")
 
 	ErrArticleNotExist     = newError(1101, "文章不存在")
 	ErrUpdateArticleFailed = newError(1102, "修改文章失败")
@@ -51,4 +54,35 @@ var (
 	ErrUserAuthFailed      = newError(20003, "用户认证失败")
 	ErrArticleAlreadyExist = newError(20004, "文章已存在")
 	ErrCreateArticleFailed = newError(20005, "创建文章失败")
+	ErrUserAuthNotExist    = newError(20006, "认证请求不存在")
+	ErrUserAuthProcessed   = newError(20007, "认证请求已处理")
+	ErrUserDisabled        = newError(20008, "用户已被禁用")
+	ErrRoleTypeInvalid     = newError(20009, "用户角色错误")
+	ErrCollegeRequired     = newError(20010, "学校管理员必须绑定学院")
+	ErrOperateSelf         = newError(20011, "不能操作自己的账号")
+	ErrUserNotDeleted      = newError(20012, "用户未注销")
+	ErrCategoryNotExist    = newError(20013, "分类不存在")
+	ErrCategoryExist       = newError(20014, "同级分类名称已存在")
+	ErrCategoryCycle       = newError(20015, "不能将分类移动到自身或其子分类下")
+	ErrCategoryHasChildren = newError(20016, "分类下存在子分类")
+	ErrCategoryHasArticles = newError(20017, "分类下存在文章")
+	ErrRejectReasonEmpty   = newError(20018, "驳回原因不能为空")
+	ErrPublishAtFormat     = newError(20019, "定时发布时间格式错误")
+	ErrArticleNotDraft     = newError(20020, "文章不是草稿")
+	ErrArticleFieldEmpty   = newError(20021, "文章标题、内容和可见范围不能为空")
+	ErrRevisionNotExist    = newError(20022, "文章历史版本不存在")
+	ErrCommentDisabled     = newError(20023, "该文章已关闭评论")
+	ErrCommentNotExist     = newError(20024, "评论不存在")
+	ErrTagInvalid          = newError(20025, "标签数量不能超过10个，且每个标签不超过20个字符")
+	ErrFileTooLarge        = newError(20026, "文件大小超过限制")
+	ErrFileTypeNotAllowed  = newError(20027, "不支持的文件类型")
+	ErrAttachmentNotExist  = newError(20028, "附件不存在")
+	ErrDownloadUrlInvalid  = newError(20029, "下载地址无效或已过期")
+	ErrStorageFailed       = newError(20030, "文件存储失败")
+	ErrVisibilityInvalid   = newError(20031, "可见范围设置不正确")
+	ErrCursorInvalid       = newError(20032, "分页游标无效")
+	ErrFolderNotExist      = newError(20033, "收藏夹不存在")
+	ErrFolderNameExists    = newError(20034, "收藏夹名称已存在")
+	ErrFolderLimit         = newError(20035, "收藏夹数量已达上限")
+	ErrArticleIsDraft      = newError(20036, "草稿请通过保存草稿和发布接口修改")
 )
@@ -0,0 +1,53 @@
+package v1
+
+type LikeArticleReq struct {
+	ArticleID uint `json:"articleId" binding:"required"` // 文章ID
+}
+
+type LikeArticleResponseData struct {
+	Liked bool  `json:"liked"` // 当前用户是否已点赞
+	Likes int64 `json:"likes"` // 点赞数
+}
+
+type FavoriteArticleReq struct {
+	ArticleID uint `json:"articleId" binding:"required"` // 文章ID
+	FolderId  uint `json:"folderId"`                     // 收藏夹ID，0 为默认收藏夹；已收藏时移动到该收藏夹
+}
+
+type UnfavoriteArticleReq struct {
+	ArticleID uint `json:"articleId" binding:"required"` // 文章ID
+}
+
+type FavoriteArticleResponseData struct {
+	Favorited bool  `json:"favorited"` // 当前用户是否已收藏
+	Favorites int64 `json:"favorites"` // 收藏数
+}
+
+type GetFavoriteListReq struct {
+	PageRequest
+	FolderId *uint `json:"folderId"` // 收藏夹ID，0 为默认收藏夹，不传时查询全部收藏
+}
+
+type CreateFavoriteFolderReq struct {
+	Name string `json:"name" binding:"required"` // 收藏夹名称
+}
+
+type CreateFavoriteFolderResponseData struct {
+	FolderId uint `json:"folderId"` // 收藏夹ID
+}
+
+type UpdateFavoriteFolderReq struct {
+	FolderId uint   `json:"folderId" binding:"required"` // 收藏夹ID
+	Name     string `json:"name" binding:"required"`     // 收藏夹名称
+}
+
+type DeleteFavoriteFolderReq struct {
+	FolderId uint `json:"folderId" binding:"required"` // 收藏夹ID，删除后其中的收藏移到默认收藏夹
+}
+
+type FavoriteFolderData struct {
+	FolderId  uint   `json:"folderId"`  // 收藏夹ID，0 为默认收藏夹
+	Name      string `json:"name"`      // 收藏夹名称
+	Count     int64  `json:"count"`     // 收藏数
+	CreatedAt string `json:"createdAt"` // 创建时间，默认收藏夹为空
+}
@@ -0,0 +1,21 @@
+package v1
+
+type NotificationData struct {
+	Id        uint   `json:"id"`
+	Type      int    `json:"type"`      // 通知类型
+	Title     string `json:"title"`     // 通知标题
+	Content   string `json:"content"`   // 通知内容
+	RelatedId uint   `json:"relatedId"` // 关联对象ID
+	IsRead    int    `json:"isRead"`    // 是否已读
+	CreatedAt string `json:"createdAt"`
+}
+
+type NotificationList struct {
+	NotificationList []*NotificationData `json:"notificationList"`
+	UnreadCount      int64               `json:"unreadCount"` // 未读数量
+	PageResponse
+}
+
+type ReadNotificationReq struct {
+	Ids []uint `json:"ids"` // 通知ID列表，为空时全部标记已读
+}
@@ -2,12 +2,14 @@ package v1
 
 // PageResponse 通用分页数据结构体
 type PageResponse struct {
-	TotalCount int64 `json:"totalCount"` // 总记录数
-	PageIndex  int   `json:"pageIndex"`  // 当前页码
-	PageSize   int   `json:"pageSize"`   // 每页大小
+	TotalCount int64  `json:"totalCount"`           // 总记录数
+	PageIndex  int    `json:"pageIndex"`            // 当前页码，游标分页时为 0
+	PageSize   int    `json:"pageSize"`             // 每页大小
+	NextCursor string `json:"nextCursor,omitempty"` // 下一页的游标，没有更多数据时为空
 }
 
 type PageRequest struct {
-	PageIndex int `form:"pageIndex" json:"pageIndex"` // 当前页码
-	PageSize  int `form:"pageSize" json:"pageSize"`   // 每页大小
+	PageIndex int    `form:"pageIndex" json:"pageIndex"` // 当前页码
+	PageSize  int    `form:"pageSize" json:"pageSize"`   // 每页大小
+	Cursor    string `form:"cursor" json:"cursor"`       // 上一页返回的游标，设置后忽略页码，按游标继续查询
 }
@@ -24,7 +24,13 @@ type PasswordLoginRequest struct {
 	CaptchaAnswer string `json:"captchaAnswer" binding:"required"` // 验证码字段
 }
 type LoginResponseData struct {
-	AccessToken string `json:"accessToken"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"` // 访问 token 有效期（秒）
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
 }
 
 type UpdateProfileRequest struct {
@@ -45,3 +51,34 @@ type UserAuthRequest struct {
 	StudentId string `json:"studentId"`
 	Remarks   string `json:"remarks"`
 }
+
+type GetUserAuthListReq struct {
+	Status int `json:"status"` // 认证状态，默认 0 待处理，-1 查询全部
+	PageRequest
+}
+
+type UserAuthData struct {
+	Id          uint   `json:"id"`
+	UserId      string `json:"userId"`
+	Nickname    string `json:"nickname"`
+	RequestType int    `json:"requestType"`
+	Status      int    `json:"status"`
+	CollegeId   uint   `json:"collegeId"`
+	StudentId   string `json:"studentId"`
+	Remarks     string `json:"remarks"`
+	ApplyTime   string `json:"applyTime"`
+	DisposeTime string `json:"disposeTime"`
+	AdminId     string `json:"adminId"`
+	AdminRemark string `json:"adminRemark"`
+}
+
+type UserAuthList struct {
+	UserAuthList []*UserAuthData `json:"userAuthList"`
+	PageResponse
+}
+
+type ReviewUserAuthReq struct {
+	Id       uint   `json:"id" binding:"required"` // 认证请求ID
+	Approved bool   `json:"approved"`              // 是否通过
+	Remark   string `json:"remark"`                // 审核意见
+}
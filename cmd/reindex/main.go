@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"projectName/cmd/reindex/wire"
+	"projectName/internal/server"
+	"projectName/pkg/config"
+	"projectName/pkg/log"
+)
+
+func main() {
+	var envConf = flag.String("conf", "config/local.yml", "config path, eg: -conf ./config/local.yml")
+	var mode = flag.String("mode", server.ReindexModeVerify, "reindex: rebuild the index and switch the alias; verify: report missing or stale documents")
+	var fix = flag.Bool("fix", false, "enqueue inconsistent articles for Elasticsearch sync in verify mode")
+	flag.Parse()
+	conf := config.NewConfig(*envConf)
+
+	logger := log.NewLog(conf)
+
+	app, cleanup, err := wire.NewWire(conf, logger, server.ReindexOptions{Mode: *mode, Fix: *fix})
+	defer cleanup()
+	if err != nil {
+		panic(err)
+	}
+	if err = app.Run(context.Background()); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build wireinject
+// +build wireinject
+
+package wire
+
+import (
+	"github.com/google/wire"
+	"github.com/spf13/viper"
+	"projectName/internal/repository"
+	"projectName/internal/server"
+	"projectName/pkg/app"
+	"projectName/pkg/log"
+)
+
+var repositorySet = wire.NewSet(
+	repository.NewDB,
+	repository.NewRedis,
+	repository.NewESClient,
+	repository.NewRepository,
+	repository.NewArticleRepository,
+	repository.NewEsOutboxRepository,
+)
+var serverSet = wire.NewSet(
+	server.NewReindexServer,
+)
+
+// build App
+func newApp(
+	reindexServer *server.ReindexServer,
+) *app.App {
+	return app.NewApp(
+		app.WithServer(reindexServer),
+		app.WithName("demo-reindex"),
+	)
+}
+
+func NewWire(*viper.Viper, *log.Logger, server.ReindexOptions) (*app.App, func(), error) {
+	panic(wire.Build(
+		repositorySet,
+		serverSet,
+		newApp,
+	))
+}
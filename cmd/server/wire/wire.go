@@ -18,6 +18,7 @@ import (
 	"projectName/pkg/log"
 	"projectName/pkg/server/http"
 	"projectName/pkg/sid"
+	"projectName/pkg/storage"
 	"time"
 )
 
@@ -36,6 +37,15 @@ var repositorySet = wire.NewSet(
 	repository.NewUserRepository,
 	repository.NewCollegeRepository,
 	repository.NewArticleRepository,
+	repository.NewNotificationRepository,
+	repository.NewArticleRevisionRepository,
+	repository.NewCommentRepository,
+	repository.NewTagRepository,
+	repository.NewAttachmentRepository,
+	repository.NewEsOutboxRepository,
+	repository.NewArticleStatRepository,
+	repository.NewArticleLikeRepository,
+	repository.NewFavoriteRepository,
 )
 
 // 提供 service 层的实例
@@ -45,7 +55,11 @@ var serviceSet = wire.NewSet(
 	ProvideCaptchaExpireDuration, // 提供 time.Duration 类型实例
 	user.NewCaptchaService,       // 使用 ProvideCaptchaExpireDuration 提供的 time.Duration 类型实例
 	user.NewCollegeService,
+	user.NewAdminService,
+	user.NewNotificationService,
 	article.NewArticleService,
+	article.NewCommentService,
+	article.NewAttachmentService,
 )
 
 // 提供 handler 层的实例
@@ -54,6 +68,10 @@ var handlerSet = wire.NewSet(
 	handler.NewUserHandler,
 	handler.NewCollegeHandler,
 	handler.NewArticleHandler,
+	handler.NewAdminHandler,
+	handler.NewNotificationHandler,
+	handler.NewCommentHandler,
+	handler.NewFileHandler,
 )
 
 // 提供 job 层的实例
@@ -89,6 +107,7 @@ func NewWire(*viper.Viper, *log.Logger) (*app.App, func(), error) {
 		serverSet,
 		sid.NewSid,
 		jwt.NewJwt,
+		storage.NewStorage,
 		newApp,
 	))
 }
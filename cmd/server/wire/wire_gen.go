@@ -21,6 +21,7 @@ import (
 	"projectName/pkg/log"
 	"projectName/pkg/server/http"
 	"projectName/pkg/sid"
+	"projectName/pkg/storage"
 	"time"
 )
 
@@ -28,7 +29,7 @@ import (
 
 // NewWire 是 Wire 的生成函数，用于构建 App 实例及其依赖
 func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), error) {
-	jwtJWT := jwt.NewJwt(viperViper)
+	jwtJWT := jwt.NewJwt(viperViper, logger)
 	handlerHandler := handler.NewHandler(logger)
 	duration := ProvideCaptchaExpireDuration()
 	captchaService := user.NewCaptchaService(duration)
@@ -45,10 +46,34 @@ func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), err
 	collegeRepository := repository.NewCollegeRepository(repositoryRepository)
 	collegeService := user.NewCollegeService(serviceService, collegeRepository)
 	collegeHandler := handler.NewCollegeHandler(handlerHandler, collegeService)
-	articleRepository := repository.NewArticleRepository(repositoryRepository)
-	articleService := article.NewArticleService(serviceService, articleRepository, userRepository)
+	articleRepository := repository.NewArticleRepository(repositoryRepository, viperViper)
+	notificationRepository := repository.NewNotificationRepository(repositoryRepository)
+	articleRevisionRepository := repository.NewArticleRevisionRepository(repositoryRepository)
+	commentRepository := repository.NewCommentRepository(repositoryRepository)
+	tagRepository := repository.NewTagRepository(repositoryRepository)
+	attachmentRepository := repository.NewAttachmentRepository(repositoryRepository)
+	esOutboxRepository := repository.NewEsOutboxRepository(repositoryRepository)
+	articleStatRepository := repository.NewArticleStatRepository(repositoryRepository)
+	articleLikeRepository := repository.NewArticleLikeRepository(repositoryRepository)
+	favoriteRepository := repository.NewFavoriteRepository(repositoryRepository)
+	articleService := article.NewArticleService(serviceService, viperViper, articleRepository, userRepository, notificationRepository, articleRevisionRepository, commentRepository, tagRepository, attachmentRepository, esOutboxRepository, articleStatRepository, articleLikeRepository, favoriteRepository)
 	articleHandler := handler.NewArticleHandler(handlerHandler, articleService)
-	httpServer := server.NewHTTPServer(logger, viperViper, jwtJWT, userHandler, collegeHandler, articleHandler)
+	adminService := user.NewAdminService(serviceService, userRepository, collegeRepository, userService)
+	adminHandler := handler.NewAdminHandler(handlerHandler, adminService)
+	notificationService := user.NewNotificationService(serviceService, notificationRepository)
+	notificationHandler := handler.NewNotificationHandler(handlerHandler, notificationService)
+	commentService := article.NewCommentService(serviceService, articleRepository, commentRepository, userRepository)
+	commentHandler := handler.NewCommentHandler(handlerHandler, commentService)
+	storageStorage, err := storage.NewStorage(viperViper)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachmentService, err := article.NewAttachmentService(serviceService, viperViper, storageStorage, attachmentRepository, articleService)
+	if err != nil {
+		return nil, nil, err
+	}
+	fileHandler := handler.NewFileHandler(handlerHandler, attachmentService)
+	httpServer := server.NewHTTPServer(logger, viperViper, jwtJWT, userRepository, userHandler, collegeHandler, articleHandler, adminHandler, notificationHandler, commentHandler, fileHandler)
 	jobJob := job.NewJob(transaction, logger, sidSid)
 	userJob := job.NewUserJob(jobJob, userRepository)
 	jobServer := server.NewJobServer(logger, userJob)
@@ -65,13 +90,13 @@ func ProvideCaptchaExpireDuration() time.Duration {
 }
 
 // 提供 repository 层的实例
-var repositorySet = wire.NewSet(repository.NewDB, repository.NewRedis, repository.NewESClient, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewCollegeRepository, repository.NewArticleRepository)
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRedis, repository.NewESClient, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewCollegeRepository, repository.NewArticleRepository, repository.NewNotificationRepository, repository.NewArticleRevisionRepository, repository.NewCommentRepository, repository.NewTagRepository, repository.NewAttachmentRepository, repository.NewEsOutboxRepository, repository.NewArticleStatRepository, repository.NewArticleLikeRepository, repository.NewFavoriteRepository)
 
 // 提供 service 层的实例
-var serviceSet = wire.NewSet(service.NewService, user.NewUserService, ProvideCaptchaExpireDuration, user.NewCaptchaService, user.NewCollegeService, article.NewArticleService)
+var serviceSet = wire.NewSet(service.NewService, user.NewUserService, ProvideCaptchaExpireDuration, user.NewCaptchaService, user.NewCollegeService, user.NewAdminService, user.NewNotificationService, article.NewArticleService, article.NewCommentService, article.NewAttachmentService)
 
 // 提供 handler 层的实例
-var handlerSet = wire.NewSet(handler.NewHandler, handler.NewUserHandler, handler.NewCollegeHandler, handler.NewArticleHandler)
+var handlerSet = wire.NewSet(handler.NewHandler, handler.NewUserHandler, handler.NewCollegeHandler, handler.NewArticleHandler, handler.NewAdminHandler, handler.NewNotificationHandler, handler.NewCommentHandler, handler.NewFileHandler)
 
 // 提供 job 层的实例
 var jobSet = wire.NewSet(job.NewJob, job.NewUserJob)
@@ -12,19 +12,28 @@ import (
 	"projectName/pkg/app"
 	"projectName/pkg/log"
 	"projectName/pkg/sid"
+	"projectName/pkg/storage"
 )
 
 var repositorySet = wire.NewSet(
 	repository.NewDB,
-	//repository.NewRedis,
+	repository.NewRedis,
+	repository.NewESClient,
 	repository.NewRepository,
 	repository.NewTransaction,
 	repository.NewUserRepository,
+	repository.NewArticleRepository,
+	repository.NewAttachmentRepository,
+	repository.NewEsOutboxRepository,
+	repository.NewArticleStatRepository,
 )
 
 var taskSet = wire.NewSet(
 	task.NewTask,
 	task.NewUserTask,
+	task.NewArticleTask,
+	task.NewAttachmentTask,
+	task.NewEsSyncTask,
 )
 var serverSet = wire.NewSet(
 	server.NewTaskServer,
@@ -47,5 +56,6 @@ func NewWire(*viper.Viper, *log.Logger) (*app.App, func(), error) {
 		serverSet,
 		newApp,
 		sid.NewSid,
+		storage.NewStorage,
 	))
 }
@@ -15,19 +15,33 @@ import (
 	"projectName/pkg/app"
 	"projectName/pkg/log"
 	"projectName/pkg/sid"
+	"projectName/pkg/storage"
 )
 
 // Injectors from wire.go:
 
 func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), error) {
 	db := repository.NewDB(viperViper, logger)
-	repositoryRepository := repository.NewRepository(logger, db)
+	client := repository.NewRedis(viperViper)
+	elasticClient := repository.NewESClient(viperViper)
+	repositoryRepository := repository.NewRepository(logger, db, client, elasticClient)
 	transaction := repository.NewTransaction(repositoryRepository)
 	sidSid := sid.NewSid()
 	taskTask := task.NewTask(transaction, logger, sidSid)
 	userRepository := repository.NewUserRepository(repositoryRepository)
 	userTask := task.NewUserTask(taskTask, userRepository)
-	taskServer := server.NewTaskServer(logger, userTask)
+	articleRepository := repository.NewArticleRepository(repositoryRepository, viperViper)
+	esOutboxRepository := repository.NewEsOutboxRepository(repositoryRepository)
+	articleStatRepository := repository.NewArticleStatRepository(repositoryRepository)
+	articleTask := task.NewArticleTask(taskTask, articleRepository, esOutboxRepository, articleStatRepository)
+	storageStorage, err := storage.NewStorage(viperViper)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachmentRepository := repository.NewAttachmentRepository(repositoryRepository)
+	attachmentTask := task.NewAttachmentTask(taskTask, storageStorage, attachmentRepository, esOutboxRepository)
+	esSyncTask := task.NewEsSyncTask(taskTask, articleRepository, esOutboxRepository)
+	taskServer := server.NewTaskServer(logger, userTask, articleTask, attachmentTask, esSyncTask)
 	appApp := newApp(taskServer)
 	return appApp, func() {
 	}, nil
@@ -35,9 +49,9 @@ func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), err
 
 // wire.go:
 
-var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository)
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRedis, repository.NewESClient, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewArticleRepository, repository.NewAttachmentRepository, repository.NewEsOutboxRepository, repository.NewArticleStatRepository)
 
-var taskSet = wire.NewSet(task.NewTask, task.NewUserTask)
+var taskSet = wire.NewSet(task.NewTask, task.NewUserTask, task.NewArticleTask, task.NewAttachmentTask, task.NewEsSyncTask)
 
 var serverSet = wire.NewSet(server.NewTaskServer)
 
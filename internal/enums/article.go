@@ -9,3 +9,24 @@ const (
 	StatusRejected      = 4 // 已驳回
 	StatusScheduled     = 5 // 已计划 设置了定时发布的时间
 )
+
+// 文章可见范围
+const (
+	VisiblePublic  = "public"  // 所有人可见
+	VisibleLogin   = "login"   // 登录用户可见
+	VisibleCollege = "college" // 与作者同学院的用户可见
+	VisibleRole    = "role"    // 不低于指定角色的用户可见
+	VisiblePrivate = "private" // 仅作者可见，可通过共享列表额外授权
+)
+
+// 文章共享对象类型
+const (
+	ShareTargetUser    = "user"    // 共享给用户
+	ShareTargetCollege = "college" // 共享给学院
+)
+
+// 热门文章的统计周期
+const (
+	HotPeriodDaily  = "daily"  // 当天
+	HotPeriodWeekly = "weekly" // 最近 7 天（含当天）
+)
@@ -0,0 +1,9 @@
+package enums
+
+// 附件文本提取状态
+const (
+	ExtractPending     = 0 // 待提取
+	ExtractDone        = 1 // 已提取
+	ExtractFailed      = 2 // 提取失败，未超过重试次数时会再次尝试
+	ExtractUnsupported = 3 // 文件类型不支持提取
+)
@@ -0,0 +1,7 @@
+package enums
+
+// 通知类型
+const (
+	NotifyArticleApproved = 1 // 文章审核通过
+	NotifyArticleRejected = 2 // 文章审核驳回
+)
@@ -1,5 +1,6 @@
 package enums
 
 const (
-	LOGIN_TOKEN_KEY = "loginToken:"
+	LOGIN_TOKEN_KEY   = "loginToken:"
+	REFRESH_TOKEN_KEY = "refreshToken:"
 )
@@ -3,4 +3,7 @@ package enums
 const (
 	USER    = "/user"
 	ARTICLE = "/article"
+	ADMIN   = "/admin"
+	COMMENT = "/comment"
+	FILE    = "/file"
 )
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	v1 "projectName/api/v1"
+	"projectName/internal/service/user"
+)
+
+type AdminHandler struct {
+	*Handler
+	adminService user.AdminService
+}
+
+func NewAdminHandler(
+	handler *Handler,
+	adminService user.AdminService,
+) *AdminHandler {
+	return &AdminHandler{
+		Handler:      handler,
+		adminService: adminService,
+	}
+}
+
+// GetUserList godoc
+// @Summary 查询用户列表
+// @Schemes
+// @Description
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.GetUserListReq true "params"
+// @Success 200 {object} v1.UserManageList
+// @Router /admin/getUserList [post]
+func (h *AdminHandler) GetUserList(ctx *gin.Context) {
+	var req v1.GetUserListReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userList, err := h.adminService.GetUserList(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, userList)
+}
+
+// UpdateUserRole godoc
+// @Summary 修改用户角色
+// @Schemes
+// @Description 任命学校管理员时必须绑定学院
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.UpdateUserRoleReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /admin/updateUserRole [post]
+func (h *AdminHandler) UpdateUserRole(ctx *gin.Context) {
+	var req v1.UpdateUserRoleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.adminService.UpdateUserRole(ctx, GetUserIdFromCtx(ctx), &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// SetUserDisabled godoc
+// @Summary 禁用/启用用户
+// @Schemes
+// @Description 禁用后用户所有设备强制下线
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.SetUserDisabledReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /admin/setUserDisabled [post]
+func (h *AdminHandler) SetUserDisabled(ctx *gin.Context) {
+	var req v1.SetUserDisabledReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.adminService.SetUserDisabled(ctx, GetUserIdFromCtx(ctx), &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ForceLogout godoc
+// @Summary 强制用户下线
+// @Schemes
+// @Description
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.UserIdReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /admin/forceLogout [post]
+func (h *AdminHandler) ForceLogout(ctx *gin.Context) {
+	var req v1.UserIdReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.adminService.ForceLogout(ctx, req.UserId); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// RestoreUser godoc
+// @Summary 恢复已注销用户
+// @Schemes
+// @Description
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.UserIdReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /admin/restoreUser [post]
+func (h *AdminHandler) RestoreUser(ctx *gin.Context) {
+	var req v1.UserIdReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.adminService.RestoreUser(ctx, req.UserId); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
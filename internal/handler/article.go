@@ -41,6 +41,7 @@ func (h *ArticleHandler) CreateArticle(ctx *gin.Context) {
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
 	}
+	req.AuthorID = GetUserIdFromCtx(ctx)
 	articleId, err := h.articleService.CreateArticle(ctx, &req)
 	if articleId == -1 || err != nil {
 		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
@@ -52,6 +53,60 @@ func (h *ArticleHandler) CreateArticle(ctx *gin.Context) {
 
 }
 
+// SaveDraft godoc
+// @Summary 保存草稿
+// @Schemes
+// @Description articleId 为 0 时新建草稿，否则更新草稿
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.SaveDraftRequest true "params"
+// @Success 200 {object} v1.CreateArticleResponseData
+// @Router /article/saveDraft [post]
+func (h *ArticleHandler) SaveDraft(ctx *gin.Context) {
+	var req v1.SaveDraftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId := GetUserIdFromCtx(ctx)
+	articleId, err := h.articleService.SaveDraft(ctx, userId, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, v1.CreateArticleResponseData{
+		ArticleID: int(articleId),
+	})
+}
+
+// PublishArticle godoc
+// @Summary 发布草稿
+// @Schemes
+// @Description
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.PublishArticleRequest true "params"
+// @Success 200 {object} v1.PublishArticleResponseData
+// @Router /article/publishArticle [post]
+func (h *ArticleHandler) PublishArticle(ctx *gin.Context) {
+	var req v1.PublishArticleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId := GetUserIdFromCtx(ctx)
+	data, err := h.articleService.PublishArticle(ctx, userId, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
 // GetArticleCategory godoc
 // @Summary 获取文章分组
 // @Schemes
@@ -73,6 +128,81 @@ func (h *ArticleHandler) GetArticleCategory(ctx *gin.Context) {
 	})
 }
 
+// CreateCategory godoc
+// @Summary 新建文章分类
+// @Schemes
+// @Description
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateCategoryReq true "params"
+// @Success 200 {object} v1.CreateCategoryResponseData
+// @Router /article/createCategory [post]
+func (h *ArticleHandler) CreateCategory(ctx *gin.Context) {
+	var req v1.CreateCategoryReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	categoryId, err := h.articleService.CreateCategory(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, v1.CreateCategoryResponseData{
+		CategoryId: categoryId,
+	})
+}
+
+// UpdateCategory godoc
+// @Summary 修改文章分类
+// @Schemes
+// @Description 重命名或移动分类
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.UpdateCategoryReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /article/updateCategory [post]
+func (h *ArticleHandler) UpdateCategory(ctx *gin.Context) {
+	var req v1.UpdateCategoryReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.articleService.UpdateCategory(ctx, &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// DeleteCategory godoc
+// @Summary 删除文章分类
+// @Schemes
+// @Description 分类下仍有文章时需指定 moveToCategoryId
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.DeleteCategoryReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /article/deleteCategory [post]
+func (h *ArticleHandler) DeleteCategory(ctx *gin.Context) {
+	var req v1.DeleteCategoryReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.articleService.DeleteCategory(ctx, &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
 // GetArticle godoc
 // @Summary 获取文章详细
 // @Schemes
@@ -107,7 +237,7 @@ func (h *ArticleHandler) GetArticle(ctx *gin.Context) {
 // UpdateArticle godoc
 // @Summary 修改文章内容
 // @Schemes
-// @Description
+// @Description 修改已提交的文章，草稿请使用 /article/saveDraft 和 /article/publishArticle
 // @Tags 文章模块
 // @Accept json
 // @Produce json
@@ -121,18 +251,13 @@ func (h *ArticleHandler) UpdateArticle(ctx *gin.Context) {
 		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
 		return
 	}
-	userId, role := GetUserIdAndRoleTypeFromCtx(ctx)
-	if req.AuthorID == userId || role == enums.SUPER_ADMIN {
-		articleData, err := h.articleService.UpdateArticle(ctx, &req)
-		if err != nil {
-			v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
-			return
-		}
-		v1.HandleSuccess(ctx, articleData)
-	} else {
-		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+	userId, roleType := GetUserIdAndRoleTypeFromCtx(ctx)
+	articleData, err := h.articleService.UpdateArticle(ctx, userId, roleType, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
 		return
 	}
+	v1.HandleSuccess(ctx, articleData)
 }
 
 // DeleteArticleList godoc
@@ -231,7 +356,8 @@ func (h *ArticleHandler) GetArticleListByCategory(ctx *gin.Context) {
 			PageSize:  pageSize,
 		},
 	}
-	articleList, err := h.articleService.GetArticleListByCategory(ctx, &req)
+	userId := GetUserIdFromCtx(ctx)
+	articleList, err := h.articleService.GetArticleListByCategory(ctx, userId, &req)
 	if err != nil {
 		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
 		return
@@ -292,9 +418,474 @@ func (h *ArticleHandler) GetArticleListByEs(ctx *gin.Context) {
 		req.Order = "desc"
 	}
 
-	articleList, err := h.articleService.GetArticleListByEs(ctx, &req)
+	userId := GetUserIdFromCtx(ctx)
+	articleList, err := h.articleService.GetArticleListByEs(ctx, userId, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, articleList)
+}
+
+// GetReviewArticleList godoc
+// @Summary 获取待审核文章列表
+// @Schemes
+// @Description 学校管理员查看本学院作者的待审核文章
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.PageRequest true "params"
+// @Success 200 {object} v1.ArticleList
+// @Router /admin/getReviewArticleList [post]
+func (h *ArticleHandler) GetReviewArticleList(ctx *gin.Context) {
+	var req v1.PageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId, role := GetUserIdAndRoleTypeFromCtx(ctx)
+	articleList, err := h.articleService.GetReviewArticleList(ctx, userId, role, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, articleList)
+}
+
+// ReviewArticle godoc
+// @Summary 审核文章
+// @Schemes
+// @Description 驳回时必须填写原因，审核结果会通知作者
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.ReviewArticleReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /admin/reviewArticle [post]
+func (h *ArticleHandler) ReviewArticle(ctx *gin.Context) {
+	var req v1.ReviewArticleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId, role := GetUserIdAndRoleTypeFromCtx(ctx)
+	if err := h.articleService.ReviewArticle(ctx, userId, role, &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// GetArticleRevisionList godoc
+// @Summary 获取文章历史版本列表
+// @Schemes
+// @Description 仅作者本人和超级管理员可查看
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.GetArticleRevisionListReq true "params"
+// @Success 200 {object} v1.ArticleRevisionList
+// @Router /article/getArticleRevisionList [post]
+func (h *ArticleHandler) GetArticleRevisionList(ctx *gin.Context) {
+	var req v1.GetArticleRevisionListReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId, role := GetUserIdAndRoleTypeFromCtx(ctx)
+	revisionList, err := h.articleService.GetArticleRevisionList(ctx, userId, role, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, revisionList)
+}
+
+// GetArticleRevisionDiff godoc
+// @Summary 比较文章两个版本的差异
+// @Schemes
+// @Description 版本号为 0 表示文章当前内容
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.GetArticleRevisionDiffReq true "params"
+// @Success 200 {object} v1.ArticleRevisionDiffData
+// @Router /article/getArticleRevisionDiff [post]
+func (h *ArticleHandler) GetArticleRevisionDiff(ctx *gin.Context) {
+	var req v1.GetArticleRevisionDiffReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId, role := GetUserIdAndRoleTypeFromCtx(ctx)
+	diffData, err := h.articleService.GetArticleRevisionDiff(ctx, userId, role, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, diffData)
+}
+
+// RollbackArticle godoc
+// @Summary 回滚文章到历史版本
+// @Schemes
+// @Description
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.RollbackArticleReq true "params"
+// @Success 200 {object} v1.ArticleData
+// @Router /article/rollbackArticle [post]
+func (h *ArticleHandler) RollbackArticle(ctx *gin.Context) {
+	var req v1.RollbackArticleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId, role := GetUserIdAndRoleTypeFromCtx(ctx)
+	articleData, err := h.articleService.RollbackArticle(ctx, userId, role, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, articleData)
+}
+
+// GetTagSuggest godoc
+// @Summary 标签联想
+// @Schemes
+// @Description 按前缀匹配标签，常用标签在前
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param keyword query string true "标签前缀"
+// @Success 200 {object} []v1.TagData
+// @Router /article/getTagSuggest [get]
+func (h *ArticleHandler) GetTagSuggest(ctx *gin.Context) {
+	tagList, err := h.articleService.GetTagSuggest(ctx, ctx.Query("keyword"))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, tagList)
+}
+
+// GetSearchSuggest godoc
+// @Summary 搜索联想
+// @Schemes
+// @Description 按输入的关键字联想可见文章的标题，并给出拼写纠错建议
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param keyword query string true "搜索关键字"
+// @Success 200 {object} v1.SearchSuggestData
+// @Router /article/getSearchSuggest [get]
+func (h *ArticleHandler) GetSearchSuggest(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	data, err := h.articleService.GetSearchSuggest(ctx, userId, ctx.Query("keyword"))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetHotArticleList godoc
+// @Summary 热门文章
+// @Schemes
+// @Description 按浏览量获取当天或最近 7 天的热门文章，只返回当前用户可见的文章
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param period query string false "统计周期：daily 当天，weekly 最近 7 天，默认 daily"
+// @Param size query int false "返回数量，默认 10，最多 50"
+// @Success 200 {object} []v1.ArticleData
+// @Router /article/getHotArticleList [get]
+func (h *ArticleHandler) GetHotArticleList(ctx *gin.Context) {
+	size := 0
+	if ctx.Query("size") != "" {
+		if !utils.IsNumeric(ctx.Query("size")) {
+			v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+			return
+		}
+		size, _ = utils.ToInt(ctx.Query("size"))
+	}
+	userId := GetUserIdFromCtx(ctx)
+	articleList, err := h.articleService.GetHotArticleList(ctx, userId, ctx.Query("period"), size)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, articleList)
+}
+
+// GetArticleListByTag godoc
+// @Summary 按标签获取文章列表
+// @Schemes
+// @Description
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.GetArticleListByTagReq true "params"
+// @Success 200 {object} v1.ArticleList
+// @Router /article/getArticleListByTag [post]
+func (h *ArticleHandler) GetArticleListByTag(ctx *gin.Context) {
+	var req v1.GetArticleListByTagReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	articleList, err := h.articleService.GetArticleListByTag(ctx, GetUserIdFromCtx(ctx), &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, articleList)
+}
+
+// GetEsSyncStatus godoc
+// @Summary 查询es同步状态
+// @Schemes
+// @Description 返回待同步到es的任务数量和同步延迟
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.EsSyncStatusData
+// @Router /admin/getEsSyncStatus [get]
+func (h *ArticleHandler) GetEsSyncStatus(ctx *gin.Context) {
+	data, err := h.articleService.GetEsSyncStatus(ctx)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// LikeArticle godoc
+// @Summary 点赞文章
+// @Schemes
+// @Description 重复点赞不会重复计数
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.LikeArticleReq true "params"
+// @Success 200 {object} v1.LikeArticleResponseData
+// @Router /article/likeArticle [post]
+func (h *ArticleHandler) LikeArticle(ctx *gin.Context) {
+	var req v1.LikeArticleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	data, err := h.articleService.LikeArticle(ctx, GetUserIdFromCtx(ctx), req.ArticleID)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// UnlikeArticle godoc
+// @Summary 取消点赞
+// @Schemes
+// @Description
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.LikeArticleReq true "params"
+// @Success 200 {object} v1.LikeArticleResponseData
+// @Router /article/unlikeArticle [post]
+func (h *ArticleHandler) UnlikeArticle(ctx *gin.Context) {
+	var req v1.LikeArticleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	data, err := h.articleService.UnlikeArticle(ctx, GetUserIdFromCtx(ctx), req.ArticleID)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// FavoriteArticle godoc
+// @Summary 收藏文章
+// @Schemes
+// @Description 收藏到指定的收藏夹，folderId 为 0 时收藏到默认收藏夹；已收藏时移动到该收藏夹
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.FavoriteArticleReq true "params"
+// @Success 200 {object} v1.FavoriteArticleResponseData
+// @Router /article/favoriteArticle [post]
+func (h *ArticleHandler) FavoriteArticle(ctx *gin.Context) {
+	var req v1.FavoriteArticleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	data, err := h.articleService.FavoriteArticle(ctx, GetUserIdFromCtx(ctx), &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// UnfavoriteArticle godoc
+// @Summary 取消收藏
+// @Schemes
+// @Description
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.UnfavoriteArticleReq true "params"
+// @Success 200 {object} v1.FavoriteArticleResponseData
+// @Router /article/unfavoriteArticle [post]
+func (h *ArticleHandler) UnfavoriteArticle(ctx *gin.Context) {
+	var req v1.UnfavoriteArticleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	data, err := h.articleService.UnfavoriteArticle(ctx, GetUserIdFromCtx(ctx), &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetFavoriteList godoc
+// @Summary 我的收藏
+// @Schemes
+// @Description 按收藏时间倒序分页，folderId 不传时查询全部收藏
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.GetFavoriteListReq true "params"
+// @Success 200 {object} v1.ArticleList
+// @Router /article/getFavoriteList [post]
+func (h *ArticleHandler) GetFavoriteList(ctx *gin.Context) {
+	var req v1.GetFavoriteListReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	articleList, err := h.articleService.GetFavoriteList(ctx, GetUserIdFromCtx(ctx), &req)
 	if err != nil {
 		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
 	}
 	v1.HandleSuccess(ctx, articleList)
 }
+
+// CreateFavoriteFolder godoc
+// @Summary 新建收藏夹
+// @Schemes
+// @Description
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateFavoriteFolderReq true "params"
+// @Success 200 {object} v1.CreateFavoriteFolderResponseData
+// @Router /article/createFavoriteFolder [post]
+func (h *ArticleHandler) CreateFavoriteFolder(ctx *gin.Context) {
+	var req v1.CreateFavoriteFolderReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	folderId, err := h.articleService.CreateFavoriteFolder(ctx, GetUserIdFromCtx(ctx), &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, v1.CreateFavoriteFolderResponseData{
+		FolderId: folderId,
+	})
+}
+
+// UpdateFavoriteFolder godoc
+// @Summary 重命名收藏夹
+// @Schemes
+// @Description
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.UpdateFavoriteFolderReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /article/updateFavoriteFolder [post]
+func (h *ArticleHandler) UpdateFavoriteFolder(ctx *gin.Context) {
+	var req v1.UpdateFavoriteFolderReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.articleService.UpdateFavoriteFolder(ctx, GetUserIdFromCtx(ctx), &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// DeleteFavoriteFolder godoc
+// @Summary 删除收藏夹
+// @Schemes
+// @Description 收藏夹中的收藏移到默认收藏夹
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.DeleteFavoriteFolderReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /article/deleteFavoriteFolder [post]
+func (h *ArticleHandler) DeleteFavoriteFolder(ctx *gin.Context) {
+	var req v1.DeleteFavoriteFolderReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.articleService.DeleteFavoriteFolder(ctx, GetUserIdFromCtx(ctx), &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// GetFavoriteFolderList godoc
+// @Summary 收藏夹列表
+// @Schemes
+// @Description 默认收藏夹（folderId 为 0）排在最前
+// @Tags 文章模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} []v1.FavoriteFolderData
+// @Router /article/getFavoriteFolderList [get]
+func (h *ArticleHandler) GetFavoriteFolderList(ctx *gin.Context) {
+	folderList, err := h.articleService.GetFavoriteFolderList(ctx, GetUserIdFromCtx(ctx))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, folderList)
+}
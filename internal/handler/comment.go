@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	v1 "projectName/api/v1"
+	"projectName/internal/service/article"
+)
+
+type CommentHandler struct {
+	*Handler
+	commentService article.CommentService
+}
+
+func NewCommentHandler(
+	handler *Handler,
+	commentService article.CommentService,
+) *CommentHandler {
+	return &CommentHandler{
+		Handler:        handler,
+		commentService: commentService,
+	}
+}
+
+// CreateComment godoc
+// @Summary 发表评论
+// @Schemes
+// @Description parentId 不为 0 时回复指定评论
+// @Tags 评论模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateCommentReq true "params"
+// @Success 200 {object} v1.CreateCommentResponseData
+// @Router /comment/createComment [post]
+func (h *CommentHandler) CreateComment(ctx *gin.Context) {
+	var req v1.CreateCommentReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	commentId, err := h.commentService.CreateComment(ctx, GetUserIdFromCtx(ctx), &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, v1.CreateCommentResponseData{
+		CommentId: commentId,
+	})
+}
+
+// GetCommentList godoc
+// @Summary 获取文章评论列表
+// @Schemes
+// @Description 按顶层评论分页，每条顶层评论带出全部回复
+// @Tags 评论模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.GetCommentListReq true "params"
+// @Success 200 {object} v1.CommentList
+// @Router /comment/getCommentList [post]
+func (h *CommentHandler) GetCommentList(ctx *gin.Context) {
+	var req v1.GetCommentListReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	commentList, err := h.commentService.GetCommentList(ctx, GetUserIdFromCtx(ctx), &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, commentList)
+}
+
+// DeleteComment godoc
+// @Summary 删除评论
+// @Schemes
+// @Description 评论人、文章作者和管理员可以删除，删除顶层评论时同时删除回复
+// @Tags 评论模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.DeleteCommentReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /comment/deleteComment [post]
+func (h *CommentHandler) DeleteComment(ctx *gin.Context) {
+	var req v1.DeleteCommentReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId, role := GetUserIdAndRoleTypeFromCtx(ctx)
+	if err := h.commentService.DeleteComment(ctx, userId, role, &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
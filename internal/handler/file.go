@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	v1 "projectName/api/v1"
+	"projectName/internal/service/article"
+	"projectName/pkg/utils"
+	"strconv"
+)
+
+type FileHandler struct {
+	*Handler
+	attachmentService article.AttachmentService
+}
+
+func NewFileHandler(
+	handler *Handler,
+	attachmentService article.AttachmentService,
+) *FileHandler {
+	return &FileHandler{
+		Handler:           handler,
+		attachmentService: attachmentService,
+	}
+}
+
+// UploadFile godoc
+// @Summary 上传附件
+// @Schemes
+// @Description 上传后通过新建或修改文章的 uploadedFiles.attachmentId 关联到文章
+// @Tags 文件模块
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "附件"
+// @Success 200 {object} v1.UploadFileResponseData
+// @Router /file/upload [post]
+func (h *FileHandler) UploadFile(ctx *gin.Context) {
+	// 限制请求体大小，额外预留 1MB 给表单其他字段
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.attachmentService.MaxFileSize()+1<<20)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	data, err := h.attachmentService.UploadFile(ctx, GetUserIdFromCtx(ctx), fileHeader)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetDownloadUrl godoc
+// @Summary 获取附件下载地址
+// @Schemes
+// @Description 校验文章可见范围后返回限时有效的签名下载地址
+// @Tags 文件模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id query int true "附件ID"
+// @Success 200 {object} v1.GetDownloadUrlResponseData
+// @Router /file/getDownloadUrl [get]
+func (h *FileHandler) GetDownloadUrl(ctx *gin.Context) {
+	if !utils.IsNumeric(ctx.Query("id")) {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	attachmentId, _ := utils.ToInt(ctx.Query("id"))
+	data, err := h.attachmentService.GetDownloadUrl(ctx, GetUserIdFromCtx(ctx), uint(attachmentId))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// Download godoc
+// @Summary 下载附件
+// @Schemes
+// @Description 使用 getDownloadUrl 返回的签名地址下载，无需登录
+// @Tags 文件模块
+// @Produce octet-stream
+// @Param id query int true "附件ID"
+// @Param expires query int true "过期时间戳"
+// @Param sign query string true "签名"
+// @Router /file/download [get]
+func (h *FileHandler) Download(ctx *gin.Context) {
+	attachmentId, err := strconv.ParseUint(ctx.Query("id"), 10, 64)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	expires, err := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	attachment, reader, err := h.attachmentService.OpenDownload(ctx, uint(attachmentId), expires, ctx.Query("sign"))
+	if err != nil {
+		v1.HandleError(ctx, http.StatusForbidden, err, nil)
+		return
+	}
+	defer reader.Close()
+	ctx.DataFromReader(http.StatusOK, attachment.Size, attachment.MimeType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(attachment.FileName)),
+	})
+}
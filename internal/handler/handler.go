@@ -40,3 +40,11 @@ func GetUserIdAndRoleTypeFromCtx(ctx *gin.Context) (string, int) {
 	}
 	return v.(*jwt.MyCustomClaims).UserId, v.(*jwt.MyCustomClaims).RoleType
 }
+
+func GetTokenIdFromCtx(ctx *gin.Context) string {
+	v, exists := ctx.Get("claims")
+	if !exists {
+		return ""
+	}
+	return v.(*jwt.MyCustomClaims).ID
+}
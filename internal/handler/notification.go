@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	v1 "projectName/api/v1"
+	"projectName/internal/service/user"
+)
+
+type NotificationHandler struct {
+	*Handler
+	notificationService user.NotificationService
+}
+
+func NewNotificationHandler(
+	handler *Handler,
+	notificationService user.NotificationService,
+) *NotificationHandler {
+	return &NotificationHandler{
+		Handler:             handler,
+		notificationService: notificationService,
+	}
+}
+
+// GetNotificationList godoc
+// @Summary 获取通知列表
+// @Schemes
+// @Description
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.PageRequest true "params"
+// @Success 200 {object} v1.NotificationList
+// @Router /user/getNotificationList [post]
+func (h *NotificationHandler) GetNotificationList(ctx *gin.Context) {
+	var req v1.PageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	notificationList, err := h.notificationService.GetNotificationList(ctx, GetUserIdFromCtx(ctx), &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, notificationList)
+}
+
+// ReadNotification godoc
+// @Summary 标记通知已读
+// @Schemes
+// @Description ids 为空时全部标记已读
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.ReadNotificationReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /user/readNotification [post]
+func (h *NotificationHandler) ReadNotification(ctx *gin.Context) {
+	var req v1.ReadNotificationReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	if err := h.notificationService.ReadNotification(ctx, GetUserIdFromCtx(ctx), &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
@@ -85,14 +85,37 @@ func (h *UserHandler) PasswordLogin(ctx *gin.Context) {
 		return
 	}
 
-	token, err := h.userService.PasswordLogin(ctx, &req)
+	loginData, err := h.userService.PasswordLogin(ctx, &req)
 	if err != nil {
 		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
 		return
 	}
-	v1.HandleSuccess(ctx, v1.LoginResponseData{
-		AccessToken: token,
-	})
+	v1.HandleSuccess(ctx, loginData)
+}
+
+// RefreshToken godoc
+// @Summary 刷新token
+// @Schemes
+// @Description 使用刷新token换取新的访问token，刷新token每次使用后轮换
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body v1.RefreshTokenRequest true "params"
+// @Success 200 {object} v1.LoginResponseData
+// @Router /refreshToken [post]
+func (h *UserHandler) RefreshToken(ctx *gin.Context) {
+	var req v1.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	loginData, err := h.userService.RefreshToken(ctx, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, loginData)
 }
 
 // GetUserInfo godoc
@@ -169,7 +192,26 @@ func (h *UserHandler) UpdateProfile(ctx *gin.Context) {
 // @Router /user/logout [get]
 func (h *UserHandler) Logout(ctx *gin.Context) {
 	userId, roleTpye := GetUserIdAndRoleTypeFromCtx(ctx)
-	if err := h.userService.Logout(ctx, userId, roleTpye); err != nil {
+	if err := h.userService.Logout(ctx, userId, roleTpye, GetTokenIdFromCtx(ctx)); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// LogoutAll godoc
+// @Summary 退出所有设备
+// @Schemes
+// @Description
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.Response
+// @Router /user/logoutAll [get]
+func (h *UserHandler) LogoutAll(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if err := h.userService.LogoutAll(ctx, userId); err != nil {
 		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
 		return
 	}
@@ -187,9 +229,9 @@ func (h *UserHandler) Logout(ctx *gin.Context) {
 // @Success 200 {object} v1.Response
 // @Router /cancel [get]
 func (h *UserHandler) Cancel(ctx *gin.Context) {
-	userId, roleTpye := GetUserIdAndRoleTypeFromCtx(ctx)
-	// 退出
-	if err := h.userService.Logout(ctx, userId, roleTpye); err != nil {
+	userId := GetUserIdFromCtx(ctx)
+	// 退出所有设备
+	if err := h.userService.LogoutAll(ctx, userId); err != nil {
 		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
 		return
 	}
@@ -225,3 +267,54 @@ func (h *UserHandler) UserAuth(ctx *gin.Context) {
 	}
 	v1.HandleSuccess(ctx, nil)
 }
+
+// GetUserAuthList godoc
+// @Summary 获取认证请求列表
+// @Schemes
+// @Description 学校管理员查看本学院的认证请求，超级管理员查看全部
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.GetUserAuthListReq true "params"
+// @Success 200 {object} v1.UserAuthList
+// @Router /admin/getUserAuthList [post]
+func (h *UserHandler) GetUserAuthList(ctx *gin.Context) {
+	var req v1.GetUserAuthListReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId, roleType := GetUserIdAndRoleTypeFromCtx(ctx)
+	userAuthList, err := h.userService.GetUserAuthList(ctx, userId, roleType, &req)
+	if err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, userAuthList)
+}
+
+// ReviewUserAuth godoc
+// @Summary 审核认证请求
+// @Schemes
+// @Description 通过后用户升级为学生用户，需重新获取token
+// @Tags 管理模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.ReviewUserAuthReq true "params"
+// @Success 200 {object} v1.Response
+// @Router /admin/reviewUserAuth [post]
+func (h *UserHandler) ReviewUserAuth(ctx *gin.Context) {
+	var req v1.ReviewUserAuthReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+	userId, roleType := GetUserIdAndRoleTypeFromCtx(ctx)
+	if err := h.userService.ReviewUserAuth(ctx, userId, roleType, &req); err != nil {
+		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
@@ -5,11 +5,13 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"projectName/api/v1"
+	"projectName/internal/repository"
 	"projectName/pkg/jwt"
 	"projectName/pkg/log"
+	"strings"
 )
 
-func StrictAuth(j *jwt.JWT, logger *log.Logger, requiredRole int) gin.HandlerFunc {
+func StrictAuth(j *jwt.JWT, userRepo repository.UserRepository, logger *log.Logger, requiredRole int) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		tokenString := ctx.Request.Header.Get("X-Token")
 		if tokenString == "" {
@@ -34,6 +36,17 @@ func StrictAuth(j *jwt.JWT, logger *log.Logger, requiredRole int) gin.HandlerFun
 			return
 		}
 
+		// 校验 token 是否仍在 redis 中（退出、注销后会被删除）
+		if !checkLoginToken(ctx, userRepo, claims, tokenString) {
+			logger.WithContext(ctx).Warn("token revoked", zap.Any("data", map[string]interface{}{
+				"url":    ctx.Request.URL,
+				"userId": claims.UserId,
+			}))
+			v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrTokenRevoked, nil)
+			ctx.Abort()
+			return
+		}
+
 		// 根据 RoleType 校验权限
 		if claims.RoleType < requiredRole {
 			v1.HandleError(ctx, http.StatusForbidden, v1.ErrPermissionDenied, nil)
@@ -48,7 +61,7 @@ func StrictAuth(j *jwt.JWT, logger *log.Logger, requiredRole int) gin.HandlerFun
 	}
 }
 
-func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
+func NoStrictAuth(j *jwt.JWT, userRepo repository.UserRepository, logger *log.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		tokenString := ctx.Request.Header.Get("Authorization")
 		if tokenString == "" {
@@ -67,6 +80,11 @@ func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 			ctx.Next()
 			return
 		}
+		// 已失效的 token 按未登录处理
+		if !checkLoginToken(ctx, userRepo, claims, tokenString) {
+			ctx.Next()
+			return
+		}
 
 		ctx.Set("claims", claims)
 		recoveryLoggerFunc(ctx, logger)
@@ -74,6 +92,18 @@ func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 	}
 }
 
+// checkLoginToken 校验 token 与 redis 中保存的登录会话是否一致
+func checkLoginToken(ctx *gin.Context, userRepo repository.UserRepository, claims *jwt.MyCustomClaims, tokenString string) bool {
+	if claims.ID == "" {
+		return false
+	}
+	stored, err := userRepo.Get(ctx, repository.LoginTokenKey(claims.UserId, claims.RoleType, claims.ID))
+	if err != nil {
+		return false
+	}
+	return stored == strings.TrimPrefix(tokenString, "Bearer ")
+}
+
 func recoveryLoggerFunc(ctx *gin.Context, logger *log.Logger) {
 	if userInfo, ok := ctx.MustGet("claims").(*jwt.MyCustomClaims); ok {
 		// 记录用户 ID 和 RoleType
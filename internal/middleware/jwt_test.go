@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/repository"
+	"projectName/pkg/jwt"
+	"projectName/pkg/log"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestAuth(t *testing.T) (*jwt.JWT, repository.UserRepository, *log.Logger) {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	l := &log.Logger{Logger: zap.NewNop()}
+	conf := viper.New()
+	conf.Set("security.jwt.key", "test-key")
+	return jwt.NewJwt(conf, l), repository.NewUserRepository(repository.NewRepository(l, nil, rdb, nil)), l
+}
+
+// login 签发访问 token 并保存会话
+func login(t *testing.T, j *jwt.JWT, userRepo repository.UserRepository, userId string, roleType int, tokenId string) string {
+	token, err := j.GenToken(userId, roleType, tokenId, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Set(context.Background(), repository.LoginTokenKey(userId, roleType, tokenId), token, time.Hour))
+	return token
+}
+
+func TestStrictAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	j, userRepo, l := newTestAuth(t)
+	student := login(t, j, userRepo, "u1", enums.SUTDENT_USER, "t1")
+	otherDevice := login(t, j, userRepo, "u1", enums.SUTDENT_USER, "t2")
+	loggedOut := login(t, j, userRepo, "u1", enums.SUTDENT_USER, "t3")
+	require.NoError(t, userRepo.Delete(context.Background(), repository.LoginTokenKey("u1", enums.SUTDENT_USER, "t3")))
+	// 会话中保存的是同一会话重新签发的 token，旧 token 不再有效
+	replaced := login(t, j, userRepo, "u2", enums.SUTDENT_USER, "t4")
+	refreshed, err := j.GenToken("u2", enums.SUTDENT_USER, "t4", time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Set(context.Background(), repository.LoginTokenKey("u2", enums.SUTDENT_USER, "t4"), refreshed, time.Hour))
+	noSession, err := j.GenToken("u3", enums.SUTDENT_USER, "", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantErr    error
+	}{
+		{"no token", "", http.StatusUnauthorized, v1.ErrUnauthorized},
+		{"invalid token", "abc", http.StatusUnauthorized, v1.ErrUnauthorized},
+		{"valid session", student, http.StatusOK, nil},
+		{"bearer prefix", "Bearer " + student, http.StatusOK, nil},
+		{"other device still valid", otherDevice, http.StatusOK, nil},
+		{"logged out", loggedOut, http.StatusUnauthorized, v1.ErrTokenRevoked},
+		{"replaced token", replaced, http.StatusUnauthorized, v1.ErrTokenRevoked},
+		{"token without session id", noSession, http.StatusUnauthorized, v1.ErrTokenRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := serve(StrictAuth(j, userRepo, l, enums.SUTDENT_USER), "X-Token", tt.token)
+			assert.Equal(t, tt.wantStatus, status)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), resp.Message)
+			}
+		})
+	}
+
+	t.Run("role below required", func(t *testing.T) {
+		status, resp := serve(StrictAuth(j, userRepo, l, enums.SCHOOL_ADMIN), "X-Token", student)
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Equal(t, v1.ErrPermissionDenied.Error(), resp.Message)
+	})
+}
+
+func TestNoStrictAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	j, userRepo, l := newTestAuth(t)
+	valid := login(t, j, userRepo, "u1", enums.COMMON_USER, "t1")
+	loggedOut := login(t, j, userRepo, "u1", enums.COMMON_USER, "t2")
+	require.NoError(t, userRepo.Delete(context.Background(), repository.LoginTokenKey("u1", enums.COMMON_USER, "t2")))
+
+	tests := []struct {
+		name      string
+		token     string
+		wantLogin bool
+	}{
+		{"anonymous", "", false},
+		{"valid session", valid, true},
+		{"logged out treated as anonymous", loggedOut, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := serve(NoStrictAuth(j, userRepo, l), "Authorization", tt.token)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, tt.wantLogin, resp.Data["login"])
+		})
+	}
+}
+
+// serve 通过中间件请求一个返回登录状态的接口
+func serve(auth gin.HandlerFunc, header, token string) (int, struct {
+	Message string
+	Data    map[string]interface{}
+}) {
+	r := gin.New()
+	r.GET("/", auth, func(ctx *gin.Context) {
+		_, ok := ctx.Get("claims")
+		v1.HandleSuccess(ctx, map[string]interface{}{"login": ok})
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set(header, token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp struct {
+		Message string
+		Data    map[string]interface{}
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
@@ -1,29 +1,72 @@
 package model
 
 import (
+	"encoding/json"
 	"gorm.io/gorm"
+	"projectName/internal/enums"
 	"time"
 )
 
 // Article 文章结构体，GORM 数据模型
 type Article struct {
-	ArticleID       uint           `gorm:"primaryKey;autoIncrement"`   // 文章的唯一ID，数据库主键
-	Title           string         `gorm:"type:varchar(255);not null"` // 文章标题
-	Content         string         `gorm:"type:text;not null"`         // 文章内容
-	ContentShort    string         `gorm:"type:varchar(255)"`          // 文章摘要
-	UserID          string         `gorm:"type:varchar(255);not null"` // 用户ID
-	CategoryID      uint           `gorm:"unique;not null"`            // 分类ID
-	Importance      int            `gorm:"type:int;default:0"`         // 文章重要性
-	VisibleRange    string         `gorm:"type:varchar(255);not null"` // 可见范围
-	CommentDisabled bool           `gorm:"type:boolean;default:false"` // 是否禁用评论
-	SourceURI       string         `gorm:"type:varchar(255)"`          // 文章外链
-	Status          int            `gorm:"type:int;default:0"`         // 文章状态
-	UploadedFiles   []byte         `gorm:"type:json"`                  // 上传的文件列表
-	CreatedAt       time.Time      `gorm:"autoCreateTime" `            // 文章创建时间
-	UpdatedAt       time.Time      `gorm:"autoUpdateTime" `            // 文章更新时间
-	DeletedAt       gorm.DeletedAt `gorm:"index"`
+	ArticleID        uint           `gorm:"primaryKey;autoIncrement"`   // 文章的唯一ID，数据库主键
+	Title            string         `gorm:"type:varchar(255);not null"` // 文章标题
+	Content          string         `gorm:"type:text;not null"`         // 文章内容
+	ContentShort     string         `gorm:"type:varchar(255)"`          // 文章摘要
+	UserID           string         `gorm:"type:varchar(255);not null"` // 用户ID
+	CategoryID       uint           `gorm:"not null;index"`             // 分类ID
+	Importance       int            `gorm:"type:int;default:0"`         // 文章重要性
+	VisibleRange     string         `gorm:"type:varchar(255);not null"` // 可见类型
+	VisibleMinRole   int            `gorm:"type:int;default:0"`         // 可见类型为 role 时要求的最低角色
+	SharedUserIds    []string       `gorm:"type:json;serializer:json"`  // 额外共享的用户ID
+	SharedCollegeIds []uint         `gorm:"type:json;serializer:json"`  // 额外共享的学院ID
+	CommentDisabled  bool           `gorm:"type:boolean;default:false"` // 是否禁用评论
+	SourceURI        string         `gorm:"type:varchar(255)"`          // 文章外链
+	Status           int            `gorm:"type:int;default:0"`         // 文章状态
+	ReviewRemark     string         `gorm:"type:varchar(255)"`          // 审核意见（驳回原因）
+	PublishAt        *time.Time     `gorm:"index"`                      // 定时发布时间
+	UploadedFiles    []byte         `gorm:"type:json"`                  // 上传的文件列表
+	CreatedAt        time.Time      `gorm:"autoCreateTime" `            // 文章创建时间
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" `            // 文章更新时间
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
 }
 
 func (m *Article) TableName() string {
 	return "kb_article"
 }
+
+// IsSearchable 已发布的文章写入es，搜索时按可见范围过滤
+func (m *Article) IsSearchable() bool {
+	return m.Status == enums.StatusPublished
+}
+
+// HasUploadedFiles 文件列表非空时返回 true，序列化后的 null 和 [] 都视为没有文件
+func (m *Article) HasUploadedFiles() bool {
+	var files []json.RawMessage
+	if err := json.Unmarshal(m.UploadedFiles, &files); err != nil {
+		return false
+	}
+	return len(files) > 0
+}
+
+// Visibility 返回文章的可见范围，兼容旧版以逗号分隔的可见范围字符串
+func (m *Article) Visibility() Visibility {
+	scope, minRole := m.VisibleRange, m.VisibleMinRole
+	if scope != "" && !IsVisibleScope(scope) {
+		scope, minRole = ParseLegacyVisibleRange(scope)
+	}
+	return Visibility{
+		Scope:            scope,
+		MinRole:          minRole,
+		SharedUserIds:    m.SharedUserIds,
+		SharedCollegeIds: m.SharedCollegeIds,
+	}
+}
+
+// SetVisibility 设置文章的可见范围
+func (m *Article) SetVisibility(visibility Visibility) {
+	m.VisibleRange = visibility.Scope
+	m.VisibleMinRole = visibility.MinRole
+	m.SharedUserIds = visibility.SharedUserIds
+	m.SharedCollegeIds = visibility.SharedCollegeIds
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// ArticleRevision 文章历史版本，每次修改前保存一份旧内容
+type ArticleRevision struct {
+	Id               uint      `gorm:"primaryKey"`
+	ArticleID        uint      `gorm:"not null;uniqueIndex:idx_article_version"` // 文章ID
+	Version          int       `gorm:"not null;uniqueIndex:idx_article_version"` // 版本号，从 1 开始递增
+	Title            string    `gorm:"type:varchar(255);not null"`               // 文章标题
+	Content          string    `gorm:"type:text;not null"`                       // 文章内容
+	ContentShort     string    `gorm:"type:varchar(255)"`                        // 文章摘要
+	CategoryID       uint      `gorm:"not null"`                                 // 分类ID
+	Importance       int       `gorm:"type:int;default:0"`                       // 文章重要性
+	VisibleRange     string    `gorm:"type:varchar(255);not null"`               // 可见类型
+	VisibleMinRole   int       `gorm:"type:int;default:0"`                       // 可见类型为 role 时要求的最低角色
+	SharedUserIds    []string  `gorm:"type:json;serializer:json"`                // 额外共享的用户ID
+	SharedCollegeIds []uint    `gorm:"type:json;serializer:json"`                // 额外共享的学院ID
+	CommentDisabled  bool      `gorm:"type:boolean;default:false"`               // 是否禁用评论
+	SourceURI        string    `gorm:"type:varchar(255)"`                        // 文章外链
+	UploadedFiles    []byte    `gorm:"type:json"`                                // 上传的文件列表
+	Tags             []string  `gorm:"type:json;serializer:json"`                // 文章标签，为空表示早期版本未记录标签
+	EditorId         string    `gorm:"type:varchar(255)"`                        // 产生该版本的修改人
+	CreatedAt        time.Time `gorm:"autoCreateTime"`                           // 版本创建时间
+}
+
+func (m *ArticleRevision) TableName() string {
+	return "kb_article_revision"
+}
+
+// NewArticleRevision 根据文章当前内容和标签生成历史版本
+func NewArticleRevision(article *Article, tags []string, version int, editorId string) *ArticleRevision {
+	if tags == nil {
+		tags = []string{}
+	}
+	return &ArticleRevision{
+		ArticleID:        article.ArticleID,
+		Version:          version,
+		Title:            article.Title,
+		Content:          article.Content,
+		ContentShort:     article.ContentShort,
+		CategoryID:       article.CategoryID,
+		Importance:       article.Importance,
+		VisibleRange:     article.VisibleRange,
+		VisibleMinRole:   article.VisibleMinRole,
+		SharedUserIds:    article.SharedUserIds,
+		SharedCollegeIds: article.SharedCollegeIds,
+		CommentDisabled:  article.CommentDisabled,
+		SourceURI:        article.SourceURI,
+		UploadedFiles:    article.UploadedFiles,
+		Tags:             tags,
+		EditorId:         editorId,
+	}
+}
+
+// Visibility 返回该版本的可见范围
+func (m *ArticleRevision) Visibility() Visibility {
+	article := Article{
+		VisibleRange:     m.VisibleRange,
+		VisibleMinRole:   m.VisibleMinRole,
+		SharedUserIds:    m.SharedUserIds,
+		SharedCollegeIds: m.SharedCollegeIds,
+	}
+	return article.Visibility()
+}
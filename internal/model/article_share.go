@@ -0,0 +1,12 @@
+package model
+
+// ArticleShare 文章的共享对象，与文章的共享列表同步保存，用于列表查询时按共享对象过滤
+type ArticleShare struct {
+	ArticleID  uint   `gorm:"primaryKey"`                                    // 文章ID
+	TargetType string `gorm:"type:varchar(20);primaryKey"`                   // 共享对象类型：user、college
+	TargetId   string `gorm:"type:varchar(255);primaryKey;index:idx_target"` // 用户ID或学院ID
+}
+
+func (m *ArticleShare) TableName() string {
+	return "kb_article_share"
+}
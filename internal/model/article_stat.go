@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// ArticleStat 文章的统计数据，浏览量先累计在 redis 中，由 task 服务定期写入
+// 与文章分开存储，避免保存文章时覆盖统计数据
+type ArticleStat struct {
+	ArticleID uint      `gorm:"primaryKey;autoIncrement:false"` // 文章ID
+	Views     int64     `gorm:"not null;default:0;index"`       // 浏览量
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (m *ArticleStat) TableName() string {
+	return "kb_article_stat"
+}
+
+// ArticleViewBatch 已写入数据库的浏览量批次，写入数据库后清除 redis 失败时，重试同一批次不会重复累加
+type ArticleViewBatch struct {
+	BatchId   string    `gorm:"type:varchar(64);primaryKey"` // 批次ID
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+func (m *ArticleViewBatch) TableName() string {
+	return "kb_article_view_batch"
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Attachment 上传的附件，相同内容的文件按校验和共用同一个存储对象
+type Attachment struct {
+	Id         uint      `gorm:"primaryKey"`
+	UserId     string    `gorm:"type:varchar(255);not null;index"` // 上传人ID
+	ArticleID  uint      `gorm:"default:0;index"`                  // 关联的文章ID，未关联时为 0
+	FileName   string    `gorm:"type:varchar(255);not null"`       // 原始文件名
+	Checksum   string    `gorm:"type:char(64);not null;index"`     // 文件内容的 SHA-256
+	Size       int64     `gorm:"not null"`                         // 文件大小（字节）
+	MimeType   string    `gorm:"type:varchar(255)"`                // 文件类型
+	StorageKey string    `gorm:"type:varchar(255);not null"`       // 存储中的对象路径
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+
+	ExtractStatus   int    `gorm:"default:0;index"` // 文本提取状态
+	ExtractAttempts int    `gorm:"default:0"`       // 文本提取尝试次数
+	Content         string `gorm:"type:longtext"`   // 提取出的文本，用于全文检索
+}
+
+func (m *Attachment) TableName() string {
+	return "kb_attachment"
+}
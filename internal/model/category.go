@@ -6,9 +6,10 @@ import (
 )
 
 type Category struct {
-	CId          uint   `gorm:"primaryKey;autoIncrement"`
+	CId          uint   `gorm:"column:category_id;primaryKey;autoIncrement"`
 	CategoryName string `gorm:"type:varchar(255);not null"`
 	ParentId     uint   `gorm:"type:int;default:0"`
+	NeedReview   int    `gorm:"default:0"` // 该分类下的文章是否需要审核
 	IsDeleted    int    `gorm:"default:0"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
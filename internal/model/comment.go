@@ -0,0 +1,23 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// Comment 文章评论，两级结构：顶层评论 RootId 为 0，回复记录所属顶层评论和被回复的评论
+type Comment struct {
+	Id        uint           `gorm:"primaryKey"`
+	ArticleID uint           `gorm:"not null;index"`             // 文章ID
+	UserId    string         `gorm:"type:varchar(255);not null"` // 评论人ID
+	ParentId  uint           `gorm:"default:0"`                  // 被回复的评论ID，顶层评论为 0
+	RootId    uint           `gorm:"default:0;index"`            // 所属顶层评论ID，顶层评论为 0
+	ReplyTo   string         `gorm:"type:varchar(255)"`          // 被回复的用户ID
+	Content   string         `gorm:"type:text;not null"`         // 评论内容
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (m *Comment) TableName() string {
+	return "kb_comment"
+}
@@ -6,18 +6,60 @@ import (
 
 // EsArticle 文章结构
 type EsArticle struct {
-	ArticleID       uint      `json:"article_id"`
-	Title           string    `json:"title"`
-	Content         string    `json:"content"`
-	ContentShort    string    `json:"content_short"`
-	UserID          string    `json:"user_id"`
-	CategoryID      uint      `json:"category_id"`
-	Importance      int       `json:"importance"`
-	VisibleRange    string    `json:"visible_range"`
-	CommentDisabled bool      `json:"comment_disabled"`
-	SourceURI       string    `json:"source_uri"`
-	Status          int       `json:"status"`
-	UploadedFile    bool      `json:"uploaded_file"`
-	CreatedAt       time.Time `json:"created_at"` // 使用 sql.NullTime
-	UpdatedAt       time.Time `json:"updated_at"` // 使用 sql.NullTime
+	ArticleID        uint           `json:"article_id"`
+	Title            string         `json:"title"`
+	Content          string         `json:"content"`
+	ContentShort     string         `json:"content_short"`
+	UserID           string         `json:"user_id"`
+	CategoryID       uint           `json:"category_id"`
+	Importance       int            `json:"importance"`
+	VisibleRange     string         `json:"visible_range"`
+	Visibility       string         `json:"visibility"`         // 解析后的可见类型
+	MinRole          int            `json:"min_role"`           // 角色限制时要求的最低角色
+	CollegeId        uint           `json:"college_id"`         // 作者所属学院
+	SharedUserIds    []string       `json:"shared_user_ids"`    // 额外共享的用户
+	SharedCollegeIds []uint         `json:"shared_college_ids"` // 额外共享的学院
+	CommentDisabled  bool           `json:"comment_disabled"`
+	SourceURI        string         `json:"source_uri"`
+	Status           int            `json:"status"`
+	UploadedFile     bool           `json:"uploaded_file"`
+	Tags             []string       `json:"tags"`
+	Attachments      []EsAttachment `json:"attachments"` // 附件文本，nested 类型
+	Views            int64          `json:"views"`       // 浏览量，随浏览量写入数据库后同步
+	CreatedAt        time.Time      `json:"created_at"`  // 使用 sql.NullTime
+	UpdatedAt        time.Time      `json:"updated_at"`  // 使用 sql.NullTime
+}
+
+// EsAttachment es文档中的附件文本
+type EsAttachment struct {
+	AttachmentId uint   `json:"attachment_id"`
+	FileName     string `json:"file_name"`
+	Content      string `json:"content"`
+}
+
+// NewEsArticle 由文章生成es文档
+func NewEsArticle(article *Article) *EsArticle {
+	esArticle := &EsArticle{
+		ArticleID:       article.ArticleID,
+		Title:           article.Title,
+		Content:         article.Content,
+		ContentShort:    article.ContentShort,
+		CategoryID:      article.CategoryID,
+		UserID:          article.UserID,
+		Importance:      article.Importance,
+		Status:          article.Status,
+		VisibleRange:    article.VisibleRange,
+		CommentDisabled: article.CommentDisabled,
+		SourceURI:       article.SourceURI,
+		UploadedFile:    article.HasUploadedFiles(),
+		CreatedAt:       article.CreatedAt,
+		UpdatedAt:       article.UpdatedAt,
+	}
+	visibility := article.Visibility()
+	esArticle.VisibleRange = visibility.Scope
+	esArticle.Visibility = visibility.Scope
+	esArticle.MinRole = visibility.MinRole
+	esArticle.SharedUserIds = visibility.SharedUserIds
+	esArticle.SharedCollegeIds = visibility.SharedCollegeIds
+	return esArticle
 }
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEsArticle_UploadedFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		uploadedFiles []byte
+		want          bool
+	}{
+		{"no column value", nil, false},
+		{"empty bytes", []byte{}, false},
+		{"null list", []byte("null"), false},
+		{"empty list", []byte("[]"), false},
+		{"invalid json", []byte("{"), false},
+		{"with files", []byte(`[{"file_name":"a.pdf","attachment_id":1}]`), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esArticle := NewEsArticle(&Article{UploadedFiles: tt.uploadedFiles})
+			assert.Equal(t, tt.want, esArticle.UploadedFile)
+		})
+	}
+}
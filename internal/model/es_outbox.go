@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// EsOutbox 文章的es同步任务，与文章修改在同一事务中写入，由 task 服务按文章最新状态同步到es
+type EsOutbox struct {
+	Id          uint      `gorm:"primaryKey"`
+	ArticleID   uint      `gorm:"not null;index"`     // 需要同步的文章ID
+	Attempts    int       `gorm:"default:0"`          // 已失败的次数
+	NextRetryAt time.Time `gorm:"not null;index"`     // 下次可处理的时间
+	LastError   string    `gorm:"type:varchar(1024)"` // 最近一次失败原因
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (m *EsOutbox) TableName() string {
+	return "kb_es_outbox"
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// ArticleLike 文章点赞，主键保证同一用户对同一文章只能点赞一次，点赞数按记录统计
+type ArticleLike struct {
+	ArticleID uint      `gorm:"primaryKey;autoIncrement:false"` // 文章ID
+	UserId    string    `gorm:"type:varchar(255);primaryKey;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (m *ArticleLike) TableName() string {
+	return "kb_article_like"
+}
+
+// FavoriteFolder 用户自定义的收藏夹，未放入收藏夹的收藏属于默认收藏夹（ID 为 0）
+type FavoriteFolder struct {
+	Id        uint      `gorm:"primaryKey"`
+	UserId    string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_folder_name"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_folder_name"` // 收藏夹名称，同一用户下不重复
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (m *FavoriteFolder) TableName() string {
+	return "kb_favorite_folder"
+}
+
+// ArticleFavorite 文章收藏，主键保证同一用户对同一文章只收藏一次，收藏数按记录统计
+type ArticleFavorite struct {
+	ArticleID uint      `gorm:"primaryKey;autoIncrement:false"`                     // 文章ID
+	UserId    string    `gorm:"type:varchar(255);primaryKey;index:idx_user_folder"` // 收藏人ID
+	FolderId  uint      `gorm:"default:0;index:idx_user_folder"`                    // 所属收藏夹，0 为默认收藏夹
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (m *ArticleFavorite) TableName() string {
+	return "kb_article_favorite"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Notification 站内通知
+type Notification struct {
+	Id        uint      `gorm:"primaryKey"`
+	UserId    string    `gorm:"not null;index"`             // 接收通知的用户ID
+	Type      int       `gorm:"not null"`                   // 通知类型
+	Title     string    `gorm:"type:varchar(255);not null"` // 通知标题
+	Content   string    `gorm:"type:text"`                  // 通知内容
+	RelatedId uint      `gorm:"default:0"`                  // 关联对象ID，如文章ID
+	IsRead    int       `gorm:"default:0"`                  // 是否已读
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (m *Notification) TableName() string {
+	return "sys_notifications"
+}
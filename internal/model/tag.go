@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Tag 文章标签
+type Tag struct {
+	Id        uint      `gorm:"primaryKey"`
+	TagName   string    `gorm:"type:varchar(64);not null;uniqueIndex"` // 标签名称
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (m *Tag) TableName() string {
+	return "kb_tag"
+}
+
+// ArticleTag 文章与标签的多对多关联
+type ArticleTag struct {
+	ArticleID uint `gorm:"primaryKey"`       // 文章ID
+	TagId     uint `gorm:"primaryKey;index"` // 标签ID
+}
+
+func (m *ArticleTag) TableName() string {
+	return "kb_article_tag"
+}
@@ -6,19 +6,20 @@ import (
 )
 
 type User struct {
-	Id        uint   `gorm:"primarykey"`
-	UserId    string `gorm:"unique;not null"`
-	Phone     string `gorm:"not null"`
-	Nickname  string `gorm:"not null"`
-	Password  string `gorm:"not null"`
-	RoleType  int    `gorm:"not null"` // 0: 普通用户，1: 学校用户，2: 学校管理员 3: 超级管理员
-	Email     string
-	CollegeId uint
-	StudentId string
-	IsDeleted int `gorm:"default:0"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	Id         uint   `gorm:"primarykey"`
+	UserId     string `gorm:"unique;not null"`
+	Phone      string `gorm:"not null"`
+	Nickname   string `gorm:"not null"`
+	Password   string `gorm:"not null"`
+	RoleType   int    `gorm:"not null"` // 0: 普通用户，1: 学校用户，2: 学校管理员 3: 超级管理员
+	Email      string
+	CollegeId  uint
+	StudentId  string
+	IsDisabled int `gorm:"default:0"` // 是否禁用，禁用后无法登录
+	IsDeleted  int `gorm:"default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
 }
 
 func (u *User) TableName() string {
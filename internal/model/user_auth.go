@@ -10,6 +10,7 @@ type UserAuth struct {
 	ApplyTime   time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP"` // 申请时间
 	DisposeTime *time.Time `gorm:"default:null"`                       // 处理时间
 	AdminId     *string    `gorm:"default:null"`                       // 处理该请求的管理员ID
+	AdminRemark *string    `gorm:"type:text;default:null"`             // 管理员审核意见
 	Remarks     *string    `gorm:"type:text;default:null"`             // 备注，允许为空
 	CollegeId   *uint      `gorm:"default:null"`                       // 学校ID，允许为空
 	StudentId   *string    `gorm:"default:null"`                       // 学生ID，允许为空
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"projectName/internal/enums"
+	"strconv"
+	"strings"
+)
+
+// Visibility 文章可见范围
+type Visibility struct {
+	Scope            string   // 可见类型：public、login、college、role、private
+	MinRole          int      // 可见类型为 role 时要求的最低角色
+	SharedUserIds    []string // 额外共享的用户，不受可见类型限制
+	SharedCollegeIds []uint   // 额外共享的学院，学院内的用户均可查看
+}
+
+// IsVisibleScope 判断是否为有效的可见类型
+func IsVisibleScope(scope string) bool {
+	switch scope {
+	case enums.VisiblePublic, enums.VisibleLogin, enums.VisibleCollege, enums.VisibleRole, enums.VisiblePrivate:
+		return true
+	}
+	return false
+}
+
+// CanView 判断用户能否查看文章，viewer 为 nil 表示未登录
+// authorCollegeId 为作者所属学院，仅在可见类型为 college 时使用
+func (v *Visibility) CanView(viewer *User, authorId string, authorCollegeId uint) bool {
+	if viewer == nil {
+		return v.Scope == enums.VisiblePublic
+	}
+	if viewer.UserId == authorId {
+		return true
+	}
+	switch v.Scope {
+	case enums.VisiblePublic, enums.VisibleLogin:
+		return true
+	case enums.VisibleCollege:
+		if viewer.CollegeId != 0 && viewer.CollegeId == authorCollegeId {
+			return true
+		}
+	case enums.VisibleRole:
+		if viewer.RoleType >= v.MinRole {
+			return true
+		}
+	}
+	for _, userId := range v.SharedUserIds {
+		if userId == viewer.UserId {
+			return true
+		}
+	}
+	for _, collegeId := range v.SharedCollegeIds {
+		if viewer.CollegeId != 0 && collegeId == viewer.CollegeId {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal 比较两个可见范围是否相同，空列表与 nil 视为相同
+func (v *Visibility) Equal(other *Visibility) bool {
+	if v.Scope != other.Scope || v.MinRole != other.MinRole ||
+		len(v.SharedUserIds) != len(other.SharedUserIds) ||
+		len(v.SharedCollegeIds) != len(other.SharedCollegeIds) {
+		return false
+	}
+	for i := range v.SharedUserIds {
+		if v.SharedUserIds[i] != other.SharedUserIds[i] {
+			return false
+		}
+	}
+	for i := range v.SharedCollegeIds {
+		if v.SharedCollegeIds[i] != other.SharedCollegeIds[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseLegacyVisibleRange 解析旧版以逗号分隔的可见范围，如 "public"、"college"、"role:2"
+// 同时包含多项时取最严格的一项，未识别的值按公开处理
+func ParseLegacyVisibleRange(visibleRange string) (string, int) {
+	scope, minRole := enums.VisiblePublic, 0
+	for _, item := range strings.Split(visibleRange, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == enums.VisiblePrivate:
+			return enums.VisiblePrivate, 0
+		case item == enums.VisibleCollege:
+			scope, minRole = enums.VisibleCollege, 0
+		case strings.HasPrefix(item, enums.VisibleRole+":") && scope != enums.VisibleCollege:
+			role, err := strconv.Atoi(strings.TrimPrefix(item, enums.VisibleRole+":"))
+			if err == nil {
+				scope, minRole = enums.VisibleRole, role
+			}
+		}
+	}
+	return scope, minRole
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"projectName/internal/enums"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisibility_CanView(t *testing.T) {
+	const (
+		authorId        = "author"
+		authorCollegeId = uint(1)
+	)
+	student := &User{UserId: "student", RoleType: enums.SUTDENT_USER, CollegeId: 1}
+	otherCollege := &User{UserId: "other", RoleType: enums.SUTDENT_USER, CollegeId: 2}
+	noCollege := &User{UserId: "nocollege", RoleType: enums.COMMON_USER}
+	schoolAdmin := &User{UserId: "admin", RoleType: enums.SCHOOL_ADMIN, CollegeId: 2}
+	author := &User{UserId: authorId, RoleType: enums.COMMON_USER, CollegeId: 1}
+
+	tests := []struct {
+		name       string
+		visibility Visibility
+		viewer     *User
+		want       bool
+	}{
+		{"public to anonymous", Visibility{Scope: enums.VisiblePublic}, nil, true},
+		{"login to anonymous", Visibility{Scope: enums.VisibleLogin}, nil, false},
+		{"login to user", Visibility{Scope: enums.VisibleLogin}, noCollege, true},
+		{"private to author", Visibility{Scope: enums.VisiblePrivate}, author, true},
+		{"private to other", Visibility{Scope: enums.VisiblePrivate}, student, false},
+		{"private to anonymous even if shared", Visibility{Scope: enums.VisiblePrivate, SharedUserIds: []string{"student"}}, nil, false},
+		{"college to same college", Visibility{Scope: enums.VisibleCollege}, student, true},
+		{"college to other college", Visibility{Scope: enums.VisibleCollege}, otherCollege, false},
+		{"college to user without college", Visibility{Scope: enums.VisibleCollege}, noCollege, false},
+		{"role below min role", Visibility{Scope: enums.VisibleRole, MinRole: enums.SCHOOL_ADMIN}, student, false},
+		{"role at min role", Visibility{Scope: enums.VisibleRole, MinRole: enums.SCHOOL_ADMIN}, schoolAdmin, true},
+		{"shared user", Visibility{Scope: enums.VisiblePrivate, SharedUserIds: []string{"x", "student"}}, student, true},
+		{"shared college", Visibility{Scope: enums.VisiblePrivate, SharedCollegeIds: []uint{2}}, otherCollege, true},
+		{"shared college ignores users without college", Visibility{Scope: enums.VisiblePrivate, SharedCollegeIds: []uint{0}}, noCollege, false},
+		{"shared lists widen role scope", Visibility{Scope: enums.VisibleRole, MinRole: enums.SUPER_ADMIN, SharedCollegeIds: []uint{1}}, student, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.visibility.CanView(tt.viewer, authorId, authorCollegeId))
+		})
+	}
+}
+
+func TestParseLegacyVisibleRange(t *testing.T) {
+	tests := []struct {
+		visibleRange string
+		wantScope    string
+		wantMinRole  int
+	}{
+		{"", enums.VisiblePublic, 0},
+		{"public", enums.VisiblePublic, 0},
+		{"unknown", enums.VisiblePublic, 0},
+		{"private", enums.VisiblePrivate, 0},
+		{"college", enums.VisibleCollege, 0},
+		{"role:2", enums.VisibleRole, 2},
+		{"role:abc", enums.VisiblePublic, 0},
+		{" role:1 , public", enums.VisibleRole, 1},
+		{"role:2,college", enums.VisibleCollege, 0},
+		{"college,role:2", enums.VisibleCollege, 0},
+		{"college,private,role:1", enums.VisiblePrivate, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.visibleRange, func(t *testing.T) {
+			scope, minRole := ParseLegacyVisibleRange(tt.visibleRange)
+			assert.Equal(t, tt.wantScope, scope)
+			assert.Equal(t, tt.wantMinRole, minRole)
+		})
+	}
+}
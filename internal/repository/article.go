@@ -5,45 +5,92 @@ import (
 	"errors"
 	"fmt"
 	"github.com/olivere/elastic/v7"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	v1 "projectName/api/v1"
 	"projectName/internal/enums"
 	"projectName/internal/model"
 	"projectName/internal/model/vo"
+	"strconv"
 	"time"
 )
 
 type ArticleRepository interface {
 	GetArticle(ctx context.Context, id uint) (*model.Article, error)
+	GetArticleFromDB(ctx context.Context, id uint) (*model.Article, error)
 	CreateArticle(ctx context.Context, article *model.Article) (int, error)
 	GetArticleByTitleAndUserId(ctx context.Context, title string, authorID string) (*model.Article, error)
 	FetchAllCategoriesAndBuildTree(ctx context.Context) ([]vo.CategoryView, error)
 	GetCategory(ctx context.Context, id uint) (*vo.CategoryView, error)
+	GetCategoriesByIds(ctx context.Context, ids []uint) ([]vo.CategoryView, error)
+	GetCategoryById(ctx context.Context, id uint) (*model.Category, error)
+	GetCategoryByNameAndParent(ctx context.Context, name string, parentId uint) (*model.Category, error)
+	GetAllCategories(ctx context.Context) ([]model.Category, error)
+	CreateCategory(ctx context.Context, category *model.Category) error
+	UpdateCategory(ctx context.Context, category *model.Category) error
+	DeleteCategory(ctx context.Context, id uint) error
+	CountArticleByCategory(ctx context.Context, categoryId uint) (int64, error)
+	MoveArticleCategory(ctx context.Context, fromCategoryId uint, toCategoryId uint) (int, error)
+	GetArticleIdsByCategory(ctx context.Context, categoryId uint) ([]uint, error)
 	UpdateArticle(ctx context.Context, article *model.Article) (*model.Article, error)
 	DeleteArticle(ctx context.Context, id uint) (int, error)
 	DeleteArticleList(ctx context.Context, ids []uint) (int, error)
-	GetArticleListByCategory(ctx context.Context, categoryId uint, pageNum int, pageSize int) ([]model.Article, int64, error)
+	GetArticleListByCategory(ctx context.Context, viewer *model.User, categoryId uint, afterId uint, pageNum int, pageSize int) ([]model.Article, int64, error)
+	GetVisibleArticlesByIds(ctx context.Context, viewer *model.User, ids []uint) ([]model.Article, error)
 	GetUserArticleList(ctx context.Context, userId string, req *v1.GetUserArticleListReq, pageNum int, pageSize int) ([]model.Article, int64, error)
-	GetArticleListByEs(ctx context.Context, query *elastic.BoolQuery, highlight *elastic.Highlight, from, size int) (*elastic.SearchResult, error)
+	GetDueScheduledArticles(ctx context.Context, now time.Time, limit int) ([]model.Article, error)
+	GetSearchableArticles(ctx context.Context, afterId uint, limit int) ([]model.Article, error)
+	GetArticleIdsUpdatedSince(ctx context.Context, since time.Time) ([]uint, error)
+	PublishScheduledArticle(ctx context.Context, id uint) (bool, error)
+	GetReviewArticleList(ctx context.Context, collegeId uint, pageNum int, pageSize int) ([]model.Article, int64, error)
+	GetArticleListByEs(ctx context.Context, query *elastic.BoolQuery, highlight *elastic.Highlight, aggs map[string]elastic.Aggregation, sorters []elastic.Sorter, searchAfter []interface{}, from, size int) (*elastic.SearchResult, error)
+	SuggestEsArticles(ctx context.Context, query elastic.Query, suggester elastic.Suggester, size int) (*elastic.SearchResult, error)
+	GetSearchSuggestCache(ctx context.Context, key string) (string, bool, error)
+	SetSearchSuggestCache(ctx context.Context, key string, value string, expiration time.Duration) error
+	GetEsArticle(ctx context.Context, article *model.Article) (*model.EsArticle, error)
+	EnsureEsArticleTemplate(ctx context.Context) error
+	EnsureEsArticleMapping(ctx context.Context) error
 	CreateEsArticle(ctx context.Context, article *model.EsArticle) error
 	UpdateEsArticle(ctx context.Context, article *model.EsArticle) error
 	DeleteEsArticle(ctx context.Context, articleId uint) error
+	CreateEsArticleIndex(ctx context.Context, index string) error
+	BulkIndexEsArticles(ctx context.Context, index string, articles []*model.EsArticle) error
+	SwitchEsArticleAlias(ctx context.Context, index string) ([]string, error)
+	GetEsArticlesByIds(ctx context.Context, ids []uint) (map[uint]*model.EsArticle, error)
+	ScrollEsArticleIds(ctx context.Context, fn func(ids []uint) error) error
 }
 
 func NewArticleRepository(
 	repository *Repository,
+	conf *viper.Viper,
 ) ArticleRepository {
 	return &articleRepository{
 		Repository: repository,
+		analyzer:   newEsAnalyzerConfig(conf),
 	}
 }
 
 type articleRepository struct {
 	*Repository
+	analyzer esAnalyzerConfig // es 文章索引的分词器配置
 }
 
+// GetArticle 获取文章，优先读取缓存
 func (r *articleRepository) GetArticle(ctx context.Context, id uint) (*model.Article, error) {
+	var article model.Article
+	err := r.cacheReadThrough(ctx, articleCacheKey(id), articleCacheTTL, &article, func(ctx context.Context) (interface{}, error) {
+		return r.GetArticleFromDB(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// GetArticleFromDB 不经过缓存直接查询文章，修改文章前读取和同步es时使用，避免基于缓存中的旧数据整行保存
+func (r *articleRepository) GetArticleFromDB(ctx context.Context, id uint) (*model.Article, error) {
 	var article model.Article
 	if err := r.DB(ctx).Table("kb_article").Where("article_id = ?", id).First(&article).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -61,13 +108,72 @@ func (r *articleRepository) CreateArticle(ctx context.Context, article *model.Ar
 		r.logger.WithContext(ctx).Error("ArticleRepository.CreateArticle error", zap.Error(err))
 		return -1, err
 	}
+	if err := r.saveArticleShares(ctx, article); err != nil {
+		return -1, err
+	}
+	// 清除新ID可能存在的空值缓存
+	r.invalidateArticleCache(ctx, article.ArticleID)
 	return int(article.ArticleID), nil
 }
 
+// invalidateArticleCache 清除文章缓存
+func (r *articleRepository) invalidateArticleCache(ctx context.Context, ids ...uint) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, articleCacheKey(id))
+	}
+	r.invalidateCache(ctx, keys...)
+}
+
+// saveArticleShares 按文章的共享列表重建共享对象，调用方需在事务中执行
+func (r *articleRepository) saveArticleShares(ctx context.Context, article *model.Article) error {
+	if err := r.DB(ctx).Where("article_id = ?", article.ArticleID).Delete(&model.ArticleShare{}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.saveArticleShares Delete error", zap.Error(err))
+		return err
+	}
+	shares := make([]model.ArticleShare, 0, len(article.SharedUserIds)+len(article.SharedCollegeIds))
+	for _, userId := range article.SharedUserIds {
+		shares = append(shares, model.ArticleShare{ArticleID: article.ArticleID, TargetType: enums.ShareTargetUser, TargetId: userId})
+	}
+	for _, collegeId := range article.SharedCollegeIds {
+		shares = append(shares, model.ArticleShare{ArticleID: article.ArticleID, TargetType: enums.ShareTargetCollege, TargetId: strconv.FormatUint(uint64(collegeId), 10)})
+	}
+	if len(shares) == 0 {
+		return nil
+	}
+	if err := r.DB(ctx).Create(&shares).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.saveArticleShares Create error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// visibleTo 过滤用户可以查看的文章，规则与 model.Visibility.CanView 一致，需关联作者表 author
+func visibleTo(viewer *model.User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		condition := "kb_article.user_id = ? OR kb_article.visible_range IN ? OR " +
+			"(kb_article.visible_range = ? AND kb_article.visible_min_role <= ?) OR " +
+			"EXISTS (SELECT 1 FROM kb_article_share s WHERE s.article_id = kb_article.article_id AND s.target_type = ? AND s.target_id = ?)"
+		args := []interface{}{
+			viewer.UserId, []string{enums.VisiblePublic, enums.VisibleLogin},
+			enums.VisibleRole, viewer.RoleType,
+			enums.ShareTargetUser, viewer.UserId,
+		}
+		if viewer.CollegeId != 0 {
+			collegeId := strconv.FormatUint(uint64(viewer.CollegeId), 10)
+			condition += " OR (kb_article.visible_range = ? AND author.college_id = ?) OR " +
+				"EXISTS (SELECT 1 FROM kb_article_share s WHERE s.article_id = kb_article.article_id AND s.target_type = ? AND s.target_id = ?)"
+			args = append(args, enums.VisibleCollege, viewer.CollegeId, enums.ShareTargetCollege, collegeId)
+		}
+		return db.Joins("LEFT JOIN sys_users author ON author.user_id = kb_article.user_id").
+			Where("("+condition+")", args...)
+	}
+}
+
 func (r *articleRepository) GetArticleByTitleAndUserId(ctx context.Context, title string, authorID string) (*model.Article, error) {
 	var article model.Article
 	result := r.db.WithContext(ctx).
-		Where("title = ? AND author_id = ?", title, authorID).
+		Where("title = ? AND user_id = ? AND status <> ?", title, authorID, enums.StatusDraft).
 		First(&article)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -92,12 +198,29 @@ func BuildCategoryTree(data []vo.CategoryView, parentId uint) []vo.CategoryView
 	return result
 }
 
-// FetchAllCategoriesAndBuildTree 从数据库获取所有分类数据并构建树状结构
-func (r *articleRepository) FetchAllCategoriesAndBuildTree(ctx context.Context) ([]vo.CategoryView, error) {
+// getCategoryViews 获取所有分类，优先读取缓存
+// 分类数量较少，整体缓存后单个分类和分类树都从缓存中获取，分类变更时只需清除一个缓存
+func (r *articleRepository) getCategoryViews(ctx context.Context) ([]vo.CategoryView, error) {
 	var categories []vo.CategoryView
-	// 查询视图中的所有分类
-	if err := r.DB(ctx).Table("view_category_tree").Find(&categories).Error; err != nil {
-		r.logger.WithContext(ctx).Error("Failed to fetch categories from view_category_tree", zap.Error(err))
+	err := r.cacheReadThrough(ctx, categoryCacheKey, categoryCacheTTL, &categories, func(ctx context.Context) (interface{}, error) {
+		var categories []vo.CategoryView
+		// 查询视图中的所有分类
+		if err := r.DB(ctx).Table("view_category_tree").Find(&categories).Error; err != nil {
+			r.logger.WithContext(ctx).Error("Failed to fetch categories from view_category_tree", zap.Error(err))
+			return nil, err
+		}
+		return categories, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// FetchAllCategoriesAndBuildTree 获取所有分类数据并构建树状结构
+func (r *articleRepository) FetchAllCategoriesAndBuildTree(ctx context.Context) ([]vo.CategoryView, error) {
+	categories, err := r.getCategoryViews(ctx)
+	if err != nil {
 		return nil, err
 	}
 	// 调用 BuildCategoryTree 函数将平坦的分类数据转换为树状结构
@@ -106,21 +229,146 @@ func (r *articleRepository) FetchAllCategoriesAndBuildTree(ctx context.Context)
 	return tree, nil
 }
 
+// GetCategory 从缓存的分类中获取单个分类
 func (r *articleRepository) GetCategory(ctx context.Context, id uint) (*vo.CategoryView, error) {
-	var categoryView vo.CategoryView
+	categories, err := r.getCategoryViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		if categories[i].CId == id {
+			return &categories[i], nil
+		}
+	}
+	return nil, v1.ErrNotFound
+}
+
+// GetCategoriesByIds 批量获取分类，不存在的分类不会出现在结果中
+func (r *articleRepository) GetCategoriesByIds(ctx context.Context, ids []uint) ([]vo.CategoryView, error) {
+	var result []vo.CategoryView
+	if len(ids) == 0 {
+		return result, nil
+	}
+	categories, err := r.getCategoryViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[id] = struct{}{}
+	}
+	for _, category := range categories {
+		if _, ok := wanted[category.CId]; ok {
+			result = append(result, category)
+		}
+	}
+	return result, nil
+}
 
-	// 查询视图中的单个分类，使用传入的 id
-	if err := r.DB(ctx).Table("view_category_tree").Where("category_id = ?", id).First(&categoryView).Error; err != nil {
+func (r *articleRepository) GetCategoryById(ctx context.Context, id uint) (*model.Category, error) {
+	var category model.Category
+	if err := r.DB(ctx).Table("kb_category").Where("category_id = ? AND is_deleted = 0", id).First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// 如果没有找到记录，返回自定义的错误
 			return nil, v1.ErrNotFound
 		}
-		r.logger.WithContext(ctx).Error("Failed to fetch category from view_category_tree", zap.Uint("categoryId", id), zap.Error(err))
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetCategoryById error", zap.Error(err))
 		return nil, err
 	}
+	return &category, nil
+}
+
+func (r *articleRepository) GetCategoryByNameAndParent(ctx context.Context, name string, parentId uint) (*model.Category, error) {
+	var category model.Category
+	if err := r.DB(ctx).Table("kb_category").
+		Where("category_name = ? AND parent_id = ? AND is_deleted = 0", name, parentId).
+		First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // 没有找到匹配的记录
+		}
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetCategoryByNameAndParent error", zap.Error(err))
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetAllCategories 获取所有未删除的分类（平坦结构）
+func (r *articleRepository) GetAllCategories(ctx context.Context) ([]model.Category, error) {
+	var categories []model.Category
+	if err := r.DB(ctx).Table("kb_category").Where("is_deleted = 0").Find(&categories).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetAllCategories error", zap.Error(err))
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *articleRepository) CreateCategory(ctx context.Context, category *model.Category) error {
+	if err := r.DB(ctx).Table("kb_category").Create(category).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.CreateCategory error", zap.Error(err))
+		return err
+	}
+	r.invalidateCache(ctx, categoryCacheKey)
+	return nil
+}
 
-	// 返回查询到的分类信息
-	return &categoryView, nil
+func (r *articleRepository) UpdateCategory(ctx context.Context, category *model.Category) error {
+	if err := r.DB(ctx).Table("kb_category").Save(category).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.UpdateCategory error", zap.Error(err))
+		return err
+	}
+	r.invalidateCache(ctx, categoryCacheKey)
+	return nil
+}
+
+func (r *articleRepository) DeleteCategory(ctx context.Context, id uint) error {
+	// 更新 is_deleted 字段为 1，并设置 deleted_at 为当前时间
+	if err := r.DB(ctx).Table("kb_category").
+		Where("category_id = ?", id).
+		Updates(map[string]interface{}{"is_deleted": 1, "deleted_at": time.Now()}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.DeleteCategory error", zap.Error(err))
+		return err
+	}
+	r.invalidateCache(ctx, categoryCacheKey)
+	return nil
+}
+
+func (r *articleRepository) CountArticleByCategory(ctx context.Context, categoryId uint) (int64, error) {
+	var total int64
+	if err := r.DB(ctx).Model(&model.Article{}).
+		Where("category_id = ? AND status <> ?", categoryId, enums.StatusDeleted).
+		Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.CountArticleByCategory error", zap.Error(err))
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetArticleIdsByCategory 获取分类下所有文章的ID，包含未发布的文章
+func (r *articleRepository) GetArticleIdsByCategory(ctx context.Context, categoryId uint) ([]uint, error) {
+	var ids []uint
+	if err := r.DB(ctx).Table("kb_article").
+		Where("category_id = ? AND deleted_at IS NULL", categoryId).
+		Pluck("article_id", &ids).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetArticleIdsByCategory error", zap.Error(err))
+		return nil, err
+	}
+	return ids, nil
+}
+
+// MoveArticleCategory 将分类下的所有文章移动到另一个分类
+func (r *articleRepository) MoveArticleCategory(ctx context.Context, fromCategoryId uint, toCategoryId uint) (int, error) {
+	ids, err := r.GetArticleIdsByCategory(ctx, fromCategoryId)
+	if err != nil {
+		return 0, err
+	}
+	result := r.DB(ctx).Table("kb_article").
+		Where("category_id = ?", fromCategoryId).
+		Updates(map[string]interface{}{"category_id": toCategoryId, "updated_at": time.Now()})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.MoveArticleCategory error", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	r.invalidateArticleCache(ctx, ids...)
+	return int(result.RowsAffected), nil
 }
 
 func (r *articleRepository) UpdateArticle(ctx context.Context, article *model.Article) (*model.Article, error) {
@@ -128,6 +376,10 @@ func (r *articleRepository) UpdateArticle(ctx context.Context, article *model.Ar
 		r.logger.WithContext(ctx).Error("ArticleRepository.UpdateArticle error", zap.Error(err))
 		return nil, err
 	}
+	if err := r.saveArticleShares(ctx, article); err != nil {
+		return nil, err
+	}
+	r.invalidateArticleCache(ctx, article.ArticleID)
 	return article, nil
 }
 
@@ -135,11 +387,12 @@ func (r *articleRepository) DeleteArticle(ctx context.Context, id uint) (int, er
 	// 更新文章的 status 字段为已删除状态
 	result := r.DB(ctx).Table("kb_article").
 		Where("article_id = ?", id).
-		Updates(map[string]interface{}{"status": enums.StatusDeleted})
+		Updates(map[string]interface{}{"status": enums.StatusDeleted, "updated_at": time.Now()})
 	if result.Error != nil {
 		r.logger.WithContext(ctx).Error("ArticleRepository.DeleteArticle error", zap.Error(result.Error))
 		return 0, result.Error
 	}
+	r.invalidateArticleCache(ctx, id)
 	return int(result.RowsAffected), nil
 }
 
@@ -147,17 +400,20 @@ func (r *articleRepository) DeleteArticleList(ctx context.Context, ids []uint) (
 	// 更新文章的 status 字段为已删除状态
 	updateResult := r.DB(ctx).Table("kb_article").
 		Where("article_id IN (?)", ids).
-		Update("status", enums.StatusDeleted)
+		Updates(map[string]interface{}{"status": enums.StatusDeleted, "updated_at": time.Now()})
 
 	if updateResult.Error != nil {
 		r.logger.WithContext(ctx).Error("ArticleRepository.DeleteArticleList UpdateStatus error", zap.Error(updateResult.Error))
 		return 0, updateResult.Error
 	}
+	r.invalidateArticleCache(ctx, ids...)
 
 	return int(updateResult.RowsAffected), nil
 }
 
-func (r *articleRepository) GetArticleListByCategory(ctx context.Context, categoryId uint, pageNum int, pageSize int) ([]model.Article, int64, error) {
+// GetArticleListByCategory 分页查询分类下当前用户可以查看的已发布文章，按文章ID排序
+// afterId 大于 0 时按游标查询该ID之后的文章，忽略页码
+func (r *articleRepository) GetArticleListByCategory(ctx context.Context, viewer *model.User, categoryId uint, afterId uint, pageNum int, pageSize int) ([]model.Article, int64, error) {
 	var articles []model.Article
 	var total int64
 
@@ -166,7 +422,8 @@ func (r *articleRepository) GetArticleListByCategory(ctx context.Context, catego
 
 	// 查询总数
 	countResult := r.DB(ctx).Table("kb_article").
-		Where("category_id = ? AND status = ?", categoryId, enums.StatusPublished).
+		Scopes(visibleTo(viewer)).
+		Where("kb_article.category_id = ? AND kb_article.status = ?", categoryId, enums.StatusPublished).
 		Count(&total)
 	if countResult.Error != nil {
 		r.logger.WithContext(ctx).Error("ArticleRepository.GetArticleListByCategory Count error", zap.Error(countResult.Error))
@@ -180,9 +437,16 @@ func (r *articleRepository) GetArticleListByCategory(ctx context.Context, catego
 	}
 
 	// 查询文章列表
-	result := r.DB(ctx).Table("kb_article").
-		Where("category_id = ? AND status = ?", categoryId, enums.StatusPublished).
-		Offset(offset).
+	query := r.DB(ctx).Table("kb_article").
+		Select("kb_article.*").
+		Scopes(visibleTo(viewer)).
+		Where("kb_article.category_id = ? AND kb_article.status = ?", categoryId, enums.StatusPublished)
+	if afterId > 0 {
+		query = query.Where("kb_article.article_id > ?", afterId)
+	} else {
+		query = query.Offset(offset)
+	}
+	result := query.Order("kb_article.article_id asc").
 		Limit(pageSize).
 		Find(&articles)
 	if result.Error != nil {
@@ -195,6 +459,25 @@ func (r *articleRepository) GetArticleListByCategory(ctx context.Context, catego
 
 	return articles, total, nil
 }
+
+// GetVisibleArticlesByIds 批量获取 viewer 可见的已发布文章，不保证顺序，已删除或不可见的文章不在结果中
+func (r *articleRepository) GetVisibleArticlesByIds(ctx context.Context, viewer *model.User, ids []uint) ([]model.Article, error) {
+	var articles []model.Article
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	err := r.DB(ctx).Table("kb_article").
+		Select("kb_article.*").
+		Scopes(visibleTo(viewer)).
+		Where("kb_article.article_id IN ? AND kb_article.status = ?", ids, enums.StatusPublished).
+		Find(&articles).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetVisibleArticlesByIds error", zap.Error(err))
+		return nil, err
+	}
+	return articles, nil
+}
+
 func (r *articleRepository) GetUserArticleList(ctx context.Context, userId string, req *v1.GetUserArticleListReq, pageNum int, pageSize int) ([]model.Article, int64, error) {
 	// 使用 GORM 获取数据库连接
 	db := r.db.WithContext(ctx)
@@ -209,8 +492,13 @@ func (r *articleRepository) GetUserArticleList(ctx context.Context, userId strin
 	if req.CategoryID != 0 {
 		query = query.Where("category_id = ?", req.CategoryID)
 	}
-	if req.Status != -1 {
-		query = query.Where("status =?", req.Status)
+	if req.Draft {
+		query = query.Where("status = ?", enums.StatusDraft)
+	} else {
+		query = query.Where("status <> ?", enums.StatusDraft)
+		if req.Status > 0 {
+			query = query.Where("status =?", req.Status)
+		}
 	}
 	if req.CreatedAt != "" {
 		// 转换字符串到时间类型并比较
@@ -248,14 +536,102 @@ func (r *articleRepository) GetUserArticleList(ctx context.Context, userId strin
 	return articles, total, nil
 }
 
+// GetDueScheduledArticles 查询已到发布时间的定时发布文章
+func (r *articleRepository) GetDueScheduledArticles(ctx context.Context, now time.Time, limit int) ([]model.Article, error) {
+	var articles []model.Article
+	if err := r.DB(ctx).Table("kb_article").
+		Where("status = ? AND publish_at <= ?", enums.StatusScheduled, now).
+		Order("publish_at asc").
+		Limit(limit).
+		Find(&articles).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetDueScheduledArticles error", zap.Error(err))
+		return nil, err
+	}
+	return articles, nil
+}
+
+// GetSearchableArticles 按ID顺序分批查询需要写入es的文章（已发布），afterId 为上一批最后一篇文章的ID
+func (r *articleRepository) GetSearchableArticles(ctx context.Context, afterId uint, limit int) ([]model.Article, error) {
+	var articles []model.Article
+	if err := r.DB(ctx).Table("kb_article").
+		Where("article_id > ? AND status = ?", afterId, enums.StatusPublished).
+		Order("article_id asc").
+		Limit(limit).
+		Find(&articles).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetSearchableArticles error", zap.Error(err))
+		return nil, err
+	}
+	return articles, nil
+}
+
+// GetArticleIdsUpdatedSince 获取指定时间之后有变更的文章ID，包含已删除的文章
+func (r *articleRepository) GetArticleIdsUpdatedSince(ctx context.Context, since time.Time) ([]uint, error) {
+	var ids []uint
+	if err := r.DB(ctx).Table("kb_article").
+		Where("updated_at >= ?", since).
+		Pluck("article_id", &ids).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetArticleIdsUpdatedSince error", zap.Error(err))
+		return nil, err
+	}
+	return ids, nil
+}
+
+// PublishScheduledArticle 将定时发布文章改为已发布，只有状态仍为定时发布时才会更新，返回是否更新成功
+func (r *articleRepository) PublishScheduledArticle(ctx context.Context, id uint) (bool, error) {
+	result := r.DB(ctx).Table("kb_article").
+		Where("article_id = ? AND status = ?", id, enums.StatusScheduled).
+		Updates(map[string]interface{}{"status": enums.StatusPublished, "updated_at": time.Now()})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.PublishScheduledArticle error", zap.Error(result.Error))
+		return false, result.Error
+	}
+	r.invalidateArticleCache(ctx, id)
+	return result.RowsAffected == 1, nil
+}
+
+// GetReviewArticleList 查询待审核文章，collegeId 不为 0 时只查询该学院作者的文章
+func (r *articleRepository) GetReviewArticleList(ctx context.Context, collegeId uint, pageNum int, pageSize int) ([]model.Article, int64, error) {
+	query := r.DB(ctx).Model(&model.Article{}).Where("kb_article.status = ?", enums.StatusPendingReview)
+	if collegeId != 0 {
+		query = query.Joins("JOIN sys_users ON sys_users.user_id = kb_article.user_id").
+			Where("sys_users.college_id = ?", collegeId)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetReviewArticleList Count error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var articles []model.Article
+	offset := (pageNum - 1) * pageSize
+	if err := query.Select("kb_article.*").Order("kb_article.updated_at asc").Offset(offset).Limit(pageSize).Find(&articles).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetReviewArticleList Find error", zap.Error(err))
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
 // GetArticleListByEs es查询
-func (r *Repository) GetArticleListByEs(ctx context.Context, query *elastic.BoolQuery, highlight *elastic.Highlight, from, size int) (*elastic.SearchResult, error) {
-	searchResult, err := r.esClient.Search().
-		Index("kb_article").
+// searchAfter 不为空时从该排序值之后继续查询（游标分页），忽略 from
+func (r *Repository) GetArticleListByEs(ctx context.Context, query *elastic.BoolQuery, highlight *elastic.Highlight, aggs map[string]elastic.Aggregation, sorters []elastic.Sorter, searchAfter []interface{}, from, size int) (*elastic.SearchResult, error) {
+	search := r.esClient.Search().
+		Index(esArticleAlias).
 		Query(query).
-		Highlight(highlight).  // 高亮设置
-		From(from).Size(size). // 分页设置
-		Do(ctx)
+		Highlight(highlight).                                                                   // 高亮设置
+		FetchSourceContext(elastic.NewFetchSourceContext(true).Exclude("attachments.content")). // 附件全文只用于检索
+		SortBy(sorters...).                                                                     // 排序，最后一项为文章ID保证顺序稳定
+		TrackScores(true).                                                                      // 按字段排序时仍返回相关度评分
+		Size(size)                                                                              // 分页设置
+	if len(searchAfter) > 0 {
+		search = search.SearchAfter(searchAfter...)
+	} else {
+		search = search.From(from)
+	}
+	for name, agg := range aggs {
+		search = search.Aggregation(name, agg) // 聚合统计
+	}
+	searchResult, err := search.Do(ctx)
 	r.logger.WithContext(ctx).Info("ArticleRepository.GetArticleListByEs", zap.Any("searchResult", searchResult))
 	if err != nil {
 		r.logger.WithContext(ctx).Error("ArticleRepository.GetArticleListByEs error", zap.Error(err))
@@ -264,9 +640,92 @@ func (r *Repository) GetArticleListByEs(ctx context.Context, query *elastic.Bool
 	return searchResult, nil
 }
 
+// SuggestEsArticles 查询标题联想的文章，suggester 不为空时同时执行拼写纠错建议，只返回文章标题
+func (r *Repository) SuggestEsArticles(ctx context.Context, query elastic.Query, suggester elastic.Suggester, size int) (*elastic.SearchResult, error) {
+	search := r.esClient.Search().
+		Index(esArticleAlias).
+		Query(query).
+		FetchSourceContext(elastic.NewFetchSourceContext(true).Include("title")).
+		Size(size)
+	if suggester != nil {
+		search = search.Suggester(suggester)
+	}
+	searchResult, err := search.Do(ctx)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.SuggestEsArticles error", zap.Error(err))
+		return nil, fmt.Errorf("failed to execute Elasticsearch suggest: %w", err)
+	}
+	return searchResult, nil
+}
+
+// GetSearchSuggestCache 获取缓存的搜索联想结果，未命中时返回 false
+func (r *Repository) GetSearchSuggestCache(ctx context.Context, key string) (string, bool, error) {
+	value, err := r.rdb.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", false, nil
+		}
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetSearchSuggestCache error", zap.Error(err))
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (r *Repository) SetSearchSuggestCache(ctx context.Context, key string, value string, expiration time.Duration) error {
+	if err := r.rdb.Set(ctx, key, value, expiration).Err(); err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.SetSearchSuggestCache error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetEsArticle 由文章生成完整的es文档，包含标签和已提取的附件文本
+func (r *articleRepository) GetEsArticle(ctx context.Context, article *model.Article) (*model.EsArticle, error) {
+	esArticle := model.NewEsArticle(article)
+	var (
+		collegeIds []uint
+		views      []int64
+	)
+	if err := r.DB(ctx).Table("sys_users").
+		Where("user_id = ?", article.UserID).
+		Pluck("college_id", &collegeIds).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetEsArticle college error", zap.Error(err))
+		return nil, err
+	}
+	if len(collegeIds) > 0 {
+		esArticle.CollegeId = collegeIds[0]
+	}
+	if err := r.DB(ctx).Table("kb_article_tag AS at").
+		Joins("JOIN kb_tag t ON t.id = at.tag_id").
+		Where("at.article_id = ?", article.ArticleID).
+		Order("t.id").
+		Pluck("t.tag_name", &esArticle.Tags).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetEsArticle tags error", zap.Error(err))
+		return nil, err
+	}
+	if err := r.DB(ctx).Table("kb_attachment").
+		Select("id AS attachment_id, file_name, content").
+		Where("article_id = ? AND extract_status = ?", article.ArticleID, enums.ExtractDone).
+		Order("id").
+		Scan(&esArticle.Attachments).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetEsArticle attachments error", zap.Error(err))
+		return nil, err
+	}
+	if err := r.DB(ctx).Model(&model.ArticleStat{}).
+		Where("article_id = ?", article.ArticleID).
+		Pluck("views", &views).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetEsArticle views error", zap.Error(err))
+		return nil, err
+	}
+	if len(views) > 0 {
+		esArticle.Views = views[0]
+	}
+	return esArticle, nil
+}
+
 func (r *Repository) CreateEsArticle(ctx context.Context, article *model.EsArticle) error {
 	_, err := r.esClient.Index().
-		Index("kb_article").
+		Index(esArticleAlias).
 		Id(fmt.Sprintf("%d", article.ArticleID)).
 		BodyJson(article).
 		Do(ctx)
@@ -280,7 +739,7 @@ func (r *Repository) CreateEsArticle(ctx context.Context, article *model.EsArtic
 
 func (r *Repository) UpdateEsArticle(ctx context.Context, article *model.EsArticle) error {
 	_, err := r.esClient.Update().
-		Index("kb_article").
+		Index(esArticleAlias).
 		Id(fmt.Sprintf("%d", article.ArticleID)).
 		Doc(article).
 		Do(ctx)
@@ -293,11 +752,11 @@ func (r *Repository) UpdateEsArticle(ctx context.Context, article *model.EsArtic
 }
 func (r *Repository) DeleteEsArticle(ctx context.Context, articleId uint) error {
 	_, err := r.esClient.Delete().
-		Index("kb_article").
+		Index(esArticleAlias).
 		Id(fmt.Sprintf("%d", articleId)).
 		Do(ctx)
 	r.logger.WithContext(ctx).Info("ArticleRepository.DeleteEsArticle", zap.Any("articleId", articleId))
-	if err != nil {
+	if err != nil && !elastic.IsNotFound(err) {
 		r.logger.WithContext(ctx).Error("ArticleRepository.DeleteEsArticle error", zap.Error(err))
 		return fmt.Errorf("failed to delete Elasticsearch document: %w", err)
 	}
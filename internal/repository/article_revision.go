@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	v1 "projectName/api/v1"
+	"projectName/internal/model"
+)
+
+type ArticleRevisionRepository interface {
+	CreateRevision(ctx context.Context, revision *model.ArticleRevision) error
+	GetMaxVersion(ctx context.Context, articleId uint) (int, error)
+	GetRevision(ctx context.Context, articleId uint, version int) (*model.ArticleRevision, error)
+	GetRevisionList(ctx context.Context, articleId uint, pageNum int, pageSize int) ([]model.ArticleRevision, int64, error)
+}
+
+func NewArticleRevisionRepository(
+	repository *Repository,
+) ArticleRevisionRepository {
+	return &articleRevisionRepository{
+		Repository: repository,
+	}
+}
+
+type articleRevisionRepository struct {
+	*Repository
+}
+
+func (r *articleRevisionRepository) CreateRevision(ctx context.Context, revision *model.ArticleRevision) error {
+	if err := r.DB(ctx).Table("kb_article_revision").Create(revision).Error; err != nil {
+		r.logger.WithContext(ctx).Error("articleRevisionRepository.CreateRevision error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetMaxVersion 获取文章当前最大版本号，没有历史版本时返回 0
+func (r *articleRevisionRepository) GetMaxVersion(ctx context.Context, articleId uint) (int, error) {
+	var version int
+	if err := r.DB(ctx).Table("kb_article_revision").
+		Where("article_id = ?", articleId).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error; err != nil {
+		r.logger.WithContext(ctx).Error("articleRevisionRepository.GetMaxVersion error", zap.Error(err))
+		return 0, err
+	}
+	return version, nil
+}
+
+func (r *articleRevisionRepository) GetRevision(ctx context.Context, articleId uint, version int) (*model.ArticleRevision, error) {
+	var revision model.ArticleRevision
+	if err := r.DB(ctx).Table("kb_article_revision").
+		Where("article_id = ? AND version = ?", articleId, version).
+		First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		r.logger.WithContext(ctx).Error("articleRevisionRepository.GetRevision error", zap.Error(err))
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetRevisionList 按版本号倒序分页获取历史版本，不查询正文
+func (r *articleRevisionRepository) GetRevisionList(ctx context.Context, articleId uint, pageNum int, pageSize int) ([]model.ArticleRevision, int64, error) {
+	query := r.DB(ctx).Table("kb_article_revision").Where("article_id = ?", articleId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("articleRevisionRepository.GetRevisionList Count error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var revisions []model.ArticleRevision
+	offset := (pageNum - 1) * pageSize
+	if err := query.Omit("content", "uploaded_files").
+		Order("version desc").Offset(offset).Limit(pageSize).
+		Find(&revisions).Error; err != nil {
+		r.logger.WithContext(ctx).Error("articleRevisionRepository.GetRevisionList Find error", zap.Error(err))
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
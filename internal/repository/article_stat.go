@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"projectName/internal/model"
+	"strconv"
+	"time"
+)
+
+const (
+	articleViewPendingKey  = "article:views:pending"        // 尚未写入数据库的浏览量，文章ID -> 增量
+	articleViewFlushingKey = "article:views:flushing"       // 正在写入数据库的浏览量
+	articleViewBatchKey    = "article:views:flushing:batch" // 正在写入数据库的浏览量的批次ID
+	articleViewFlushLock   = "article:views:flush:lock"     // 写入数据库的锁，多个 task 实例同时运行时只有一个写入
+	articleViewFlushTTL    = 5 * time.Minute                // 写入锁的过期时间，写入失败时过期后重试
+	articleViewBatchTTL    = 7 * 24 * time.Hour             // 已写入批次的保留时间，需覆盖写入失败后重试的间隔
+	articleViewDailyTTL    = 8 * 24 * time.Hour             // 每日浏览量排行的保留时间，需覆盖周排行的天数
+	articleViewUnionTTL    = 5 * time.Minute                // 多日浏览量排行合并结果的缓存时间
+)
+
+// clearFlushingScript 仍持有写入锁时删除已取出的浏览量并释放锁，锁已过期时由取得锁的实例重试同一批次
+var clearFlushingScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1], KEYS[2], KEYS[3])
+end
+return 0
+`)
+
+func articleViewedKey(articleId uint, viewerId string) string {
+	return fmt.Sprintf("article:viewed:%d:%s", articleId, viewerId)
+}
+
+func articleViewDailyKey(day time.Time) string {
+	return "article:views:daily:" + day.Format("20060102")
+}
+
+type ArticleStatRepository interface {
+	RecordView(ctx context.Context, articleId uint, viewerId string, window time.Duration) (bool, error)
+	GetViews(ctx context.Context, articleIds []uint) (map[uint]int64, error)
+	GetHotArticleIds(ctx context.Context, days int, limit int) ([]uint, error)
+	TakePendingViews(ctx context.Context, owner string) (string, map[uint]int64, error)
+	AddViews(ctx context.Context, batchId string, views map[uint]int64) (bool, error)
+	ClearFlushingViews(ctx context.Context, owner string) error
+}
+
+func NewArticleStatRepository(
+	repository *Repository,
+) ArticleStatRepository {
+	return &articleStatRepository{
+		Repository: repository,
+	}
+}
+
+type articleStatRepository struct {
+	*Repository
+}
+
+// RecordView 记录一次浏览，同一用户在 window 时间内重复浏览只计一次，返回是否计入浏览量
+func (r *articleStatRepository) RecordView(ctx context.Context, articleId uint, viewerId string, window time.Duration) (bool, error) {
+	ok, err := r.rdb.SetNX(ctx, articleViewedKey(articleId, viewerId), 1, window).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.RecordView SetNX error", zap.Error(err))
+		return false, err
+	}
+	if !ok {
+		return false, nil
+	}
+	field := strconv.FormatUint(uint64(articleId), 10)
+	dailyKey := articleViewDailyKey(time.Now())
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, articleViewPendingKey, field, 1)
+		pipe.ZIncrBy(ctx, dailyKey, 1, field)
+		pipe.Expire(ctx, dailyKey, articleViewDailyTTL)
+		return nil
+	})
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.RecordView incr error", zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// GetViews 批量获取文章的浏览量，包含尚未写入数据库的部分，没有浏览记录的文章不在结果中
+func (r *articleStatRepository) GetViews(ctx context.Context, articleIds []uint) (map[uint]int64, error) {
+	views := make(map[uint]int64, len(articleIds))
+	if len(articleIds) == 0 {
+		return views, nil
+	}
+	var stats []model.ArticleStat
+	if err := r.DB(ctx).Where("article_id IN ?", articleIds).Find(&stats).Error; err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.GetViews error", zap.Error(err))
+		return nil, err
+	}
+	for _, stat := range stats {
+		views[stat.ArticleID] = stat.Views
+	}
+	fields := make([]string, 0, len(articleIds))
+	for _, articleId := range articleIds {
+		fields = append(fields, strconv.FormatUint(uint64(articleId), 10))
+	}
+	for _, key := range []string{articleViewPendingKey, articleViewFlushingKey} {
+		values, err := r.rdb.HMGet(ctx, key, fields...).Result()
+		if err != nil {
+			// 未写入数据库的浏览量只影响展示，redis 不可用时返回数据库中的浏览量
+			r.logger.WithContext(ctx).Warn("ArticleStatRepository.GetViews HMGet error", zap.Error(err))
+			break
+		}
+		for i, value := range values {
+			s, ok := value.(string)
+			if !ok {
+				continue
+			}
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				views[articleIds[i]] += n
+			}
+		}
+	}
+	return views, nil
+}
+
+// GetHotArticleIds 按最近 days 天（含当天）的浏览量获取排行前 limit 的文章ID
+func (r *articleStatRepository) GetHotArticleIds(ctx context.Context, days int, limit int) ([]uint, error) {
+	now := time.Now()
+	key := articleViewDailyKey(now)
+	if days > 1 {
+		key = fmt.Sprintf("article:views:last%d:%s", days, now.Format("20060102"))
+		exists, err := r.rdb.Exists(ctx, key).Result()
+		if err != nil {
+			r.logger.WithContext(ctx).Error("ArticleStatRepository.GetHotArticleIds Exists error", zap.Error(err))
+			return nil, err
+		}
+		if exists == 0 {
+			keys := make([]string, 0, days)
+			for i := 0; i < days; i++ {
+				keys = append(keys, articleViewDailyKey(now.AddDate(0, 0, -i)))
+			}
+			_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.ZUnionStore(ctx, key, &redis.ZStore{Keys: keys, Aggregate: "SUM"})
+				pipe.Expire(ctx, key, articleViewUnionTTL)
+				return nil
+			})
+			if err != nil {
+				r.logger.WithContext(ctx).Error("ArticleStatRepository.GetHotArticleIds ZUnionStore error", zap.Error(err))
+				return nil, err
+			}
+		}
+	}
+	members, err := r.rdb.ZRevRange(ctx, key, 0, int64(limit-1)).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.GetHotArticleIds ZRevRange error", zap.Error(err))
+		return nil, err
+	}
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// TakePendingViews 以 owner 取得写入锁后取出待写入数据库的浏览量及其批次ID，未取得写入锁或没有浏览量时返回空
+// 上次写入失败时重新返回上次取出的浏览量和批次ID，写入数据库后需调用 ClearFlushingViews
+func (r *articleStatRepository) TakePendingViews(ctx context.Context, owner string) (string, map[uint]int64, error) {
+	ok, err := r.rdb.SetNX(ctx, articleViewFlushLock, owner, articleViewFlushTTL).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.TakePendingViews lock error", zap.Error(err))
+		return "", nil, err
+	}
+	if !ok {
+		return "", nil, nil
+	}
+	exists, err := r.rdb.Exists(ctx, articleViewFlushingKey).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.TakePendingViews Exists error", zap.Error(err))
+		return "", nil, err
+	}
+	if exists == 0 {
+		// 只有持有写入锁时才会删除 pending，检查后 pending 不会消失
+		if exists, err = r.rdb.Exists(ctx, articleViewPendingKey).Result(); err != nil {
+			r.logger.WithContext(ctx).Error("ArticleStatRepository.TakePendingViews Exists error", zap.Error(err))
+			return "", nil, err
+		}
+		if exists == 0 {
+			return "", nil, releaseLockScript.Run(ctx, r.rdb, []string{articleViewFlushLock}, owner).Err()
+		}
+		// 以 owner 作为本批次的ID
+		_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Rename(ctx, articleViewPendingKey, articleViewFlushingKey)
+			pipe.Set(ctx, articleViewBatchKey, owner, 0)
+			return nil
+		})
+		if err != nil {
+			r.logger.WithContext(ctx).Error("ArticleStatRepository.TakePendingViews Rename error", zap.Error(err))
+			return "", nil, err
+		}
+	}
+	batchId, err := r.rdb.Get(ctx, articleViewBatchKey).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.TakePendingViews Get batch error", zap.Error(err))
+		return "", nil, err
+	}
+	values, err := r.rdb.HGetAll(ctx, articleViewFlushingKey).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.TakePendingViews HGetAll error", zap.Error(err))
+		return "", nil, err
+	}
+	views := make(map[uint]int64, len(values))
+	for field, value := range values {
+		id, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			continue
+		}
+		views[uint(id)] = n
+	}
+	return batchId, views, nil
+}
+
+// AddViews 将一批浏览量增量累加到数据库，需在事务中调用，同一批次只会累加一次，返回是否累加
+func (r *articleStatRepository) AddViews(ctx context.Context, batchId string, views map[uint]int64) (bool, error) {
+	result := r.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ArticleViewBatch{BatchId: batchId})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.AddViews batch error", zap.Error(result.Error))
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// 该批次已写入数据库，上次清除 redis 失败
+		return false, nil
+	}
+	for articleId, n := range views {
+		stat := model.ArticleStat{ArticleID: articleId, Views: n}
+		err := r.DB(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "article_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"views":      gorm.Expr("kb_article_stat.views + ?", n),
+				"updated_at": time.Now(),
+			}),
+		}).Create(&stat).Error
+		if err != nil {
+			r.logger.WithContext(ctx).Error("ArticleStatRepository.AddViews error", zap.Uint("articleId", articleId), zap.Error(err))
+			return false, err
+		}
+	}
+	err := r.DB(ctx).Where("created_at < ?", time.Now().Add(-articleViewBatchTTL)).Delete(&model.ArticleViewBatch{}).Error
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.AddViews delete batch error", zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// ClearFlushingViews 浏览量写入数据库后删除已取出的浏览量并释放 owner 持有的写入锁
+func (r *articleStatRepository) ClearFlushingViews(ctx context.Context, owner string) error {
+	keys := []string{articleViewFlushLock, articleViewFlushingKey, articleViewBatchKey}
+	deleted, err := clearFlushingScript.Run(ctx, r.rdb, keys, owner).Int()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleStatRepository.ClearFlushingViews error", zap.Error(err))
+		return err
+	}
+	if deleted == 0 {
+		r.logger.WithContext(ctx).Warn("ArticleStatRepository.ClearFlushingViews lock expired", zap.String("owner", owner))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestArticleStatRepository_FlushViews 浏览量写入数据库的完整流程，包括写入失败后的重试
+func TestArticleStatRepository_FlushViews(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	repo := NewArticleStatRepository(r)
+	const window = time.Hour
+
+	recordViews := []struct {
+		articleId uint
+		viewerId  string
+		want      bool
+	}{
+		{1, "u1", true},
+		{1, "u1", false},
+		{1, "u2", true},
+		{2, "u1", true},
+	}
+	for _, v := range recordViews {
+		counted, err := repo.RecordView(ctx, v.articleId, v.viewerId, window)
+		require.NoError(t, err)
+		assert.Equal(t, v.want, counted, "article %d viewer %s", v.articleId, v.viewerId)
+	}
+	assertViews := func(want map[uint]int64) {
+		t.Helper()
+		views, err := repo.GetViews(ctx, []uint{1, 2, 3})
+		require.NoError(t, err)
+		assert.Equal(t, want, views)
+	}
+	flush := func(owner string, batchId string, views map[uint]int64) bool {
+		t.Helper()
+		var applied bool
+		err := r.Transaction(ctx, func(ctx context.Context) error {
+			var err error
+			applied, err = repo.AddViews(ctx, batchId, views)
+			return err
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo.ClearFlushingViews(ctx, owner))
+		return applied
+	}
+	assertViews(map[uint]int64{1: 2, 2: 1})
+
+	batchId, views, err := repo.TakePendingViews(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", batchId)
+	assert.Equal(t, map[uint]int64{1: 2, 2: 1}, views)
+
+	// 其他实例未取得写入锁
+	batchId, views, err = repo.TakePendingViews(ctx, "b")
+	require.NoError(t, err)
+	assert.Empty(t, batchId)
+	assert.Nil(t, views)
+
+	// 写入期间的新浏览记入 pending，已取出的浏览量仍计入展示
+	_, err = repo.RecordView(ctx, 1, "u3", window)
+	require.NoError(t, err)
+	assertViews(map[uint]int64{1: 3, 2: 1})
+
+	// 写入数据库失败，锁过期后由其他实例重新取出同一批次，而不是新的 pending
+	require.NoError(t, r.rdb.Del(ctx, articleViewFlushLock).Err())
+	batchId, views, err = repo.TakePendingViews(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "a", batchId)
+	assert.Equal(t, map[uint]int64{1: 2, 2: 1}, views)
+	assert.True(t, flush("b", batchId, views))
+	assertViews(map[uint]int64{1: 3, 2: 1})
+
+	batchId, views, err = repo.TakePendingViews(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, "c", batchId)
+	assert.Equal(t, map[uint]int64{1: 1}, views)
+
+	// 写入数据库后锁已过期并被其他实例取得：原实例不能清除，重试同一批次时不会重复累加
+	var applied bool
+	err = r.Transaction(ctx, func(ctx context.Context) error {
+		applied, err = repo.AddViews(ctx, batchId, views)
+		return err
+	})
+	require.NoError(t, err)
+	assert.True(t, applied)
+	require.NoError(t, r.rdb.Set(ctx, articleViewFlushLock, "d", articleViewFlushTTL).Err())
+	require.NoError(t, repo.ClearFlushingViews(ctx, "c"))
+	owner, err := r.rdb.Get(ctx, articleViewFlushLock).Result()
+	require.NoError(t, err)
+	assert.Equal(t, "d", owner)
+
+	batchId, views, err = repo.TakePendingViews(ctx, "e")
+	require.NoError(t, err)
+	assert.Empty(t, views)
+	require.NoError(t, r.rdb.Del(ctx, articleViewFlushLock).Err())
+	batchId, views, err = repo.TakePendingViews(ctx, "e")
+	require.NoError(t, err)
+	assert.Equal(t, "c", batchId)
+	assert.False(t, flush("e", batchId, views))
+	assertViews(map[uint]int64{1: 3, 2: 1})
+
+	// 没有待写入的浏览量时返回空并释放锁
+	batchId, views, err = repo.TakePendingViews(ctx, "f")
+	require.NoError(t, err)
+	assert.Empty(t, batchId)
+	assert.Nil(t, views)
+	exists, err := r.rdb.Exists(ctx, articleViewFlushLock, articleViewPendingKey, articleViewFlushingKey, articleViewBatchKey).Result()
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+
+	ids, err := repo.GetHotArticleIds(ctx, 7, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, ids)
+}
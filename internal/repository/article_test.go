@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetEsArticle 私有文章同样写入es，由文档中的可见范围字段过滤
+func TestGetEsArticle(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	r := NewArticleRepository(repo, viper.New())
+	require.NoError(t, repo.db.Create(&model.User{UserId: "author", Phone: "1", RoleType: enums.COMMON_USER, CollegeId: 3}).Error)
+	article := &model.Article{
+		Title:         "private",
+		UserID:        "author",
+		Status:        enums.StatusPublished,
+		UploadedFiles: []byte(`[{"attachmentId":1}]`),
+	}
+	article.SetVisibility(model.Visibility{Scope: enums.VisibleRole, MinRole: enums.SCHOOL_ADMIN, SharedUserIds: []string{"u1"}})
+	require.NoError(t, repo.db.Create(article).Error)
+	require.True(t, article.IsSearchable())
+	tags := []model.Tag{{TagName: "go"}, {TagName: "db"}}
+	require.NoError(t, repo.db.Create(&tags).Error)
+	for _, tag := range tags {
+		require.NoError(t, repo.db.Create(&model.ArticleTag{ArticleID: article.ArticleID, TagId: tag.Id}).Error)
+	}
+	require.NoError(t, repo.db.Create(&[]model.Attachment{
+		{UserId: "author", ArticleID: article.ArticleID, FileName: "a.pdf", StorageKey: "a", ExtractStatus: enums.ExtractDone, Content: "text"},
+		{UserId: "author", ArticleID: article.ArticleID, FileName: "b.pdf", StorageKey: "b"},
+	}).Error)
+	require.NoError(t, repo.db.Create(&model.ArticleStat{ArticleID: article.ArticleID, Views: 7}).Error)
+
+	esArticle, err := r.GetEsArticle(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, enums.VisibleRole, esArticle.Visibility)
+	assert.Equal(t, enums.SCHOOL_ADMIN, esArticle.MinRole)
+	assert.Equal(t, []string{"u1"}, esArticle.SharedUserIds)
+	assert.Equal(t, uint(3), esArticle.CollegeId)
+	assert.Equal(t, []string{"go", "db"}, esArticle.Tags)
+	// 只写入已提取文本的附件
+	require.Len(t, esArticle.Attachments, 1)
+	assert.Equal(t, "a.pdf", esArticle.Attachments[0].FileName)
+	assert.True(t, esArticle.UploadedFile)
+	assert.Equal(t, int64(7), esArticle.Views)
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *model.Attachment) error
+	GetAttachmentById(ctx context.Context, id uint) (*model.Attachment, error)
+	GetAttachmentsByIds(ctx context.Context, ids []uint) ([]model.Attachment, error)
+	GetStorageKeyByChecksum(ctx context.Context, checksum string) (string, error)
+	BindArticle(ctx context.Context, articleId uint, ids []uint) error
+	GetPendingExtractAttachments(ctx context.Context, maxAttempts int, limit int) ([]model.Attachment, error)
+	GetExtractedContentByChecksum(ctx context.Context, checksum string) (string, bool, error)
+	UpdateExtractResult(ctx context.Context, attachment *model.Attachment) error
+}
+
+func NewAttachmentRepository(
+	repository *Repository,
+) AttachmentRepository {
+	return &attachmentRepository{
+		Repository: repository,
+	}
+}
+
+type attachmentRepository struct {
+	*Repository
+}
+
+func (r *attachmentRepository) CreateAttachment(ctx context.Context, attachment *model.Attachment) error {
+	if err := r.DB(ctx).Create(attachment).Error; err != nil {
+		r.logger.WithContext(ctx).Error("attachmentRepository.CreateAttachment error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *attachmentRepository) GetAttachmentById(ctx context.Context, id uint) (*model.Attachment, error) {
+	var attachment model.Attachment
+	if err := r.DB(ctx).Omit("content").Where("id = ?", id).First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		r.logger.WithContext(ctx).Error("attachmentRepository.GetAttachmentById error", zap.Error(err))
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) GetAttachmentsByIds(ctx context.Context, ids []uint) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+	if err := r.DB(ctx).Omit("content").Where("id IN (?)", ids).Find(&attachments).Error; err != nil {
+		r.logger.WithContext(ctx).Error("attachmentRepository.GetAttachmentsByIds error", zap.Error(err))
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// GetStorageKeyByChecksum 查找相同内容文件已有的存储路径，没有时返回空字符串
+func (r *attachmentRepository) GetStorageKeyByChecksum(ctx context.Context, checksum string) (string, error) {
+	var attachments []model.Attachment
+	if err := r.DB(ctx).Omit("content").Where("checksum = ?", checksum).Limit(1).Find(&attachments).Error; err != nil {
+		r.logger.WithContext(ctx).Error("attachmentRepository.GetStorageKeyByChecksum error", zap.Error(err))
+		return "", err
+	}
+	if len(attachments) == 0 {
+		return "", nil
+	}
+	return attachments[0].StorageKey, nil
+}
+
+// BindArticle 将附件关联到文章
+func (r *attachmentRepository) BindArticle(ctx context.Context, articleId uint, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.DB(ctx).Model(&model.Attachment{}).
+		Where("id IN (?)", ids).
+		Update("article_id", articleId).Error; err != nil {
+		r.logger.WithContext(ctx).Error("attachmentRepository.BindArticle error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetPendingExtractAttachments 获取待提取文本和可重试的附件
+func (r *attachmentRepository) GetPendingExtractAttachments(ctx context.Context, maxAttempts int, limit int) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	if err := r.DB(ctx).Omit("content").
+		Where("extract_status = ? OR (extract_status = ? AND extract_attempts < ?)",
+			enums.ExtractPending, enums.ExtractFailed, maxAttempts).
+		Order("id").
+		Limit(limit).
+		Find(&attachments).Error; err != nil {
+		r.logger.WithContext(ctx).Error("attachmentRepository.GetPendingExtractAttachments error", zap.Error(err))
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// GetExtractedContentByChecksum 查找相同内容文件已提取的文本，相同文件无需重复提取
+func (r *attachmentRepository) GetExtractedContentByChecksum(ctx context.Context, checksum string) (string, bool, error) {
+	var attachments []model.Attachment
+	if err := r.DB(ctx).Select("content").
+		Where("checksum = ? AND extract_status = ?", checksum, enums.ExtractDone).
+		Limit(1).
+		Find(&attachments).Error; err != nil {
+		r.logger.WithContext(ctx).Error("attachmentRepository.GetExtractedContentByChecksum error", zap.Error(err))
+		return "", false, err
+	}
+	if len(attachments) == 0 {
+		return "", false, nil
+	}
+	return attachments[0].Content, true, nil
+}
+
+func (r *attachmentRepository) UpdateExtractResult(ctx context.Context, attachment *model.Attachment) error {
+	if err := r.DB(ctx).Model(&model.Attachment{}).
+		Where("id = ?", attachment.Id).
+		Updates(map[string]interface{}{
+			"extract_status":   attachment.ExtractStatus,
+			"extract_attempts": attachment.ExtractAttempts,
+			"content":          attachment.Content,
+		}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("attachmentRepository.UpdateExtractResult error", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"math/rand"
+	v1 "projectName/api/v1"
+	"time"
+)
+
+const (
+	articleCacheTTL  = 10 * time.Minute // 文章缓存时间
+	categoryCacheTTL = 30 * time.Minute // 分类缓存时间
+	notFoundCacheTTL = time.Minute      // 不存在的数据的缓存时间，避免反复查询不存在的ID
+	cacheTTLJitter   = 0.1              // 缓存时间的随机浮动比例，避免大量缓存同时过期
+
+	cacheNotFound    = "null" // 数据不存在时缓存的值
+	categoryCacheKey = "cache:category:all"
+)
+
+func articleCacheKey(id uint) string {
+	return fmt.Sprintf("cache:article:%d", id)
+}
+
+// cacheReadThrough 先读缓存，未命中时查询数据库并写入缓存，结果写入 dest
+// 相同缓存键的并发查询通过 singleflight 合并为一次，load 返回 v1.ErrNotFound 时缓存空值
+// 事务中的查询可能读到未提交的数据，直接查询数据库且不写入缓存；redis 不可用时直接查询数据库
+func (r *Repository) cacheReadThrough(ctx context.Context, key string, ttl time.Duration, dest interface{}, load func(ctx context.Context) (interface{}, error)) error {
+	if r.inTransaction(ctx) {
+		value, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, dest)
+	}
+
+	cached, err := r.rdb.Get(ctx, key).Bytes()
+	if err == nil {
+		if string(cached) == cacheNotFound {
+			return v1.ErrNotFound
+		}
+		if err = json.Unmarshal(cached, dest); err == nil {
+			return nil
+		}
+		r.logger.WithContext(ctx).Warn("Repository.cacheReadThrough Unmarshal error", zap.String("key", key), zap.Error(err))
+	} else if !errors.Is(err, redis.Nil) {
+		r.logger.WithContext(ctx).Warn("Repository.cacheReadThrough Get error", zap.String("key", key), zap.Error(err))
+	}
+
+	value, err, _ := r.sf.Do(key, func() (interface{}, error) {
+		value, err := load(ctx)
+		if errors.Is(err, v1.ErrNotFound) {
+			r.setCache(ctx, key, []byte(cacheNotFound), notFoundCacheTTL)
+			return nil, err
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		r.setCache(ctx, key, data, jitterTTL(ttl))
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(value.([]byte), dest)
+}
+
+func (r *Repository) setCache(ctx context.Context, key string, data []byte, ttl time.Duration) {
+	if err := r.rdb.Set(ctx, key, data, ttl).Err(); err != nil {
+		r.logger.WithContext(ctx).Warn("Repository.setCache error", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// invalidateCache 删除缓存，在事务中时等到事务提交后再删除，避免其他请求在提交前把旧数据重新写入缓存
+func (r *Repository) invalidateCache(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	r.afterCommit(ctx, func() {
+		if err := r.rdb.Del(ctx, keys...).Err(); err != nil {
+			r.logger.WithContext(ctx).Error("Repository.invalidateCache error", zap.Strings("keys", keys), zap.Error(err))
+		}
+	})
+}
+
+// jitterTTL 在缓存时间上增加随机浮动
+func jitterTTL(ttl time.Duration) time.Duration {
+	return ttl + time.Duration(rand.Float64()*cacheTTLJitter*float64(ttl))
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheReadThrough(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	var loads int
+	load := func(value string, err error) func(ctx context.Context) (interface{}, error) {
+		return func(ctx context.Context) (interface{}, error) {
+			loads++
+			return value, err
+		}
+	}
+
+	// 未命中时回源并写入缓存，之后直接读取缓存
+	var got string
+	require.NoError(t, r.cacheReadThrough(ctx, "k1", time.Minute, &got, load("v1", nil)))
+	require.NoError(t, r.cacheReadThrough(ctx, "k1", time.Minute, &got, load("v2", nil)))
+	assert.Equal(t, "v1", got)
+	assert.Equal(t, 1, loads)
+	ttl := r.rdb.TTL(ctx, "k1").Val()
+	assert.True(t, ttl >= time.Minute && ttl <= time.Minute+time.Duration(cacheTTLJitter*float64(time.Minute)), ttl)
+
+	// 不存在的数据缓存空值
+	loads = 0
+	err := r.cacheReadThrough(ctx, "k2", time.Minute, &got, load("", v1.ErrNotFound))
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+	err = r.cacheReadThrough(ctx, "k2", time.Minute, &got, load("v2", nil))
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, cacheNotFound, r.rdb.Get(ctx, "k2").Val())
+	assert.Equal(t, notFoundCacheTTL, r.rdb.TTL(ctx, "k2").Val())
+
+	// 其他错误不缓存
+	loads = 0
+	queryErr := errors.New("query failed")
+	err = r.cacheReadThrough(ctx, "k3", time.Minute, &got, load("", queryErr))
+	assert.ErrorIs(t, err, queryErr)
+	assert.Zero(t, r.rdb.Exists(ctx, "k3").Val())
+
+	// 事务中直接查询数据库，不读写缓存
+	loads = 0
+	require.NoError(t, r.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.cacheReadThrough(ctx, "k1", time.Minute, &got, load("tx", nil)); err != nil {
+			return err
+		}
+		return r.cacheReadThrough(ctx, "k4", time.Minute, &got, load("tx", nil))
+	}))
+	assert.Equal(t, "tx", got)
+	assert.Equal(t, 2, loads)
+	assert.Zero(t, r.rdb.Exists(ctx, "k4").Val())
+}
+
+func TestCacheReadThrough_Singleflight(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	var loads int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return "v", nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, r.cacheReadThrough(ctx, "k", time.Minute, &results[i], load))
+		}(i)
+	}
+	// 等待所有请求进入回源查询后再返回结果
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&loads) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads)
+	for _, result := range results {
+		assert.Equal(t, "v", result)
+	}
+}
+
+func TestInvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	set := func() {
+		require.NoError(t, r.rdb.Set(ctx, "k", "v", time.Minute).Err())
+	}
+
+	set()
+	r.invalidateCache(ctx, "k")
+	assert.Zero(t, r.rdb.Exists(ctx, "k").Val())
+
+	// 事务提交后才删除
+	set()
+	require.NoError(t, r.Transaction(ctx, func(ctx context.Context) error {
+		r.invalidateCache(ctx, "k")
+		assert.Equal(t, int64(1), r.rdb.Exists(ctx, "k").Val())
+		return nil
+	}))
+	assert.Zero(t, r.rdb.Exists(ctx, "k").Val())
+
+	// 事务回滚时不删除
+	set()
+	rollback := errors.New("rollback")
+	err := r.Transaction(ctx, func(ctx context.Context) error {
+		r.invalidateCache(ctx, "k")
+		return rollback
+	})
+	assert.ErrorIs(t, err, rollback)
+	assert.Equal(t, int64(1), r.rdb.Exists(ctx, "k").Val())
+}
+
+func TestArticleCache(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	repo := NewArticleRepository(r, viper.New())
+
+	// 不存在的文章缓存空值，创建后清除
+	_, err := repo.GetArticle(ctx, 1)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+	assert.Equal(t, cacheNotFound, r.rdb.Get(ctx, articleCacheKey(1)).Val())
+	article := &model.Article{Title: "t1", UserID: "u1", CategoryID: 1, Status: enums.StatusPublished}
+	id, err := repo.CreateArticle(ctx, article)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+	got, err := repo.GetArticle(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "t1", got.Title)
+
+	// 绕过仓库直接修改时仍读到缓存，通过仓库更新后清除缓存
+	require.NoError(t, r.db.Model(&model.Article{}).Where("article_id = ?", 1).Update("title", "t2").Error)
+	got, err = repo.GetArticle(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "t1", got.Title)
+	article.Title = "t3"
+	_, err = repo.UpdateArticle(ctx, article)
+	require.NoError(t, err)
+	got, err = repo.GetArticle(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "t3", got.Title)
+
+	_, err = repo.DeleteArticle(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, r.rdb.Exists(ctx, articleCacheKey(1)).Val())
+}
+
+func TestCategoryCache(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	require.NoError(t, r.db.AutoMigrate(&model.Category{}))
+	require.NoError(t, r.db.Exec("CREATE VIEW view_category_tree AS "+
+		"SELECT category_id, category_name, parent_id, 0 AS level FROM kb_category WHERE is_deleted = 0").Error)
+	repo := NewArticleRepository(r, viper.New())
+
+	c1 := &model.Category{CategoryName: "c1"}
+	require.NoError(t, repo.CreateCategory(ctx, c1))
+	got, err := repo.GetCategory(ctx, c1.CId)
+	require.NoError(t, err)
+	assert.Equal(t, "c1", got.CategoryName)
+	assert.Equal(t, int64(1), r.rdb.Exists(ctx, categoryCacheKey).Val())
+
+	// 新建分类后清除整体缓存，单个分类和批量查询都能读到
+	c2 := &model.Category{CategoryName: "c2"}
+	require.NoError(t, repo.CreateCategory(ctx, c2))
+	assert.Zero(t, r.rdb.Exists(ctx, categoryCacheKey).Val())
+	categories, err := repo.GetCategoriesByIds(ctx, []uint{c2.CId, 999})
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, "c2", categories[0].CategoryName)
+
+	_, err = repo.GetCategory(ctx, 999)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+
+	c2.CategoryName = "c2-new"
+	require.NoError(t, repo.UpdateCategory(ctx, c2))
+	got, err = repo.GetCategory(ctx, c2.CId)
+	require.NoError(t, err)
+	assert.Equal(t, "c2-new", got.CategoryName)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	v1 "projectName/api/v1"
+	"projectName/internal/model"
+)
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment *model.Comment) error
+	GetCommentById(ctx context.Context, id uint) (*model.Comment, error)
+	GetRootCommentList(ctx context.Context, articleId uint, pageNum int, pageSize int) ([]model.Comment, int64, error)
+	GetRepliesByRootIds(ctx context.Context, rootIds []uint) ([]model.Comment, error)
+	DeleteComment(ctx context.Context, id uint) error
+	CountByArticleIds(ctx context.Context, articleIds []uint) (map[uint]int64, error)
+}
+
+func NewCommentRepository(
+	repository *Repository,
+) CommentRepository {
+	return &commentRepository{
+		Repository: repository,
+	}
+}
+
+type commentRepository struct {
+	*Repository
+}
+
+func (r *commentRepository) CreateComment(ctx context.Context, comment *model.Comment) error {
+	if err := r.DB(ctx).Create(comment).Error; err != nil {
+		r.logger.WithContext(ctx).Error("commentRepository.CreateComment error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *commentRepository) GetCommentById(ctx context.Context, id uint) (*model.Comment, error) {
+	var comment model.Comment
+	if err := r.DB(ctx).Where("id = ?", id).First(&comment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		r.logger.WithContext(ctx).Error("commentRepository.GetCommentById error", zap.Error(err))
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetRootCommentList 分页获取文章的顶层评论，按时间倒序
+func (r *commentRepository) GetRootCommentList(ctx context.Context, articleId uint, pageNum int, pageSize int) ([]model.Comment, int64, error) {
+	query := r.DB(ctx).Model(&model.Comment{}).Where("article_id = ? AND root_id = 0", articleId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("commentRepository.GetRootCommentList Count error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var comments []model.Comment
+	offset := (pageNum - 1) * pageSize
+	if err := query.Order("created_at desc").Offset(offset).Limit(pageSize).Find(&comments).Error; err != nil {
+		r.logger.WithContext(ctx).Error("commentRepository.GetRootCommentList Find error", zap.Error(err))
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// GetRepliesByRootIds 一次查询多个顶层评论下的全部回复，按时间正序
+func (r *commentRepository) GetRepliesByRootIds(ctx context.Context, rootIds []uint) ([]model.Comment, error) {
+	var comments []model.Comment
+	if len(rootIds) == 0 {
+		return comments, nil
+	}
+	if err := r.DB(ctx).Where("root_id IN (?)", rootIds).Order("created_at asc").Find(&comments).Error; err != nil {
+		r.logger.WithContext(ctx).Error("commentRepository.GetRepliesByRootIds error", zap.Error(err))
+		return nil, err
+	}
+	return comments, nil
+}
+
+// DeleteComment 删除评论，删除顶层评论时同时删除其下的回复
+func (r *commentRepository) DeleteComment(ctx context.Context, id uint) error {
+	if err := r.DB(ctx).Where("id = ? OR root_id = ?", id, id).Delete(&model.Comment{}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("commentRepository.DeleteComment error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CountByArticleIds 批量统计文章的评论数
+func (r *commentRepository) CountByArticleIds(ctx context.Context, articleIds []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(articleIds))
+	if len(articleIds) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		ArticleID uint
+		Total     int64
+	}
+	if err := r.DB(ctx).Model(&model.Comment{}).
+		Select("article_id, COUNT(*) AS total").
+		Where("article_id IN (?)", articleIds).
+		Group("article_id").
+		Scan(&rows).Error; err != nil {
+		r.logger.WithContext(ctx).Error("commentRepository.CountByArticleIds error", zap.Error(err))
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ArticleID] = row.Total
+	}
+	return counts, nil
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/olivere/elastic/v7"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"io"
+	"projectName/internal/model"
+	"strconv"
+	"time"
+)
+
+const (
+	esArticleAlias        = "kb_article"          // 文章索引的别名，读写都通过别名进行，重建索引时切换到新版本的索引
+	esArticleTemplate     = "kb_article_template" // 文章索引模板，应用于所有版本的文章索引
+	esArticleIndexPattern = esArticleAlias + "_v*"
+	esFallbackAnalyzer    = "cjk" // 分词插件未安装时使用的内置分词器，按二元组切分中日韩文字
+)
+
+// esAnalyzerConfig 文章索引的分词器配置
+type esAnalyzerConfig struct {
+	index  string // 写入时使用的分词器，如 ik_max_word
+	search string // 查询时使用的分词器，如 ik_smart
+	plugin string // 分词器所属的插件，为空表示使用内置分词器
+}
+
+func newEsAnalyzerConfig(conf *viper.Viper) esAnalyzerConfig {
+	analyzer := esAnalyzerConfig{
+		index:  conf.GetString("data.elasticsearch.analyzer.index"),
+		search: conf.GetString("data.elasticsearch.analyzer.search"),
+		plugin: conf.GetString("data.elasticsearch.analyzer.plugin"),
+	}
+	if analyzer.index == "" {
+		analyzer.index, analyzer.plugin = esFallbackAnalyzer, ""
+	}
+	if analyzer.search == "" {
+		analyzer.search = analyzer.index
+	}
+	return analyzer
+}
+
+// resolveAnalyzer 返回实际使用的写入和查询分词器，插件未安装时退回内置分词器
+func (r *articleRepository) resolveAnalyzer(ctx context.Context) (string, string) {
+	if r.analyzer.plugin == "" {
+		return r.analyzer.index, r.analyzer.search
+	}
+	ok, err := r.esClient.HasPlugin(r.analyzer.plugin)
+	if err != nil || !ok {
+		r.logger.WithContext(ctx).Warn("ArticleRepository analyzer plugin unavailable, use fallback analyzer",
+			zap.String("plugin", r.analyzer.plugin), zap.String("fallback", esFallbackAnalyzer), zap.Error(err))
+		return esFallbackAnalyzer, esFallbackAnalyzer
+	}
+	return r.analyzer.index, r.analyzer.search
+}
+
+// esArticleMapping 文章索引的字段映射，与 model.EsArticle 保持一致
+// 精确匹配的字段使用 keyword，全文字段使用配置的分词器，标题额外提供边输入边搜索的子字段
+func esArticleMapping(indexAnalyzer, searchAnalyzer string) map[string]interface{} {
+	text := map[string]interface{}{"type": "text", "analyzer": indexAnalyzer, "search_analyzer": searchAnalyzer}
+	return map[string]interface{}{
+		"dynamic": false,
+		"properties": map[string]interface{}{
+			"article_id": map[string]interface{}{"type": "long"},
+			"title": map[string]interface{}{
+				"type":            "text",
+				"analyzer":        indexAnalyzer,
+				"search_analyzer": searchAnalyzer,
+				"fields": map[string]interface{}{
+					"suggest": map[string]interface{}{"type": "search_as_you_type", "analyzer": indexAnalyzer},
+				},
+			},
+			"content":            text,
+			"content_short":      text,
+			"user_id":            map[string]interface{}{"type": "keyword"},
+			"category_id":        map[string]interface{}{"type": "long"},
+			"importance":         map[string]interface{}{"type": "integer"},
+			"visible_range":      map[string]interface{}{"type": "keyword"},
+			"visibility":         map[string]interface{}{"type": "keyword"},
+			"min_role":           map[string]interface{}{"type": "integer"},
+			"college_id":         map[string]interface{}{"type": "long"},
+			"shared_user_ids":    map[string]interface{}{"type": "keyword"},
+			"shared_college_ids": map[string]interface{}{"type": "long"},
+			"comment_disabled":   map[string]interface{}{"type": "boolean"},
+			"source_uri":         map[string]interface{}{"type": "keyword", "index": false},
+			"status":             map[string]interface{}{"type": "integer"},
+			"uploaded_file":      map[string]interface{}{"type": "boolean"},
+			"tags":               map[string]interface{}{"type": "keyword"},
+			"views":              map[string]interface{}{"type": "long"},
+			"attachments": map[string]interface{}{
+				"type": "nested",
+				"properties": map[string]interface{}{
+					"attachment_id": map[string]interface{}{"type": "long"},
+					"file_name":     map[string]interface{}{"type": "keyword"},
+					"content":       text,
+				},
+			},
+			"created_at": map[string]interface{}{"type": "date"},
+			"updated_at": map[string]interface{}{"type": "date"},
+		},
+	}
+}
+
+// esAddedArticleFields 模板映射中后续新增的字段，补充到已创建的版本索引上，新增字段无需重建索引
+func esAddedArticleFields() map[string]interface{} {
+	return map[string]interface{}{
+		"properties": map[string]interface{}{
+			"views": map[string]interface{}{"type": "long"},
+		},
+	}
+}
+
+// NewEsArticleIndexName 生成带版本号的文章索引名
+func NewEsArticleIndexName() string {
+	return fmt.Sprintf("%s_v%s", esArticleAlias, time.Now().Format("20060102150405"))
+}
+
+// EnsureEsArticleTemplate 创建或更新文章索引模板，之后创建的文章索引使用模板中的映射
+func (r *articleRepository) EnsureEsArticleTemplate(ctx context.Context) error {
+	indexAnalyzer, searchAnalyzer := r.resolveAnalyzer(ctx)
+	_, err := r.esClient.IndexPutIndexTemplate(esArticleTemplate).
+		BodyJson(map[string]interface{}{
+			"index_patterns": []string{esArticleIndexPattern},
+			"template": map[string]interface{}{
+				"mappings": esArticleMapping(indexAnalyzer, searchAnalyzer),
+			},
+		}).
+		Do(ctx)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.EnsureEsArticleTemplate error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// EnsureEsArticleMapping 更新索引模板，文章索引不存在时创建带版本号的索引并指向别名
+// 已有的版本索引只补充新增的字段，已有字段的映射变更需通过 cmd/reindex 重建索引
+func (r *articleRepository) EnsureEsArticleMapping(ctx context.Context) error {
+	if err := r.EnsureEsArticleTemplate(ctx); err != nil {
+		return err
+	}
+	exists, err := r.esClient.IndexExists(esArticleAlias).Do(ctx)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.EnsureEsArticleMapping exists error", zap.Error(err))
+		return err
+	}
+	if !exists {
+		index := NewEsArticleIndexName()
+		if err = r.CreateEsArticleIndex(ctx, index); err != nil {
+			return err
+		}
+		_, err = r.SwitchEsArticleAlias(ctx, index)
+		return err
+	}
+	indices, err := r.getEsArticleAliasIndices(ctx)
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		return r.migrateLegacyEsArticleIndex(ctx)
+	}
+	if _, err = r.esClient.PutMapping().Index(esArticleAlias).BodyJson(esAddedArticleFields()).Do(ctx); err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.EnsureEsArticleMapping error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// migrateLegacyEsArticleIndex 别名不存在时文章索引是动态映射创建的旧索引，user_id、tags 等字段为 text 类型，
+// 查询使用的 keyword 字段在旧索引上无法匹配，因此按完整映射创建新索引，复制旧索引的文档后切换别名并删除旧索引
+// 调用方需保证迁移期间没有其他写入，es同步任务持有同步锁时调用
+func (r *articleRepository) migrateLegacyEsArticleIndex(ctx context.Context) error {
+	index := NewEsArticleIndexName()
+	if err := r.CreateEsArticleIndex(ctx, index); err != nil {
+		return err
+	}
+	resp, err := r.esClient.Reindex().
+		SourceIndex(esArticleAlias).
+		DestinationIndex(index).
+		WaitForCompletion(true).
+		Refresh("true").
+		Do(ctx)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.migrateLegacyEsArticleIndex error", zap.String("index", index), zap.Error(err))
+		return err
+	}
+	if len(resp.Failures) > 0 {
+		r.logger.WithContext(ctx).Error("ArticleRepository.migrateLegacyEsArticleIndex failed",
+			zap.String("index", index), zap.Int("failed", len(resp.Failures)), zap.Any("first", resp.Failures[0]))
+		return fmt.Errorf("failed to copy %d Elasticsearch documents to %s", len(resp.Failures), index)
+	}
+	if _, err = r.SwitchEsArticleAlias(ctx, index); err != nil {
+		return err
+	}
+	r.logger.WithContext(ctx).Info("ArticleRepository.migrateLegacyEsArticleIndex done",
+		zap.String("index", index), zap.Int64("documents", resp.Created))
+	return nil
+}
+
+// CreateEsArticleIndex 更新索引模板后创建文章索引，索引的映射来自模板
+func (r *articleRepository) CreateEsArticleIndex(ctx context.Context, index string) error {
+	if err := r.EnsureEsArticleTemplate(ctx); err != nil {
+		return err
+	}
+	if _, err := r.esClient.CreateIndex(index).Do(ctx); err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.CreateEsArticleIndex error", zap.String("index", index), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// getEsArticleAliasIndices 获取别名指向的索引，别名不存在时返回空
+func (r *Repository) getEsArticleAliasIndices(ctx context.Context) ([]string, error) {
+	aliases, err := r.esClient.Aliases().Alias(esArticleAlias).Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return nil, nil
+		}
+		r.logger.WithContext(ctx).Error("ArticleRepository.getEsArticleAliasIndices error", zap.Error(err))
+		return nil, err
+	}
+	return aliases.IndicesByAlias(esArticleAlias), nil
+}
+
+// BulkIndexEsArticles 批量写入文章到指定索引
+func (r *Repository) BulkIndexEsArticles(ctx context.Context, index string, articles []*model.EsArticle) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	bulk := r.esClient.Bulk().Index(index)
+	for _, article := range articles {
+		bulk.Add(elastic.NewBulkIndexRequest().Id(strconv.FormatUint(uint64(article.ArticleID), 10)).Doc(article))
+	}
+	resp, err := bulk.Do(ctx)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.BulkIndexEsArticles error", zap.String("index", index), zap.Error(err))
+		return err
+	}
+	if resp.Errors {
+		failed := resp.Failed()
+		r.logger.WithContext(ctx).Error("ArticleRepository.BulkIndexEsArticles failed",
+			zap.String("index", index), zap.Int("failed", len(failed)), zap.Any("first", failed[0].Error))
+		return fmt.Errorf("failed to index %d Elasticsearch documents", len(failed))
+	}
+	return nil
+}
+
+// SwitchEsArticleAlias 原子地将别名切换到新索引，返回原来别名指向的索引
+// 与别名同名的旧索引（由动态映射创建）无法同时存在，会在同一操作中删除
+func (r *Repository) SwitchEsArticleAlias(ctx context.Context, index string) ([]string, error) {
+	oldIndices, err := r.getEsArticleAliasIndices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actions := []elastic.AliasAction{elastic.NewAliasAddAction(esArticleAlias).Index(index)}
+	for _, old := range oldIndices {
+		actions = append(actions, elastic.NewAliasRemoveAction(esArticleAlias).Index(old))
+	}
+	if len(oldIndices) == 0 {
+		legacy, err := r.esClient.IndexExists(esArticleAlias).Do(ctx)
+		if err != nil {
+			r.logger.WithContext(ctx).Error("ArticleRepository.SwitchEsArticleAlias exists error", zap.Error(err))
+			return nil, err
+		}
+		if legacy {
+			actions = append(actions, elastic.NewAliasRemoveIndexAction(esArticleAlias))
+			oldIndices = append(oldIndices, esArticleAlias)
+		}
+	}
+	if _, err = r.esClient.Alias().Action(actions...).Do(ctx); err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.SwitchEsArticleAlias error", zap.String("index", index), zap.Error(err))
+		return nil, err
+	}
+	return oldIndices, nil
+}
+
+// GetEsArticlesByIds 按ID批量获取es中的文章，不存在的文章不会出现在结果中
+func (r *Repository) GetEsArticlesByIds(ctx context.Context, ids []uint) (map[uint]*model.EsArticle, error) {
+	result := make(map[uint]*model.EsArticle, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	mget := r.esClient.Mget()
+	for _, id := range ids {
+		mget.Add(elastic.NewMultiGetItem().Index(esArticleAlias).Id(strconv.FormatUint(uint64(id), 10)).
+			FetchSource(elastic.NewFetchSourceContext(true).Exclude("content", "attachments")))
+	}
+	resp, err := mget.Do(ctx)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("ArticleRepository.GetEsArticlesByIds error", zap.Error(err))
+		return nil, err
+	}
+	for _, doc := range resp.Docs {
+		if !doc.Found || doc.Source == nil {
+			continue
+		}
+		id, err := strconv.ParseUint(doc.Id, 10, 64)
+		if err != nil {
+			continue
+		}
+		var article model.EsArticle
+		if err = json.Unmarshal(doc.Source, &article); err != nil {
+			return nil, err
+		}
+		result[uint(id)] = &article
+	}
+	return result, nil
+}
+
+// ScrollEsArticleIds 遍历es中所有文章的ID
+func (r *Repository) ScrollEsArticleIds(ctx context.Context, fn func(ids []uint) error) error {
+	scroll := r.esClient.Scroll(esArticleAlias).
+		FetchSource(false).
+		Size(1000)
+	defer scroll.Clear(context.Background())
+	for {
+		resp, err := scroll.Do(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			r.logger.WithContext(ctx).Error("ArticleRepository.ScrollEsArticleIds error", zap.Error(err))
+			return err
+		}
+		ids := make([]uint, 0, len(resp.Hits.Hits))
+		for _, hit := range resp.Hits.Hits {
+			if id, err := strconv.ParseUint(hit.Id, 10, 64); err == nil {
+				ids = append(ids, uint(id))
+			}
+		}
+		if err = fn(ids); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"projectName/internal/model"
+	"projectName/pkg/log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewEsAnalyzerConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     esAnalyzerConfig
+	}{
+		{"fallback", nil, esAnalyzerConfig{index: esFallbackAnalyzer, search: esFallbackAnalyzer}},
+		{"search defaults to index", map[string]string{"index": "standard"}, esAnalyzerConfig{index: "standard", search: "standard"}},
+		{"plugin", map[string]string{"index": "ik_max_word", "search": "ik_smart", "plugin": "analysis-ik"}, esAnalyzerConfig{index: "ik_max_word", search: "ik_smart", plugin: "analysis-ik"}},
+		// 未配置写入分词器时插件配置无效
+		{"plugin without index analyzer", map[string]string{"search": "ik_smart", "plugin": "analysis-ik"}, esAnalyzerConfig{index: esFallbackAnalyzer, search: "ik_smart"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := viper.New()
+			for k, v := range tt.settings {
+				conf.Set("data.elasticsearch.analyzer."+k, v)
+			}
+			assert.Equal(t, tt.want, newEsAnalyzerConfig(conf))
+		})
+	}
+}
+
+func TestResolveAnalyzer(t *testing.T) {
+	// 通过集群统计接口返回已安装的插件
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"nodes":{"plugins":[{"name":"analysis-ik"}]}}`))
+	}))
+	t.Cleanup(srv.Close)
+	client, err := elastic.NewClient(elastic.SetURL(srv.URL), elastic.SetSniff(false), elastic.SetHealthcheck(false))
+	require.NoError(t, err)
+	repo := NewRepository(&log.Logger{Logger: zap.NewNop()}, nil, nil, client)
+
+	tests := []struct {
+		name       string
+		analyzer   esAnalyzerConfig
+		wantIndex  string
+		wantSearch string
+	}{
+		{"builtin", esAnalyzerConfig{index: "standard", search: "simple"}, "standard", "simple"},
+		{"plugin installed", esAnalyzerConfig{index: "ik_max_word", search: "ik_smart", plugin: "analysis-ik"}, "ik_max_word", "ik_smart"},
+		{"plugin missing", esAnalyzerConfig{index: "pinyin", search: "pinyin", plugin: "analysis-pinyin"}, esFallbackAnalyzer, esFallbackAnalyzer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &articleRepository{Repository: repo, analyzer: tt.analyzer}
+			index, search := r.resolveAnalyzer(context.Background())
+			assert.Equal(t, tt.wantIndex, index)
+			assert.Equal(t, tt.wantSearch, search)
+		})
+	}
+}
+
+// TestEsArticleMapping 映射须覆盖 es 文档的全部字段，关闭动态映射后未声明的字段无法检索
+func TestEsArticleMapping(t *testing.T) {
+	mapping := esArticleMapping("ik_max_word", "ik_smart")
+	assert.Equal(t, false, mapping["dynamic"])
+	properties := mapping["properties"].(map[string]interface{})
+	assertFieldsMapped(t, reflect.TypeOf(model.EsArticle{}), properties)
+	attachments := properties["attachments"].(map[string]interface{})
+	assert.Equal(t, "nested", attachments["type"])
+	assertFieldsMapped(t, reflect.TypeOf(model.EsAttachment{}), attachments["properties"].(map[string]interface{}))
+
+	title := properties["title"].(map[string]interface{})
+	assert.Equal(t, "ik_max_word", title["analyzer"])
+	assert.Equal(t, "ik_smart", title["search_analyzer"])
+	for _, field := range []string{"user_id", "visibility", "shared_user_ids", "tags"} {
+		assert.Equal(t, "keyword", properties[field].(map[string]interface{})["type"], field)
+	}
+
+	// 补充到已有索引的字段须与模板中的映射一致
+	for field, fieldMapping := range esAddedArticleFields()["properties"].(map[string]interface{}) {
+		assert.Equal(t, properties[field], fieldMapping, field)
+	}
+}
+
+func assertFieldsMapped(t *testing.T, typ reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		assert.Contains(t, properties, name)
+	}
+}
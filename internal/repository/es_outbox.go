@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"projectName/internal/model"
+	"time"
+)
+
+const esSyncLockKey = "es:sync:lock" // es同步的锁，多个 task 实例同时运行时只有一个处理同步任务
+
+// releaseLockScript 锁的值与 owner 一致时才删除，避免锁过期后删除其他实例持有的锁
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// EsOutboxStats es同步任务的积压情况
+type EsOutboxStats struct {
+	Pending         int64      // 待同步的任务数
+	Retrying        int64      // 失败后等待重试的任务数
+	OldestCreatedAt *time.Time // 最早的待同步任务创建时间
+}
+
+type EsOutboxRepository interface {
+	Enqueue(ctx context.Context, articleIds ...uint) error
+	GetDueOutbox(ctx context.Context, now time.Time, limit int) ([]model.EsOutbox, error)
+	DeleteOutbox(ctx context.Context, ids []uint) error
+	MarkOutboxFailed(ctx context.Context, ids []uint, attempts int, nextRetryAt time.Time, lastError string) error
+	GetOutboxStats(ctx context.Context) (*EsOutboxStats, error)
+	AcquireSyncLock(ctx context.Context, owner string, ttl time.Duration) (bool, error)
+	ReleaseSyncLock(ctx context.Context, owner string) error
+}
+
+func NewEsOutboxRepository(
+	repository *Repository,
+) EsOutboxRepository {
+	return &esOutboxRepository{
+		Repository: repository,
+	}
+}
+
+type esOutboxRepository struct {
+	*Repository
+}
+
+// Enqueue 写入文章的es同步任务，需与文章修改在同一事务中调用
+func (r *esOutboxRepository) Enqueue(ctx context.Context, articleIds ...uint) error {
+	if len(articleIds) == 0 {
+		return nil
+	}
+	now := time.Now()
+	outboxes := make([]model.EsOutbox, 0, len(articleIds))
+	for _, articleId := range articleIds {
+		outboxes = append(outboxes, model.EsOutbox{ArticleID: articleId, NextRetryAt: now})
+	}
+	if err := r.DB(ctx).CreateInBatches(&outboxes, 500).Error; err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.Enqueue error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetDueOutbox 按写入顺序获取已到处理时间的同步任务
+func (r *esOutboxRepository) GetDueOutbox(ctx context.Context, now time.Time, limit int) ([]model.EsOutbox, error) {
+	var outboxes []model.EsOutbox
+	if err := r.DB(ctx).
+		Where("next_retry_at <= ?", now).
+		Order("id").
+		Limit(limit).
+		Find(&outboxes).Error; err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.GetDueOutbox error", zap.Error(err))
+		return nil, err
+	}
+	return outboxes, nil
+}
+
+func (r *esOutboxRepository) DeleteOutbox(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.DB(ctx).Where("id IN ?", ids).Delete(&model.EsOutbox{}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.DeleteOutbox error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// MarkOutboxFailed 记录同步失败，推迟到 nextRetryAt 后重试
+func (r *esOutboxRepository) MarkOutboxFailed(ctx context.Context, ids []uint, attempts int, nextRetryAt time.Time, lastError string) error {
+	if len(lastError) > 1024 {
+		lastError = lastError[:1024]
+	}
+	if err := r.DB(ctx).Model(&model.EsOutbox{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"attempts":      attempts,
+			"next_retry_at": nextRetryAt,
+			"last_error":    lastError,
+		}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.MarkOutboxFailed error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *esOutboxRepository) GetOutboxStats(ctx context.Context) (*EsOutboxStats, error) {
+	var stats EsOutboxStats
+	if err := r.DB(ctx).Model(&model.EsOutbox{}).Count(&stats.Pending).Error; err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.GetOutboxStats Count error", zap.Error(err))
+		return nil, err
+	}
+	if stats.Pending == 0 {
+		return &stats, nil
+	}
+	if err := r.DB(ctx).Model(&model.EsOutbox{}).Where("attempts > 0").Count(&stats.Retrying).Error; err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.GetOutboxStats Retrying error", zap.Error(err))
+		return nil, err
+	}
+	var oldest model.EsOutbox
+	if err := r.DB(ctx).Order("id").Limit(1).Find(&oldest).Error; err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.GetOutboxStats Oldest error", zap.Error(err))
+		return nil, err
+	}
+	if oldest.Id != 0 {
+		stats.OldestCreatedAt = &oldest.CreatedAt
+	}
+	return &stats, nil
+}
+
+// AcquireSyncLock 获取es同步的锁，返回是否获取成功
+func (r *esOutboxRepository) AcquireSyncLock(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	ok, err := r.rdb.SetNX(ctx, esSyncLockKey, owner, ttl).Result()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.AcquireSyncLock error", zap.Error(err))
+		return false, err
+	}
+	return ok, nil
+}
+
+// ReleaseSyncLock 释放 owner 持有的es同步锁
+func (r *esOutboxRepository) ReleaseSyncLock(ctx context.Context, owner string) error {
+	if err := releaseLockScript.Run(ctx, r.rdb, []string{esSyncLockKey}, owner).Err(); err != nil {
+		r.logger.WithContext(ctx).Error("esOutboxRepository.ReleaseSyncLock error", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+)
+
+type ArticleLikeRepository interface {
+	Like(ctx context.Context, articleId uint, userId string) (bool, error)
+	Unlike(ctx context.Context, articleId uint, userId string) (bool, error)
+	CountByArticleIds(ctx context.Context, articleIds []uint) (map[uint]int64, error)
+	GetLikedArticleIds(ctx context.Context, userId string, articleIds []uint) (map[uint]bool, error)
+}
+
+func NewArticleLikeRepository(
+	repository *Repository,
+) ArticleLikeRepository {
+	return &articleLikeRepository{
+		Repository: repository,
+	}
+}
+
+type articleLikeRepository struct {
+	*Repository
+}
+
+// Like 点赞文章，已点赞时不重复写入，返回是否新增了点赞
+func (r *articleLikeRepository) Like(ctx context.Context, articleId uint, userId string) (bool, error) {
+	result := r.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ArticleLike{ArticleID: articleId, UserId: userId})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Error("ArticleLikeRepository.Like error", zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Unlike 取消点赞，返回是否删除了点赞
+func (r *articleLikeRepository) Unlike(ctx context.Context, articleId uint, userId string) (bool, error) {
+	result := r.DB(ctx).Where("article_id = ? AND user_id = ?", articleId, userId).Delete(&model.ArticleLike{})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Error("ArticleLikeRepository.Unlike error", zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CountByArticleIds 批量统计文章的点赞数，没有点赞的文章不在结果中
+func (r *articleLikeRepository) CountByArticleIds(ctx context.Context, articleIds []uint) (map[uint]int64, error) {
+	return countByArticleIds(ctx, r.Repository, &model.ArticleLike{}, articleIds)
+}
+
+// GetLikedArticleIds 查询用户点赞过的文章
+func (r *articleLikeRepository) GetLikedArticleIds(ctx context.Context, userId string, articleIds []uint) (map[uint]bool, error) {
+	return userArticleIds(ctx, r.Repository, &model.ArticleLike{}, userId, articleIds)
+}
+
+type FavoriteRepository interface {
+	CreateFolder(ctx context.Context, folder *model.FavoriteFolder) error
+	UpdateFolder(ctx context.Context, folder *model.FavoriteFolder) error
+	DeleteFolder(ctx context.Context, id uint) error
+	GetFolder(ctx context.Context, userId string, id uint) (*model.FavoriteFolder, error)
+	GetFolderByName(ctx context.Context, userId string, name string) (*model.FavoriteFolder, error)
+	GetFolderList(ctx context.Context, userId string) ([]model.FavoriteFolder, error)
+	CountFolders(ctx context.Context, userId string) (int64, error)
+	CountByFolders(ctx context.Context, userId string) (map[uint]int64, error)
+	AddFavorite(ctx context.Context, articleId uint, userId string, folderId uint) (bool, error)
+	RemoveFavorite(ctx context.Context, articleId uint, userId string) (bool, error)
+	MoveFolderFavorites(ctx context.Context, userId string, fromFolderId uint, toFolderId uint) error
+	CountByArticleIds(ctx context.Context, articleIds []uint) (map[uint]int64, error)
+	GetFavoritedArticleIds(ctx context.Context, userId string, articleIds []uint) (map[uint]bool, error)
+	GetFavoriteArticleList(ctx context.Context, viewer *model.User, folderId *uint, pageNum int, pageSize int) ([]model.Article, int64, error)
+}
+
+func NewFavoriteRepository(
+	repository *Repository,
+) FavoriteRepository {
+	return &favoriteRepository{
+		Repository: repository,
+	}
+}
+
+type favoriteRepository struct {
+	*Repository
+}
+
+func (r *favoriteRepository) CreateFolder(ctx context.Context, folder *model.FavoriteFolder) error {
+	if err := r.DB(ctx).Create(folder).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.CreateFolder error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *favoriteRepository) UpdateFolder(ctx context.Context, folder *model.FavoriteFolder) error {
+	if err := r.DB(ctx).Save(folder).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.UpdateFolder error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *favoriteRepository) DeleteFolder(ctx context.Context, id uint) error {
+	if err := r.DB(ctx).Where("id = ?", id).Delete(&model.FavoriteFolder{}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.DeleteFolder error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetFolder 获取用户的收藏夹，不属于该用户的收藏夹视为不存在
+func (r *favoriteRepository) GetFolder(ctx context.Context, userId string, id uint) (*model.FavoriteFolder, error) {
+	var folder model.FavoriteFolder
+	if err := r.DB(ctx).Where("id = ? AND user_id = ?", id, userId).First(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		r.logger.WithContext(ctx).Error("FavoriteRepository.GetFolder error", zap.Error(err))
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *favoriteRepository) GetFolderByName(ctx context.Context, userId string, name string) (*model.FavoriteFolder, error) {
+	var folder model.FavoriteFolder
+	if err := r.DB(ctx).Where("user_id = ? AND name = ?", userId, name).First(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		r.logger.WithContext(ctx).Error("FavoriteRepository.GetFolderByName error", zap.Error(err))
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// GetFolderList 按创建顺序获取用户的收藏夹
+func (r *favoriteRepository) GetFolderList(ctx context.Context, userId string) ([]model.FavoriteFolder, error) {
+	var folders []model.FavoriteFolder
+	if err := r.DB(ctx).Where("user_id = ?", userId).Order("id").Find(&folders).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.GetFolderList error", zap.Error(err))
+		return nil, err
+	}
+	return folders, nil
+}
+
+func (r *favoriteRepository) CountFolders(ctx context.Context, userId string) (int64, error) {
+	var total int64
+	if err := r.DB(ctx).Model(&model.FavoriteFolder{}).Where("user_id = ?", userId).Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.CountFolders error", zap.Error(err))
+		return 0, err
+	}
+	return total, nil
+}
+
+// CountByFolders 统计用户每个收藏夹中的收藏数，包含默认收藏夹
+func (r *favoriteRepository) CountByFolders(ctx context.Context, userId string) (map[uint]int64, error) {
+	var rows []struct {
+		FolderId uint
+		Total    int64
+	}
+	if err := r.DB(ctx).Model(&model.ArticleFavorite{}).
+		Select("folder_id, COUNT(*) AS total").
+		Where("user_id = ?", userId).
+		Group("folder_id").
+		Scan(&rows).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.CountByFolders error", zap.Error(err))
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.FolderId] = row.Total
+	}
+	return counts, nil
+}
+
+// AddFavorite 收藏文章，已收藏时移动到指定的收藏夹，返回是否新增了收藏
+func (r *favoriteRepository) AddFavorite(ctx context.Context, articleId uint, userId string, folderId uint) (bool, error) {
+	result := r.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ArticleFavorite{ArticleID: articleId, UserId: userId, FolderId: folderId})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.AddFavorite error", zap.Error(result.Error))
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+	if err := r.DB(ctx).Model(&model.ArticleFavorite{}).
+		Where("article_id = ? AND user_id = ?", articleId, userId).
+		Update("folder_id", folderId).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.AddFavorite move error", zap.Error(err))
+		return false, err
+	}
+	return false, nil
+}
+
+// RemoveFavorite 取消收藏，返回是否删除了收藏
+func (r *favoriteRepository) RemoveFavorite(ctx context.Context, articleId uint, userId string) (bool, error) {
+	result := r.DB(ctx).Where("article_id = ? AND user_id = ?", articleId, userId).Delete(&model.ArticleFavorite{})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.RemoveFavorite error", zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MoveFolderFavorites 将收藏夹中的收藏移动到另一个收藏夹
+func (r *favoriteRepository) MoveFolderFavorites(ctx context.Context, userId string, fromFolderId uint, toFolderId uint) error {
+	if err := r.DB(ctx).Model(&model.ArticleFavorite{}).
+		Where("user_id = ? AND folder_id = ?", userId, fromFolderId).
+		Update("folder_id", toFolderId).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.MoveFolderFavorites error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CountByArticleIds 批量统计文章的收藏数，没有收藏的文章不在结果中
+func (r *favoriteRepository) CountByArticleIds(ctx context.Context, articleIds []uint) (map[uint]int64, error) {
+	return countByArticleIds(ctx, r.Repository, &model.ArticleFavorite{}, articleIds)
+}
+
+// GetFavoritedArticleIds 查询用户收藏过的文章
+func (r *favoriteRepository) GetFavoritedArticleIds(ctx context.Context, userId string, articleIds []uint) (map[uint]bool, error) {
+	return userArticleIds(ctx, r.Repository, &model.ArticleFavorite{}, userId, articleIds)
+}
+
+// GetFavoriteArticleList 按收藏时间倒序分页查询用户收藏的文章，folderId 为空时查询全部收藏
+// 只返回仍已发布且当前用户可见的文章
+func (r *favoriteRepository) GetFavoriteArticleList(ctx context.Context, viewer *model.User, folderId *uint, pageNum int, pageSize int) ([]model.Article, int64, error) {
+	query := r.DB(ctx).Table("kb_article").
+		Joins("JOIN kb_article_favorite f ON f.article_id = kb_article.article_id AND f.user_id = ?", viewer.UserId).
+		Scopes(visibleTo(viewer)).
+		Where("kb_article.status = ? AND kb_article.deleted_at IS NULL", enums.StatusPublished)
+	if folderId != nil {
+		query = query.Where("f.folder_id = ?", *folderId)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.GetFavoriteArticleList Count error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var articles []model.Article
+	offset := (pageNum - 1) * pageSize
+	if err := query.Select("kb_article.*").
+		Order("f.created_at desc").Order("kb_article.article_id desc").
+		Offset(offset).Limit(pageSize).
+		Find(&articles).Error; err != nil {
+		r.logger.WithContext(ctx).Error("FavoriteRepository.GetFavoriteArticleList Find error", zap.Error(err))
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
+// countByArticleIds 按文章分组统计点赞或收藏记录数
+func countByArticleIds(ctx context.Context, r *Repository, value interface{}, articleIds []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(articleIds))
+	if len(articleIds) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		ArticleID uint
+		Total     int64
+	}
+	if err := r.DB(ctx).Model(value).
+		Select("article_id, COUNT(*) AS total").
+		Where("article_id IN ?", articleIds).
+		Group("article_id").
+		Scan(&rows).Error; err != nil {
+		r.logger.WithContext(ctx).Error("Repository.countByArticleIds error", zap.Error(err))
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ArticleID] = row.Total
+	}
+	return counts, nil
+}
+
+// userArticleIds 查询用户点赞或收藏过的文章
+func userArticleIds(ctx context.Context, r *Repository, value interface{}, userId string, articleIds []uint) (map[uint]bool, error) {
+	result := make(map[uint]bool, len(articleIds))
+	if len(articleIds) == 0 {
+		return result, nil
+	}
+	var ids []uint
+	if err := r.DB(ctx).Model(value).
+		Where("user_id = ? AND article_id IN ?", userId, articleIds).
+		Pluck("article_id", &ids).Error; err != nil {
+		r.logger.WithContext(ctx).Error("Repository.userArticleIds error", zap.Error(err))
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"projectName/internal/model"
+)
+
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *model.Notification) error
+	GetNotificationList(ctx context.Context, userId string, pageNum int, pageSize int) ([]model.Notification, int64, error)
+	CountUnread(ctx context.Context, userId string) (int64, error)
+	MarkRead(ctx context.Context, userId string, ids []uint) error
+}
+
+func NewNotificationRepository(
+	repository *Repository,
+) NotificationRepository {
+	return &notificationRepository{
+		Repository: repository,
+	}
+}
+
+type notificationRepository struct {
+	*Repository
+}
+
+func (r *notificationRepository) CreateNotification(ctx context.Context, notification *model.Notification) error {
+	if err := r.DB(ctx).Table("sys_notifications").Create(notification).Error; err != nil {
+		r.logger.WithContext(ctx).Error("notificationRepository.CreateNotification error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *notificationRepository) GetNotificationList(ctx context.Context, userId string, pageNum int, pageSize int) ([]model.Notification, int64, error) {
+	query := r.DB(ctx).Table("sys_notifications").Where("user_id = ?", userId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("notificationRepository.GetNotificationList Count error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var notifications []model.Notification
+	offset := (pageNum - 1) * pageSize
+	if err := query.Order("created_at desc").Offset(offset).Limit(pageSize).Find(&notifications).Error; err != nil {
+		r.logger.WithContext(ctx).Error("notificationRepository.GetNotificationList Find error", zap.Error(err))
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userId string) (int64, error) {
+	var total int64
+	if err := r.DB(ctx).Table("sys_notifications").
+		Where("user_id = ? AND is_read = 0", userId).
+		Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("notificationRepository.CountUnread error", zap.Error(err))
+		return 0, err
+	}
+	return total, nil
+}
+
+// MarkRead 标记通知为已读，ids 为空时标记全部
+func (r *notificationRepository) MarkRead(ctx context.Context, userId string, ids []uint) error {
+	query := r.DB(ctx).Table("sys_notifications").Where("user_id = ?", userId)
+	if len(ids) > 0 {
+		query = query.Where("id IN (?)", ids)
+	}
+	if err := query.Update("is_read", 1).Error; err != nil {
+		r.logger.WithContext(ctx).Error("notificationRepository.MarkRead error", zap.Error(err))
+		return err
+	}
+	return nil
+}
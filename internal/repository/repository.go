@@ -7,6 +7,7 @@ import (
 	"github.com/olivere/elastic/v7"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -15,13 +16,17 @@ import (
 	"time"
 )
 
-const ctxTxKey = "TxKey"
+const (
+	ctxTxKey          = "TxKey"
+	ctxAfterCommitKey = "AfterCommitKey"
+)
 
 type Repository struct {
 	db       *gorm.DB
 	rdb      *redis.Client
 	logger   *log.Logger
-	esClient *elastic.Client // esClient 实例
+	esClient *elastic.Client    // esClient 实例
+	sf       singleflight.Group // 合并相同缓存键的并发回源查询
 }
 
 func NewRepository(
@@ -58,11 +63,36 @@ func (r *Repository) DB(ctx context.Context) *gorm.DB {
 	return r.db.WithContext(ctx)
 }
 
+// Transaction 在事务中执行 fn，事务提交成功后执行通过 afterCommit 注册的回调
 func (r *Repository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		ctx = context.WithValue(ctx, ctxTxKey, tx)
-		return fn(ctx)
+	var hooks []func()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := context.WithValue(ctx, ctxTxKey, tx)
+		txCtx = context.WithValue(txCtx, ctxAfterCommitKey, &hooks)
+		return fn(txCtx)
 	})
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+	return nil
+}
+
+// afterCommit 在事务提交后执行 fn，不在事务中时立即执行
+func (r *Repository) afterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(ctxAfterCommitKey).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn()
+}
+
+// inTransaction 判断 ctx 是否处于事务中
+func (r *Repository) inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(ctxTxKey).(*gorm.DB)
+	return ok
 }
 
 func NewDB(conf *viper.Viper, l *log.Logger) *gorm.DB {
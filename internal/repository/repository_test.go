@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/pkg/log"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestRepository(t *testing.T) *Repository {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库每个连接独立，只保留一个连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(
+		&model.User{},
+		&model.Article{},
+		&model.ArticleShare{},
+		&model.ArticleStat{},
+		&model.ArticleViewBatch{},
+		&model.Tag{},
+		&model.ArticleTag{},
+		&model.Attachment{},
+	))
+	return NewRepository(&log.Logger{Logger: zap.NewNop()}, db, rdb, nil)
+}
+
+// TestVisibleTo 列表查询的可见范围过滤须与 CanView 一致
+func TestVisibleTo(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	users := []*model.User{
+		{UserId: "author1", RoleType: enums.COMMON_USER, CollegeId: 1},
+		{UserId: "author0", RoleType: enums.COMMON_USER},
+		{UserId: "student", RoleType: enums.SUTDENT_USER, CollegeId: 1},
+		{UserId: "other", RoleType: enums.SUTDENT_USER, CollegeId: 2},
+		{UserId: "nocollege", RoleType: enums.COMMON_USER},
+		{UserId: "admin", RoleType: enums.SCHOOL_ADMIN, CollegeId: 2},
+		{UserId: "super", RoleType: enums.SUPER_ADMIN},
+	}
+	for i, user := range users {
+		user.Phone = strconv.Itoa(i)
+		require.NoError(t, r.db.Create(user).Error)
+	}
+	var visibilities []model.Visibility
+	for _, scope := range []string{enums.VisiblePublic, enums.VisibleLogin, enums.VisibleCollege, enums.VisiblePrivate} {
+		visibilities = append(visibilities,
+			model.Visibility{Scope: scope},
+			model.Visibility{Scope: scope, SharedUserIds: []string{"student", "nocollege"}},
+			model.Visibility{Scope: scope, SharedCollegeIds: []uint{2}},
+		)
+	}
+	for role := enums.COMMON_USER; role <= enums.SUPER_ADMIN; role++ {
+		visibilities = append(visibilities, model.Visibility{Scope: enums.VisibleRole, MinRole: role})
+	}
+
+	type visibleArticle struct {
+		article    *model.Article
+		visibility model.Visibility
+		author     *model.User
+	}
+	var articles []visibleArticle
+	for _, author := range users[:2] {
+		for _, visibility := range visibilities {
+			article := &model.Article{
+				Title:            fmt.Sprintf("%s %+v", author.UserId, visibility),
+				UserID:           author.UserId,
+				CategoryID:       1,
+				Status:           enums.StatusPublished,
+				VisibleRange:     visibility.Scope,
+				VisibleMinRole:   visibility.MinRole,
+				SharedUserIds:    visibility.SharedUserIds,
+				SharedCollegeIds: visibility.SharedCollegeIds,
+			}
+			require.NoError(t, r.db.Create(article).Error)
+			var shares []model.ArticleShare
+			for _, userId := range visibility.SharedUserIds {
+				shares = append(shares, model.ArticleShare{ArticleID: article.ArticleID, TargetType: enums.ShareTargetUser, TargetId: userId})
+			}
+			for _, collegeId := range visibility.SharedCollegeIds {
+				shares = append(shares, model.ArticleShare{ArticleID: article.ArticleID, TargetType: enums.ShareTargetCollege, TargetId: strconv.FormatUint(uint64(collegeId), 10)})
+			}
+			if len(shares) > 0 {
+				require.NoError(t, r.db.Create(&shares).Error)
+			}
+			articles = append(articles, visibleArticle{article: article, visibility: visibility, author: author})
+		}
+	}
+
+	repo := NewArticleRepository(r, viper.New())
+	for _, viewer := range users {
+		t.Run(viewer.UserId, func(t *testing.T) {
+			list, total, err := repo.GetArticleListByCategory(ctx, viewer, 1, 0, 1, len(articles))
+			require.NoError(t, err)
+			got := make(map[uint]bool, len(list))
+			for _, article := range list {
+				got[article.ArticleID] = true
+			}
+			var want int64
+			for _, a := range articles {
+				canView := a.visibility.CanView(viewer, a.author.UserId, a.author.CollegeId)
+				assert.Equal(t, canView, got[a.article.ArticleID], a.article.Title)
+				if canView {
+					want++
+				}
+			}
+			assert.Equal(t, want, total)
+		})
+	}
+}
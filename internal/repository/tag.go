@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"strings"
+)
+
+// TagCount 标签及其关联的文章数
+type TagCount struct {
+	Id           uint
+	TagName      string
+	ArticleCount int64
+}
+
+type TagRepository interface {
+	GetOrCreateTags(ctx context.Context, names []string) ([]model.Tag, error)
+	SetArticleTags(ctx context.Context, articleId uint, tagIds []uint) error
+	GetTagNamesByArticleIds(ctx context.Context, articleIds []uint) (map[uint][]string, error)
+	GetTagByName(ctx context.Context, name string) (*model.Tag, error)
+	SearchTags(ctx context.Context, prefix string, limit int) ([]TagCount, error)
+	GetArticleListByTag(ctx context.Context, viewer *model.User, tagId uint, pageNum int, pageSize int) ([]model.Article, int64, error)
+}
+
+func NewTagRepository(
+	repository *Repository,
+) TagRepository {
+	return &tagRepository{
+		Repository: repository,
+	}
+}
+
+type tagRepository struct {
+	*Repository
+}
+
+// GetOrCreateTags 按名称获取标签，不存在的标签自动创建，并发创建同名标签时依赖唯一索引去重
+func (r *tagRepository) GetOrCreateTags(ctx context.Context, names []string) ([]model.Tag, error) {
+	var tags []model.Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+	newTags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		newTags = append(newTags, model.Tag{TagName: name})
+	}
+	if err := r.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.GetOrCreateTags Create error", zap.Error(err))
+		return nil, err
+	}
+	if err := r.DB(ctx).Where("tag_name IN (?)", names).Find(&tags).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.GetOrCreateTags Find error", zap.Error(err))
+		return nil, err
+	}
+	return tags, nil
+}
+
+// SetArticleTags 用新的标签列表覆盖文章原有标签
+func (r *tagRepository) SetArticleTags(ctx context.Context, articleId uint, tagIds []uint) error {
+	if err := r.DB(ctx).Where("article_id = ?", articleId).Delete(&model.ArticleTag{}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.SetArticleTags Delete error", zap.Error(err))
+		return err
+	}
+	if len(tagIds) == 0 {
+		return nil
+	}
+	articleTags := make([]model.ArticleTag, 0, len(tagIds))
+	for _, tagId := range tagIds {
+		articleTags = append(articleTags, model.ArticleTag{ArticleID: articleId, TagId: tagId})
+	}
+	if err := r.DB(ctx).Create(&articleTags).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.SetArticleTags Create error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetTagNamesByArticleIds 批量获取文章的标签名称
+func (r *tagRepository) GetTagNamesByArticleIds(ctx context.Context, articleIds []uint) (map[uint][]string, error) {
+	tagNames := make(map[uint][]string, len(articleIds))
+	if len(articleIds) == 0 {
+		return tagNames, nil
+	}
+	var rows []struct {
+		ArticleID uint
+		TagName   string
+	}
+	if err := r.DB(ctx).Table("kb_article_tag AS at").
+		Select("at.article_id, t.tag_name").
+		Joins("JOIN kb_tag t ON t.id = at.tag_id").
+		Where("at.article_id IN (?)", articleIds).
+		Order("t.id").
+		Scan(&rows).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.GetTagNamesByArticleIds error", zap.Error(err))
+		return nil, err
+	}
+	for _, row := range rows {
+		tagNames[row.ArticleID] = append(tagNames[row.ArticleID], row.TagName)
+	}
+	return tagNames, nil
+}
+
+// GetTagByName 按名称获取标签，不存在时返回 nil
+func (r *tagRepository) GetTagByName(ctx context.Context, name string) (*model.Tag, error) {
+	var tags []model.Tag
+	if err := r.DB(ctx).Where("tag_name = ?", name).Limit(1).Find(&tags).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.GetTagByName error", zap.Error(err))
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	return &tags[0], nil
+}
+
+// SearchTags 按前缀匹配标签，按关联文章数倒序，用于输入联想
+func (r *tagRepository) SearchTags(ctx context.Context, prefix string, limit int) ([]TagCount, error) {
+	var tags []TagCount
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+	if err := r.DB(ctx).Table("kb_tag AS t").
+		Select("t.id, t.tag_name, COUNT(at.article_id) AS article_count").
+		Joins("LEFT JOIN kb_article_tag at ON at.tag_id = t.id").
+		Where("t.tag_name LIKE ?", escaped+"%").
+		Group("t.id, t.tag_name").
+		Order("article_count desc, t.id").
+		Limit(limit).
+		Scan(&tags).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.SearchTags error", zap.Error(err))
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetArticleListByTag 分页获取带有指定标签且当前用户可以查看的已发布文章
+func (r *tagRepository) GetArticleListByTag(ctx context.Context, viewer *model.User, tagId uint, pageNum int, pageSize int) ([]model.Article, int64, error) {
+	query := r.DB(ctx).Table("kb_article").
+		Joins("JOIN kb_article_tag at ON at.article_id = kb_article.article_id").
+		Scopes(visibleTo(viewer)).
+		Where("at.tag_id = ? AND kb_article.status = ? AND kb_article.deleted_at IS NULL", tagId, enums.StatusPublished)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.GetArticleListByTag Count error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var articles []model.Article
+	offset := (pageNum - 1) * pageSize
+	if err := query.Select("kb_article.*").
+		Order("kb_article.created_at desc").
+		Offset(offset).Limit(pageSize).
+		Find(&articles).Error; err != nil {
+		r.logger.WithContext(ctx).Error("tagRepository.GetArticleListByTag Find error", zap.Error(err))
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	v1 "projectName/api/v1"
+	"projectName/internal/enums"
 	"projectName/internal/model"
 	"time"
 )
@@ -15,19 +18,57 @@ type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	Update(ctx context.Context, user *model.User) error
 	GetByUserId(ctx context.Context, id string) (*model.User, error)
+	GetByUserIds(ctx context.Context, userIds []string) ([]model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	GetByPhone(ctx context.Context, phone string) (*model.User, error)
 	DeleteByUserId(ctx context.Context, userId string) error
+	GetByUserIdUnscoped(ctx context.Context, userId string) (*model.User, error)
+	RestoreByUserId(ctx context.Context, userId string) error
+	GetUserList(ctx context.Context, req *v1.GetUserListReq, pageNum int, pageSize int) ([]model.User, int64, error)
 	// 表：sys_user_auths
 	CreateUserAuth(ctx context.Context, userAuth *model.UserAuth) error
 	GetUserAuthByUserId(ctx context.Context, userId string) (*model.UserAuth, error)
 	UpdateUserAuth(ctx context.Context, userAuth *model.UserAuth) error
+	DisposeUserAuth(ctx context.Context, userAuth *model.UserAuth) (bool, error)
+	GetUserAuthById(ctx context.Context, id uint) (*model.UserAuth, error)
+	GetUserAuthList(ctx context.Context, collegeId uint, status int, pageNum int, pageSize int) ([]model.UserAuth, int64, error)
 	// redis
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
 	Delete(ctx context.Context, key string) error
+	DeleteByPrefix(ctx context.Context, prefix string) error
+	CompareAndSet(ctx context.Context, key string, oldValue string, newValue interface{}, expiration time.Duration) (bool, error)
 }
 
+// LoginTokenKey 登录会话 key，格式：loginToken:<userId>:<roleType>:<tokenId>
+func LoginTokenKey(userId string, roleType int, tokenId string) string {
+	return fmt.Sprintf("%s%s:%d:%s", enums.LOGIN_TOKEN_KEY, userId, roleType, tokenId)
+}
+
+// LoginTokenUserPrefix 用户所有登录会话 key 的前缀，用于退出所有设备
+func LoginTokenUserPrefix(userId string) string {
+	return fmt.Sprintf("%s%s:", enums.LOGIN_TOKEN_KEY, userId)
+}
+
+// RefreshTokenKey 刷新 token key，格式：refreshToken:<userId>:<tokenId>，值为当前有效的 refreshId
+func RefreshTokenKey(userId string, tokenId string) string {
+	return fmt.Sprintf("%s%s:%s", enums.REFRESH_TOKEN_KEY, userId, tokenId)
+}
+
+// RefreshTokenUserPrefix 用户所有刷新 token key 的前缀
+func RefreshTokenUserPrefix(userId string) string {
+	return fmt.Sprintf("%s%s:", enums.REFRESH_TOKEN_KEY, userId)
+}
+
+// compareAndSetScript 值与 oldValue 一致时才写入新值，保证并发刷新只有一个成功
+var compareAndSetScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0
+`)
+
 func NewUserRepository(
 	r *Repository,
 ) UserRepository {
@@ -68,6 +109,19 @@ func (r *userRepository) GetByUserId(ctx context.Context, userId string) (*model
 	return &user, nil
 }
 
+// GetByUserIds 批量获取用户，不存在的用户不会出现在结果中
+func (r *userRepository) GetByUserIds(ctx context.Context, userIds []string) ([]model.User, error) {
+	var users []model.User
+	if len(userIds) == 0 {
+		return users, nil
+	}
+	if err := r.DB(ctx).Table("sys_users").Where("user_id IN (?)", userIds).Find(&users).Error; err != nil {
+		r.logger.WithContext(ctx).Error("userRepository.GetByUserIds error", zap.Error(err))
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
 	if err := r.DB(ctx).Table("sys_users").Where("email = ?", email).First(&user).Error; err != nil {
@@ -105,6 +159,64 @@ func (r *userRepository) DeleteByUserId(ctx context.Context, userId string) erro
 	return nil
 }
 
+// GetByUserIdUnscoped 根据用户ID查询，包含已注销的用户
+func (r *userRepository) GetByUserIdUnscoped(ctx context.Context, userId string) (*model.User, error) {
+	var user model.User
+	if err := r.DB(ctx).Unscoped().Table("sys_users").Where("user_id = ?", userId).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		r.logger.WithContext(ctx).Error("userRepository.GetByUserIdUnscoped error", zap.Error(err))
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RestoreByUserId 恢复已注销的用户
+func (r *userRepository) RestoreByUserId(ctx context.Context, userId string) error {
+	if err := r.DB(ctx).Unscoped().Table("sys_users").
+		Where("user_id = ?", userId).
+		Updates(map[string]interface{}{"is_deleted": 0, "deleted_at": nil}).Error; err != nil {
+		r.logger.WithContext(ctx).Error("userRepository.RestoreByUserId error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetUserList 分页查询用户，筛选条件为空时不限
+func (r *userRepository) GetUserList(ctx context.Context, req *v1.GetUserListReq, pageNum int, pageSize int) ([]model.User, int64, error) {
+	query := r.DB(ctx).Unscoped().Model(&model.User{})
+	if req.Keyword != "" {
+		query = query.Where("phone LIKE ? OR nickname LIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+	if req.RoleType != nil && *req.RoleType != -1 {
+		query = query.Where("role_type = ?", *req.RoleType)
+	}
+	if req.CollegeId != 0 {
+		query = query.Where("college_id = ?", req.CollegeId)
+	}
+	if req.IsDisabled != nil && *req.IsDisabled != -1 {
+		query = query.Where("is_disabled = ?", *req.IsDisabled)
+	}
+	if req.IsDeleted != nil && *req.IsDeleted != -1 {
+		query = query.Where("is_deleted = ?", *req.IsDeleted)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("userRepository.GetUserList Count error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var users []model.User
+	offset := (pageNum - 1) * pageSize
+	if err := query.Order("created_at desc").Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
+		r.logger.WithContext(ctx).Error("userRepository.GetUserList Find error", zap.Error(err))
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 func (r *userRepository) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	if err := r.rdb.Set(ctx, key, value, expiration).Err(); err != nil {
 		r.logger.WithContext(ctx).Error("userRepository.Set error", zap.Error(err))
@@ -129,6 +241,31 @@ func (r *userRepository) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// DeleteByPrefix 删除指定前缀的所有 key
+func (r *userRepository) DeleteByPrefix(ctx context.Context, prefix string) error {
+	iter := r.rdb.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		if err := r.rdb.Del(ctx, iter.Val()).Err(); err != nil {
+			r.logger.WithContext(ctx).Error("userRepository.DeleteByPrefix error", zap.Error(err))
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		r.logger.WithContext(ctx).Error("userRepository.DeleteByPrefix error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *userRepository) CompareAndSet(ctx context.Context, key string, oldValue string, newValue interface{}, expiration time.Duration) (bool, error) {
+	ok, err := compareAndSetScript.Run(ctx, r.rdb, []string{key}, oldValue, newValue, expiration.Milliseconds()).Int()
+	if err != nil {
+		r.logger.WithContext(ctx).Error("userRepository.CompareAndSet error", zap.Error(err))
+		return false, err
+	}
+	return ok == 1, nil
+}
+
 func (r *userRepository) CreateUserAuth(ctx context.Context, userAuth *model.UserAuth) error {
 	if err := r.DB(ctx).Table("sys_user_auths").Create(userAuth).Error; err != nil {
 		r.logger.WithContext(ctx).Error("userRepository.CreateUserAuth error", zap.Error(err))
@@ -155,3 +292,57 @@ func (r *userRepository) UpdateUserAuth(ctx context.Context, userAuth *model.Use
 	}
 	return nil
 }
+
+// DisposeUserAuth 保存认证请求的审核结果，只有请求仍为待处理时才会更新，返回是否更新成功
+func (r *userRepository) DisposeUserAuth(ctx context.Context, userAuth *model.UserAuth) (bool, error) {
+	result := r.DB(ctx).Table("sys_user_auths").
+		Where("id = ? AND status = ?", userAuth.Id, enums.WAITING).
+		Updates(map[string]interface{}{
+			"status":       userAuth.Status,
+			"admin_id":     userAuth.AdminId,
+			"dispose_time": userAuth.DisposeTime,
+			"admin_remark": userAuth.AdminRemark,
+		})
+	if result.Error != nil {
+		r.logger.WithContext(ctx).Error("userRepository.DisposeUserAuth error", zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *userRepository) GetUserAuthById(ctx context.Context, id uint) (*model.UserAuth, error) {
+	var userAuth model.UserAuth
+	if err := r.DB(ctx).Table("sys_user_auths").Where("id = ?", id).First(&userAuth).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		r.logger.WithContext(ctx).Error("userRepository.GetUserAuthById error", zap.Error(err))
+		return nil, err
+	}
+	return &userAuth, nil
+}
+
+// GetUserAuthList 分页查询认证请求，collegeId 为 0 时不限学院，status 为 -1 时不限状态
+func (r *userRepository) GetUserAuthList(ctx context.Context, collegeId uint, status int, pageNum int, pageSize int) ([]model.UserAuth, int64, error) {
+	query := r.DB(ctx).Table("sys_user_auths")
+	if collegeId != 0 {
+		query = query.Where("college_id = ?", collegeId)
+	}
+	if status != -1 {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.WithContext(ctx).Error("userRepository.GetUserAuthList Count error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	var userAuthList []model.UserAuth
+	offset := (pageNum - 1) * pageSize
+	if err := query.Order("apply_time asc").Offset(offset).Limit(pageSize).Find(&userAuthList).Error; err != nil {
+		r.logger.WithContext(ctx).Error("userRepository.GetUserAuthList Find error", zap.Error(err))
+		return nil, 0, err
+	}
+	return userAuthList, total, nil
+}
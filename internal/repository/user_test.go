@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserRepository_DeleteByPrefix 退出所有设备只删除该用户自己的会话
+func TestUserRepository_DeleteByPrefix(t *testing.T) {
+	ctx := context.Background()
+	r := NewUserRepository(newTestRepository(t))
+	keys := []string{
+		LoginTokenKey("1", 0, "a"),
+		LoginTokenKey("1", 1, "b"),
+		LoginTokenKey("12", 0, "c"),
+		LoginTokenKey("2", 0, "d"),
+	}
+	for _, key := range keys {
+		require.NoError(t, r.Set(ctx, key, "token", time.Hour))
+	}
+
+	require.NoError(t, r.DeleteByPrefix(ctx, LoginTokenUserPrefix("1")))
+	for i, key := range keys {
+		_, err := r.Get(ctx, key)
+		if i < 2 {
+			assert.Error(t, err, key)
+		} else {
+			assert.NoError(t, err, key)
+		}
+	}
+}
+
+// TestUserRepository_CompareAndSet 刷新 token 轮换只有持有当前 refreshId 的请求能成功
+func TestUserRepository_CompareAndSet(t *testing.T) {
+	ctx := context.Background()
+	r := NewUserRepository(newTestRepository(t))
+	key := RefreshTokenKey("1", "t1")
+	require.NoError(t, r.Set(ctx, key, "r1", time.Hour))
+
+	ok, err := r.CompareAndSet(ctx, key, "r1", "r2", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	value, err := r.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, "r2", value)
+
+	// 旧的 refreshId 重复使用
+	ok, err = r.CompareAndSet(ctx, key, "r1", "r3", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	value, err = r.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, "r2", value)
+
+	// 会话已失效时不会重新创建
+	ok, err = r.CompareAndSet(ctx, RefreshTokenKey("1", "gone"), "r1", "r2", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = r.Get(ctx, RefreshTokenKey("1", "gone"))
+	assert.Error(t, err)
+}
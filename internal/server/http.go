@@ -10,6 +10,7 @@ import (
 	"projectName/internal/enums"
 	"projectName/internal/handler"
 	"projectName/internal/middleware"
+	"projectName/internal/repository"
 	"projectName/pkg/jwt"
 	"projectName/pkg/log"
 	"projectName/pkg/server/http"
@@ -19,9 +20,14 @@ func NewHTTPServer(
 	logger *log.Logger,
 	conf *viper.Viper,
 	jwt *jwt.JWT,
+	userRepo repository.UserRepository,
 	userHandler *handler.UserHandler,
 	collegeHandler *handler.CollegeHandler,
 	articleHandler *handler.ArticleHandler,
+	adminHandler *handler.AdminHandler,
+	notificationHandler *handler.NotificationHandler,
+	commentHandler *handler.CommentHandler,
+	fileHandler *handler.FileHandler,
 
 ) *http.Server {
 	gin.SetMode(gin.DebugMode)
@@ -61,45 +67,95 @@ func NewHTTPServer(
 		{
 			noAuthRouter.POST("/register", userHandler.Register)
 			noAuthRouter.POST("/passwordLogin", userHandler.PasswordLogin)
+			noAuthRouter.POST("/refreshToken", userHandler.RefreshToken)
 			noAuthRouter.GET("/getCaptcha", userHandler.GetCaptcha)
+			noAuthRouter.GET(enums.FILE+"/download", fileHandler.Download) // 凭签名地址下载附件
 		}
 		// 权限包含关系：超级管理员 > 学校管理员 > 学生用户 > 普通用户
 		// 普通用户路由组
-		commonUserRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, logger, enums.COMMON_USER))
+		commonUserRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, userRepo, logger, enums.COMMON_USER))
 		{
 			// 用户模块
 			commonUserRouter.GET(enums.USER+"/logout", userHandler.Logout)                    // 退出
+			commonUserRouter.GET(enums.USER+"/logoutAll", userHandler.LogoutAll)              // 退出所有设备
 			commonUserRouter.GET(enums.USER+"/cancel", userHandler.Cancel)                    // 注销
 			commonUserRouter.GET(enums.USER+"/getUserInfo", userHandler.GetUserInfo)          // 获取用户信息
 			commonUserRouter.POST(enums.USER+"/updateProfile", userHandler.UpdateProfile)     // 修改用户信息
 			commonUserRouter.GET(enums.USER+"/getCollege", collegeHandler.GetCollege)         // 获取学院信息
 			commonUserRouter.GET(enums.USER+"/getCollegeList", collegeHandler.GetCollegeList) // 获取学院信息列表
 			commonUserRouter.POST(enums.USER+"/userAuth", userHandler.UserAuth)
+			commonUserRouter.POST(enums.USER+"/getNotificationList", notificationHandler.GetNotificationList) // 获取通知列表
+			commonUserRouter.POST(enums.USER+"/readNotification", notificationHandler.ReadNotification)       // 标记通知已读
 
 			// 文章模块
 			commonUserRouter.GET(enums.ARTICLE+"/getArticleCategory", articleHandler.GetArticleCategory)             // 获取文章分组
 			commonUserRouter.GET(enums.ARTICLE+"/getArticle", articleHandler.GetArticle)                             // 获取文章详细
 			commonUserRouter.GET(enums.ARTICLE+"/getArticleListByCategory", articleHandler.GetArticleListByCategory) // 分类获取公开文章列表
 			commonUserRouter.POST(enums.ARTICLE+"/getArticleListByEs", articleHandler.GetArticleListByEs)            // es文章查询
+			commonUserRouter.GET(enums.ARTICLE+"/getTagSuggest", articleHandler.GetTagSuggest)                       // 标签联想
+			commonUserRouter.GET(enums.ARTICLE+"/getSearchSuggest", articleHandler.GetSearchSuggest)                 // 搜索联想和纠错
+			commonUserRouter.POST(enums.ARTICLE+"/getArticleListByTag", articleHandler.GetArticleListByTag)          // 按标签获取文章列表
+			commonUserRouter.GET(enums.ARTICLE+"/getHotArticleList", articleHandler.GetHotArticleList)               // 热门文章
+			commonUserRouter.POST(enums.ARTICLE+"/likeArticle", articleHandler.LikeArticle)                          // 点赞文章
+			commonUserRouter.POST(enums.ARTICLE+"/unlikeArticle", articleHandler.UnlikeArticle)                      // 取消点赞
+			commonUserRouter.POST(enums.ARTICLE+"/favoriteArticle", articleHandler.FavoriteArticle)                  // 收藏文章
+			commonUserRouter.POST(enums.ARTICLE+"/unfavoriteArticle", articleHandler.UnfavoriteArticle)              // 取消收藏
+			commonUserRouter.POST(enums.ARTICLE+"/getFavoriteList", articleHandler.GetFavoriteList)                  // 我的收藏
+			commonUserRouter.POST(enums.ARTICLE+"/createFavoriteFolder", articleHandler.CreateFavoriteFolder)        // 新建收藏夹
+			commonUserRouter.POST(enums.ARTICLE+"/updateFavoriteFolder", articleHandler.UpdateFavoriteFolder)        // 重命名收藏夹
+			commonUserRouter.POST(enums.ARTICLE+"/deleteFavoriteFolder", articleHandler.DeleteFavoriteFolder)        // 删除收藏夹
+			commonUserRouter.GET(enums.ARTICLE+"/getFavoriteFolderList", articleHandler.GetFavoriteFolderList)       // 收藏夹列表
+
+			// 评论模块
+			commonUserRouter.POST(enums.COMMENT+"/createComment", commentHandler.CreateComment)   // 发表评论
+			commonUserRouter.POST(enums.COMMENT+"/getCommentList", commentHandler.GetCommentList) // 获取评论列表
+			commonUserRouter.POST(enums.COMMENT+"/deleteComment", commentHandler.DeleteComment)   // 删除评论
+
+			// 文件模块
+			commonUserRouter.GET(enums.FILE+"/getDownloadUrl", fileHandler.GetDownloadUrl) // 获取附件下载地址
 		}
 		// 学生用户路由组
-		studentUserRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, logger, enums.SUTDENT_USER))
+		studentUserRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, userRepo, logger, enums.SUTDENT_USER))
 		{
 			// 文章模块
-			studentUserRouter.POST(enums.ARTICLE+"/create", articleHandler.CreateArticle)                  // 新建文章
-			studentUserRouter.POST(enums.ARTICLE+"/updateArticle", articleHandler.UpdateArticle)           // 修改文章
-			studentUserRouter.POST(enums.ARTICLE+"/deleteArticle", articleHandler.DeleteArticle)           // 删除文章
-			studentUserRouter.POST(enums.ARTICLE+"/deleteArticleList", articleHandler.DeleteArticleList)   // 批量删除文章
-			studentUserRouter.POST(enums.ARTICLE+"/getUserArticleList", articleHandler.GetUserArticleList) // 获取个人文章列表
+			studentUserRouter.POST(enums.ARTICLE+"/create", articleHandler.CreateArticle)                          // 新建文章
+			studentUserRouter.POST(enums.ARTICLE+"/saveDraft", articleHandler.SaveDraft)                           // 保存草稿
+			studentUserRouter.POST(enums.ARTICLE+"/publishArticle", articleHandler.PublishArticle)                 // 发布草稿
+			studentUserRouter.POST(enums.ARTICLE+"/updateArticle", articleHandler.UpdateArticle)                   // 修改文章
+			studentUserRouter.POST(enums.ARTICLE+"/deleteArticle", articleHandler.DeleteArticle)                   // 删除文章
+			studentUserRouter.POST(enums.ARTICLE+"/deleteArticleList", articleHandler.DeleteArticleList)           // 批量删除文章
+			studentUserRouter.POST(enums.ARTICLE+"/getUserArticleList", articleHandler.GetUserArticleList)         // 获取个人文章列表
+			studentUserRouter.POST(enums.ARTICLE+"/getArticleRevisionList", articleHandler.GetArticleRevisionList) // 获取文章历史版本
+			studentUserRouter.POST(enums.ARTICLE+"/getArticleRevisionDiff", articleHandler.GetArticleRevisionDiff) // 比较文章版本差异
+			studentUserRouter.POST(enums.ARTICLE+"/rollbackArticle", articleHandler.RollbackArticle)               // 回滚文章版本
+			studentUserRouter.POST(enums.FILE+"/upload", fileHandler.UploadFile)                                   // 上传附件
 		}
 		// 学校管理员路由组
-		//schoolAdminRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, logger, enums.SCHOOL_ADMIN))
-		//{
-		//}
+		schoolAdminRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, userRepo, logger, enums.SCHOOL_ADMIN))
+		{
+			// 认证审核
+			schoolAdminRouter.POST(enums.ADMIN+"/getUserAuthList", userHandler.GetUserAuthList) // 获取认证请求列表
+			schoolAdminRouter.POST(enums.ADMIN+"/reviewUserAuth", userHandler.ReviewUserAuth)   // 审核认证请求
+			// 文章审核
+			schoolAdminRouter.POST(enums.ADMIN+"/getReviewArticleList", articleHandler.GetReviewArticleList) // 获取待审核文章列表
+			schoolAdminRouter.POST(enums.ADMIN+"/reviewArticle", articleHandler.ReviewArticle)               // 审核文章
+		}
 		// 超级管理员路由组
-		//superAdminRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, logger, enums.SUPER_ADMIN))
-		//{
-		//}
+		superAdminRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, userRepo, logger, enums.SUPER_ADMIN))
+		{
+			// 用户管理
+			superAdminRouter.POST(enums.ADMIN+"/getUserList", adminHandler.GetUserList)          // 查询用户列表
+			superAdminRouter.POST(enums.ADMIN+"/updateUserRole", adminHandler.UpdateUserRole)    // 修改用户角色
+			superAdminRouter.POST(enums.ADMIN+"/setUserDisabled", adminHandler.SetUserDisabled)  // 禁用/启用用户
+			superAdminRouter.POST(enums.ADMIN+"/forceLogout", adminHandler.ForceLogout)          // 强制下线
+			superAdminRouter.POST(enums.ADMIN+"/restoreUser", adminHandler.RestoreUser)          // 恢复已注销用户
+			superAdminRouter.GET(enums.ADMIN+"/getEsSyncStatus", articleHandler.GetEsSyncStatus) // 查询es同步状态
+
+			// 分类管理
+			superAdminRouter.POST(enums.ARTICLE+"/createCategory", articleHandler.CreateCategory) // 新建分类
+			superAdminRouter.POST(enums.ARTICLE+"/updateCategory", articleHandler.UpdateCategory) // 修改分类
+			superAdminRouter.POST(enums.ARTICLE+"/deleteCategory", articleHandler.DeleteCategory) // 删除分类
+		}
 	}
 
 	return s
@@ -5,6 +5,7 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"os"
+	"projectName/internal/enums"
 	"projectName/internal/model"
 	"projectName/pkg/log"
 )
@@ -23,14 +24,57 @@ func NewMigrateServer(db *gorm.DB, log *log.Logger) *MigrateServer {
 func (m *MigrateServer) Start(ctx context.Context) error {
 	if err := m.db.AutoMigrate(
 		&model.User{},
+		&model.UserAuth{},
+		&model.Category{},
+		&model.Article{},
+		&model.Notification{},
+		&model.ArticleRevision{},
+		&model.Comment{},
+		&model.Tag{},
+		&model.ArticleTag{},
+		&model.Attachment{},
+		&model.ArticleShare{},
+		&model.EsOutbox{},
+		&model.ArticleStat{},
+		&model.ArticleViewBatch{},
+		&model.ArticleLike{},
+		&model.FavoriteFolder{},
+		&model.ArticleFavorite{},
 	); err != nil {
 		m.log.Error("user migrate error", zap.Error(err))
 		return err
 	}
+	if err := m.normalizeVisibleRange(); err != nil {
+		m.log.Error("normalize visible range error", zap.Error(err))
+		return err
+	}
 	m.log.Info("AutoMigrate success")
 	os.Exit(0)
 	return nil
 }
+
+// normalizeVisibleRange 将旧版以逗号分隔的可见范围转换为可见类型，列表查询直接按可见类型过滤
+func (m *MigrateServer) normalizeVisibleRange() error {
+	scopes := []string{enums.VisiblePublic, enums.VisibleLogin, enums.VisibleCollege, enums.VisibleRole, enums.VisiblePrivate}
+	var articles []model.Article
+	if err := m.db.Select("article_id", "visible_range").
+		Where("visible_range <> '' AND visible_range NOT IN ?", scopes).
+		Find(&articles).Error; err != nil {
+		return err
+	}
+	for _, article := range articles {
+		scope, minRole := model.ParseLegacyVisibleRange(article.VisibleRange)
+		if err := m.db.Model(&model.Article{}).Where("article_id = ?", article.ArticleID).
+			Updates(map[string]interface{}{"visible_range": scope, "visible_min_role": minRole}).Error; err != nil {
+			return err
+		}
+	}
+	if len(articles) > 0 {
+		m.log.Info("normalize visible range", zap.Int("count", len(articles)))
+	}
+	return nil
+}
+
 func (m *MigrateServer) Stop(ctx context.Context) error {
 	m.log.Info("AutoMigrate stop")
 	return nil
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"os"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/pkg/log"
+	"time"
+)
+
+const (
+	ReindexModeReindex = "reindex" // 重建索引并切换别名
+	ReindexModeVerify  = "verify"  // 校验数据库与es的一致性
+
+	reindexBatchSize  = 200 // 每批处理的文章数量
+	reindexSampleSize = 20  // 校验结果中记录的示例ID数量
+)
+
+// ReindexOptions 重建索引命令的参数
+type ReindexOptions struct {
+	Mode string // 运行模式：reindex、verify
+	Fix  bool   // 校验模式下将不一致的文章写入es同步任务
+}
+
+// ReindexServer 全量重建es文章索引，或校验es与数据库是否一致
+type ReindexServer struct {
+	log                *log.Logger
+	opts               ReindexOptions
+	articleRepository  repository.ArticleRepository
+	esOutboxRepository repository.EsOutboxRepository
+}
+
+func NewReindexServer(
+	log *log.Logger,
+	opts ReindexOptions,
+	articleRepository repository.ArticleRepository,
+	esOutboxRepository repository.EsOutboxRepository,
+) *ReindexServer {
+	return &ReindexServer{
+		log:                log,
+		opts:               opts,
+		articleRepository:  articleRepository,
+		esOutboxRepository: esOutboxRepository,
+	}
+}
+
+// Start 执行完成后退出进程，出错或校验发现不一致时退出码为 1
+func (s *ReindexServer) Start(ctx context.Context) error {
+	var (
+		ok  bool
+		err error
+	)
+	switch s.opts.Mode {
+	case ReindexModeReindex:
+		err = s.reindex(ctx)
+		ok = err == nil
+	case ReindexModeVerify:
+		ok, err = s.verify(ctx)
+	default:
+		s.log.Error("unknown reindex mode", zap.String("mode", s.opts.Mode))
+	}
+	if err != nil {
+		s.log.Error("reindex error", zap.String("mode", s.opts.Mode), zap.Error(err))
+	}
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(0)
+	return nil
+}
+
+// reindex 按完整映射创建新版本的索引，写入所有已发布文章后原子地切换别名
+// 重建期间同步服务仍写入旧索引，切换后将重建期间有变更的文章重新写入同步任务
+func (s *ReindexServer) reindex(ctx context.Context) error {
+	start := time.Now()
+	index := repository.NewEsArticleIndexName()
+	if err := s.articleRepository.CreateEsArticleIndex(ctx, index); err != nil {
+		return err
+	}
+	s.log.Info("reindex create index", zap.String("index", index))
+
+	total := 0
+	var afterId uint
+	for {
+		articles, err := s.articleRepository.GetSearchableArticles(ctx, afterId, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			break
+		}
+		esArticles := make([]*model.EsArticle, 0, len(articles))
+		for i := range articles {
+			esArticle, err := s.articleRepository.GetEsArticle(ctx, &articles[i])
+			if err != nil {
+				return err
+			}
+			esArticles = append(esArticles, esArticle)
+		}
+		if err = s.articleRepository.BulkIndexEsArticles(ctx, index, esArticles); err != nil {
+			return err
+		}
+		total += len(articles)
+		afterId = articles[len(articles)-1].ArticleID
+		s.log.Info("reindex progress", zap.String("index", index), zap.Int("indexed", total))
+	}
+
+	oldIndices, err := s.articleRepository.SwitchEsArticleAlias(ctx, index)
+	if err != nil {
+		return err
+	}
+	changedIds, err := s.articleRepository.GetArticleIdsUpdatedSince(ctx, start)
+	if err != nil {
+		return err
+	}
+	if err = s.esOutboxRepository.Enqueue(ctx, changedIds...); err != nil {
+		return err
+	}
+	// 旧索引保留用于回滚，确认无误后手动删除
+	s.log.Info("reindex success", zap.String("index", index), zap.Int("indexed", total),
+		zap.Int("resynced", len(changedIds)), zap.Strings("oldIndices", oldIndices), zap.Duration("cost", time.Since(start)))
+	return nil
+}
+
+// verify 对比数据库中已发布的文章与es文档，报告缺失、过期和多余的文档，返回是否一致
+func (s *ReindexServer) verify(ctx context.Context) (bool, error) {
+	var missing, stale, extra []uint
+	searchable := make(map[uint]struct{})
+	var afterId uint
+	for {
+		articles, err := s.articleRepository.GetSearchableArticles(ctx, afterId, reindexBatchSize)
+		if err != nil {
+			return false, err
+		}
+		if len(articles) == 0 {
+			break
+		}
+		ids := make([]uint, 0, len(articles))
+		for _, article := range articles {
+			ids = append(ids, article.ArticleID)
+			searchable[article.ArticleID] = struct{}{}
+		}
+		docs, err := s.articleRepository.GetEsArticlesByIds(ctx, ids)
+		if err != nil {
+			return false, err
+		}
+		for i := range articles {
+			doc, ok := docs[articles[i].ArticleID]
+			if !ok {
+				missing = append(missing, articles[i].ArticleID)
+			} else if esArticleStale(&articles[i], doc) {
+				stale = append(stale, articles[i].ArticleID)
+			}
+		}
+		afterId = articles[len(articles)-1].ArticleID
+	}
+
+	err := s.articleRepository.ScrollEsArticleIds(ctx, func(ids []uint) error {
+		for _, id := range ids {
+			if _, ok := searchable[id]; !ok {
+				extra = append(extra, id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	s.log.Info("verify result", zap.Int("searchable", len(searchable)),
+		zap.Int("missing", len(missing)), zap.Uints("missingSample", sample(missing)),
+		zap.Int("stale", len(stale)), zap.Uints("staleSample", sample(stale)),
+		zap.Int("extra", len(extra)), zap.Uints("extraSample", sample(extra)))
+	drift := len(missing) + len(stale) + len(extra)
+	if drift == 0 {
+		return true, nil
+	}
+	if s.opts.Fix {
+		// 同步服务按文章的最新状态写入或删除文档，多余的文档也会被删除
+		ids := make([]uint, 0, drift)
+		ids = append(append(append(ids, missing...), stale...), extra...)
+		if err = s.esOutboxRepository.Enqueue(ctx, ids...); err != nil {
+			return false, err
+		}
+		s.log.Info("verify fix enqueued", zap.Int("count", len(ids)))
+	}
+	return false, nil
+}
+
+// esArticleStale 判断es文档是否落后于数据库中的文章
+func esArticleStale(article *model.Article, doc *model.EsArticle) bool {
+	visibility := article.Visibility()
+	return !article.UpdatedAt.Truncate(time.Second).Equal(doc.UpdatedAt.Truncate(time.Second)) ||
+		article.Status != doc.Status ||
+		article.CategoryID != doc.CategoryID ||
+		visibility.Scope != doc.Visibility ||
+		visibility.MinRole != doc.MinRole
+}
+
+func sample(ids []uint) []uint {
+	if len(ids) > reindexSampleSize {
+		return ids[:reindexSampleSize]
+	}
+	return ids
+}
+
+func (s *ReindexServer) Stop(ctx context.Context) error {
+	s.log.Info("ReindexServer stop")
+	return nil
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/pkg/log"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeReindexRepository 用内存中的文章和es文档代替数据库和es，只实现重建索引用到的方法
+type fakeReindexRepository struct {
+	repository.ArticleRepository
+	articles  []model.Article // 数据库中已发布的文章，按ID排序
+	docs      map[uint]*model.EsArticle
+	indexed   map[string][]uint
+	aliasedTo string
+	changed   []uint
+}
+
+func (r *fakeReindexRepository) GetSearchableArticles(ctx context.Context, afterId uint, limit int) ([]model.Article, error) {
+	var result []model.Article
+	for _, article := range r.articles {
+		if article.ArticleID > afterId && len(result) < limit {
+			result = append(result, article)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeReindexRepository) GetEsArticlesByIds(ctx context.Context, ids []uint) (map[uint]*model.EsArticle, error) {
+	docs := make(map[uint]*model.EsArticle)
+	for _, id := range ids {
+		if doc, ok := r.docs[id]; ok {
+			docs[id] = doc
+		}
+	}
+	return docs, nil
+}
+
+func (r *fakeReindexRepository) ScrollEsArticleIds(ctx context.Context, fn func(ids []uint) error) error {
+	ids := make([]uint, 0, len(r.docs))
+	for id := range r.docs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return fn(ids)
+}
+
+func (r *fakeReindexRepository) CreateEsArticleIndex(ctx context.Context, index string) error {
+	r.indexed[index] = []uint{}
+	return nil
+}
+
+func (r *fakeReindexRepository) GetEsArticle(ctx context.Context, article *model.Article) (*model.EsArticle, error) {
+	return model.NewEsArticle(article), nil
+}
+
+func (r *fakeReindexRepository) BulkIndexEsArticles(ctx context.Context, index string, articles []*model.EsArticle) error {
+	for _, article := range articles {
+		r.indexed[index] = append(r.indexed[index], article.ArticleID)
+	}
+	return nil
+}
+
+func (r *fakeReindexRepository) SwitchEsArticleAlias(ctx context.Context, index string) ([]string, error) {
+	r.aliasedTo = index
+	return []string{"kb_article_v1"}, nil
+}
+
+func (r *fakeReindexRepository) GetArticleIdsUpdatedSince(ctx context.Context, since time.Time) ([]uint, error) {
+	return r.changed, nil
+}
+
+type fakeOutboxRepository struct {
+	repository.EsOutboxRepository
+	enqueued []uint
+}
+
+func (r *fakeOutboxRepository) Enqueue(ctx context.Context, articleIds ...uint) error {
+	r.enqueued = append(r.enqueued, articleIds...)
+	return nil
+}
+
+func newTestReindexServer(opts ReindexOptions, articles []model.Article, docs map[uint]*model.EsArticle) (*ReindexServer, *fakeReindexRepository, *fakeOutboxRepository) {
+	articleRepository := &fakeReindexRepository{articles: articles, docs: docs, indexed: map[string][]uint{}}
+	outbox := &fakeOutboxRepository{}
+	return NewReindexServer(&log.Logger{Logger: zap.NewNop()}, opts, articleRepository, outbox), articleRepository, outbox
+}
+
+func TestReindexServer_Reindex(t *testing.T) {
+	articles := make([]model.Article, 0, reindexBatchSize+1)
+	for i := 1; i <= reindexBatchSize+1; i++ {
+		articles = append(articles, model.Article{ArticleID: uint(i), Status: enums.StatusPublished})
+	}
+	s, articleRepository, outbox := newTestReindexServer(ReindexOptions{Mode: ReindexModeReindex}, articles, nil)
+	articleRepository.changed = []uint{3, 500}
+
+	require.NoError(t, s.reindex(context.Background()))
+	require.Len(t, articleRepository.indexed, 1)
+	for index, ids := range articleRepository.indexed {
+		assert.Len(t, ids, reindexBatchSize+1)
+		assert.Equal(t, index, articleRepository.aliasedTo)
+	}
+	// 重建期间有变更的文章重新写入同步任务
+	assert.Equal(t, []uint{3, 500}, outbox.enqueued)
+}
+
+func TestReindexServer_Verify(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	article := func(id uint) model.Article {
+		return model.Article{ArticleID: id, Status: enums.StatusPublished, CategoryID: 1, VisibleRange: enums.VisiblePublic, UpdatedAt: updatedAt}
+	}
+	doc := func(id uint) *model.EsArticle {
+		a := article(id)
+		return model.NewEsArticle(&a)
+	}
+	staleDoc := doc(2)
+	staleDoc.CategoryID = 9
+	// 数据库中的时间精度高于es，按秒比较
+	precise := article(4)
+	precise.UpdatedAt = updatedAt.Add(300 * time.Millisecond)
+	articles := []model.Article{article(1), article(2), article(3), precise}
+	docs := map[uint]*model.EsArticle{1: doc(1), 2: staleDoc, 4: doc(4), 5: doc(5)}
+
+	tests := []struct {
+		name         string
+		fix          bool
+		articles     []model.Article
+		docs         map[uint]*model.EsArticle
+		wantOk       bool
+		wantEnqueued []uint
+	}{
+		{"consistent", true, []model.Article{article(1), precise}, map[uint]*model.EsArticle{1: doc(1), 4: doc(4)}, true, nil},
+		{"report only", false, articles, docs, false, nil},
+		// 缺失、过期、多余的文档依次写入同步任务
+		{"fix", true, articles, docs, false, []uint{3, 2, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, outbox := newTestReindexServer(ReindexOptions{Mode: ReindexModeVerify, Fix: tt.fix}, tt.articles, tt.docs)
+			ok, err := s.verify(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantEnqueued, outbox.enqueued)
+		})
+	}
+}
+
+func TestEsArticleStale(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	base := model.Article{ArticleID: 1, Status: enums.StatusPublished, CategoryID: 1, UpdatedAt: updatedAt}
+	base.SetVisibility(model.Visibility{Scope: enums.VisibleRole, MinRole: enums.SUTDENT_USER})
+	tests := []struct {
+		name   string
+		modify func(doc *model.EsArticle)
+		want   bool
+	}{
+		{"same", func(doc *model.EsArticle) {}, false},
+		{"sub second difference", func(doc *model.EsArticle) { doc.UpdatedAt = updatedAt.Add(999 * time.Millisecond) }, false},
+		{"updated", func(doc *model.EsArticle) { doc.UpdatedAt = updatedAt.Add(-time.Second) }, true},
+		{"status", func(doc *model.EsArticle) { doc.Status = enums.StatusDraft }, true},
+		{"category", func(doc *model.EsArticle) { doc.CategoryID = 2 }, true},
+		{"visibility", func(doc *model.EsArticle) { doc.Visibility = enums.VisiblePublic }, true},
+		{"min role", func(doc *model.EsArticle) { doc.MinRole = enums.SCHOOL_ADMIN }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := model.NewEsArticle(&base)
+			tt.modify(doc)
+			assert.Equal(t, tt.want, esArticleStale(&base, doc))
+		})
+	}
+}
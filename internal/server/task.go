@@ -10,18 +10,27 @@ import (
 )
 
 type TaskServer struct {
-	log       *log.Logger
-	scheduler *gocron.Scheduler
-	userTask  task.UserTask
+	log            *log.Logger
+	scheduler      *gocron.Scheduler
+	userTask       task.UserTask
+	articleTask    task.ArticleTask
+	attachmentTask task.AttachmentTask
+	esSyncTask     task.EsSyncTask
 }
 
 func NewTaskServer(
 	log *log.Logger,
 	userTask task.UserTask,
+	articleTask task.ArticleTask,
+	attachmentTask task.AttachmentTask,
+	esSyncTask task.EsSyncTask,
 ) *TaskServer {
 	return &TaskServer{
-		log:      log,
-		userTask: userTask,
+		log:            log,
+		userTask:       userTask,
+		articleTask:    articleTask,
+		attachmentTask: attachmentTask,
+		esSyncTask:     esSyncTask,
 	}
 }
 func (t *TaskServer) Start(ctx context.Context) error {
@@ -45,6 +54,50 @@ func (t *TaskServer) Start(ctx context.Context) error {
 		t.log.Error("CheckUser error", zap.Error(err))
 	}
 
+	// 定时发布文章
+	_, err = t.scheduler.CronWithSeconds("0/30 * * * * *").SingletonMode().Do(func() {
+		err := t.articleTask.PublishScheduledArticles(ctx)
+		if err != nil {
+			t.log.Error("PublishScheduledArticles error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("PublishScheduledArticles error", zap.Error(err))
+	}
+
+	// 浏览量写入数据库
+	_, err = t.scheduler.Every(1).Minute().SingletonMode().Do(func() {
+		err := t.articleTask.FlushArticleViews(ctx)
+		if err != nil {
+			t.log.Error("FlushArticleViews error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("FlushArticleViews error", zap.Error(err))
+	}
+
+	// 提取附件文本写入es
+	_, err = t.scheduler.Every(1).Minute().SingletonMode().Do(func() {
+		err := t.attachmentTask.ExtractAttachments(ctx)
+		if err != nil {
+			t.log.Error("ExtractAttachments error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("ExtractAttachments error", zap.Error(err))
+	}
+
+	// 同步文章到es
+	_, err = t.scheduler.Every(5).Seconds().SingletonMode().Do(func() {
+		err := t.esSyncTask.SyncEsArticles(ctx)
+		if err != nil {
+			t.log.Error("SyncEsArticles error", zap.Error(err))
+		}
+	})
+	if err != nil {
+		t.log.Error("SyncEsArticles error", zap.Error(err))
+	}
+
 	t.scheduler.StartBlocking()
 	return nil
 }
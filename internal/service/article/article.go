@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/olivere/elastic/v7"
+	"github.com/spf13/viper"
 	v1 "projectName/api/v1"
 	"projectName/internal/enums"
 	"projectName/internal/model"
@@ -11,38 +12,101 @@ import (
 	"projectName/internal/repository"
 	"projectName/internal/service"
 	"projectName/pkg/utils"
-	"strings"
+	"time"
 )
 
 type ArticleService interface {
 	GetArticleById(ctx context.Context, id uint) (*model.Article, error)
 	GetArticle(ctx context.Context, userId string, id uint) (*v1.ArticleData, error)
+	CheckArticleVisible(ctx context.Context, userId string, id uint) error
 	CreateArticle(ctx context.Context, req *v1.CreateArticleRequest) (int, error)
+	SaveDraft(ctx context.Context, userId string, req *v1.SaveDraftRequest) (uint, error)
+	PublishArticle(ctx context.Context, userId string, req *v1.PublishArticleRequest) (*v1.PublishArticleResponseData, error)
 	GetArticleCategory(ctx context.Context) ([]vo.CategoryView, error)
-	UpdateArticle(ctx context.Context, req *v1.UpdateArticleRequest) (*v1.ArticleData, error)
+	CreateCategory(ctx context.Context, req *v1.CreateCategoryReq) (uint, error)
+	UpdateCategory(ctx context.Context, req *v1.UpdateCategoryReq) error
+	DeleteCategory(ctx context.Context, req *v1.DeleteCategoryReq) error
+	UpdateArticle(ctx context.Context, userId string, roleType int, req *v1.UpdateArticleRequest) (*v1.ArticleData, error)
 	DeleteArticle(ctx context.Context, id uint) (int, error)
 	DeleteArticleList(ctx context.Context, req *v1.DelArticleListReq) (int, error)
-	GetArticleListByCategory(ctx context.Context, req *v1.GetArticleListByCategoryReq) (*v1.ArticleList, error)
+	GetArticleListByCategory(ctx context.Context, userId string, req *v1.GetArticleListByCategoryReq) (*v1.ArticleList, error)
 	GetUserArticleList(ctx context.Context, userId string, req *v1.GetUserArticleListReq) (*v1.ArticleList, error)
-	GetArticleListByEs(ctx context.Context, req *v1.GetArticleListByEsReq) (*v1.SearchArticleResp, error)
+	GetArticleListByEs(ctx context.Context, userId string, req *v1.GetArticleListByEsReq) (*v1.SearchArticleResp, error)
+	GetReviewArticleList(ctx context.Context, reviewerId string, reviewerRole int, req *v1.PageRequest) (*v1.ArticleList, error)
+	ReviewArticle(ctx context.Context, reviewerId string, reviewerRole int, req *v1.ReviewArticleReq) error
+	GetArticleRevisionList(ctx context.Context, userId string, roleType int, req *v1.GetArticleRevisionListReq) (*v1.ArticleRevisionList, error)
+	GetArticleRevisionDiff(ctx context.Context, userId string, roleType int, req *v1.GetArticleRevisionDiffReq) (*v1.ArticleRevisionDiffData, error)
+	RollbackArticle(ctx context.Context, userId string, roleType int, req *v1.RollbackArticleReq) (*v1.ArticleData, error)
+	GetTagSuggest(ctx context.Context, keyword string) ([]*v1.TagData, error)
+	GetSearchSuggest(ctx context.Context, userId string, keyword string) (*v1.SearchSuggestData, error)
+	GetArticleListByTag(ctx context.Context, userId string, req *v1.GetArticleListByTagReq) (*v1.ArticleList, error)
+	GetEsSyncStatus(ctx context.Context) (*v1.EsSyncStatusData, error)
+	GetHotArticleList(ctx context.Context, userId string, period string, size int) ([]*v1.ArticleData, error)
+	LikeArticle(ctx context.Context, userId string, articleId uint) (*v1.LikeArticleResponseData, error)
+	UnlikeArticle(ctx context.Context, userId string, articleId uint) (*v1.LikeArticleResponseData, error)
+	FavoriteArticle(ctx context.Context, userId string, req *v1.FavoriteArticleReq) (*v1.FavoriteArticleResponseData, error)
+	UnfavoriteArticle(ctx context.Context, userId string, req *v1.UnfavoriteArticleReq) (*v1.FavoriteArticleResponseData, error)
+	GetFavoriteList(ctx context.Context, userId string, req *v1.GetFavoriteListReq) (*v1.ArticleList, error)
+	CreateFavoriteFolder(ctx context.Context, userId string, req *v1.CreateFavoriteFolderReq) (uint, error)
+	UpdateFavoriteFolder(ctx context.Context, userId string, req *v1.UpdateFavoriteFolderReq) error
+	DeleteFavoriteFolder(ctx context.Context, userId string, req *v1.DeleteFavoriteFolderReq) error
+	GetFavoriteFolderList(ctx context.Context, userId string) ([]*v1.FavoriteFolderData, error)
 }
 
 func NewArticleService(
 	service *service.Service,
+	conf *viper.Viper,
 	articleRepository repository.ArticleRepository,
 	userRepo repository.UserRepository,
+	notificationRepository repository.NotificationRepository,
+	articleRevisionRepository repository.ArticleRevisionRepository,
+	commentRepository repository.CommentRepository,
+	tagRepository repository.TagRepository,
+	attachmentRepository repository.AttachmentRepository,
+	esOutboxRepository repository.EsOutboxRepository,
+	articleStatRepository repository.ArticleStatRepository,
+	articleLikeRepository repository.ArticleLikeRepository,
+	favoriteRepository repository.FavoriteRepository,
 ) ArticleService {
+	viewWindow := conf.GetDuration("article.view.window")
+	if viewWindow <= 0 {
+		viewWindow = defaultViewWindow
+	}
 	return &articleService{
-		Service:           service,
-		articleRepository: articleRepository,
-		userRepo:          userRepo,
+		Service:                   service,
+		articleRepository:         articleRepository,
+		userRepo:                  userRepo,
+		notificationRepository:    notificationRepository,
+		articleRevisionRepository: articleRevisionRepository,
+		commentRepository:         commentRepository,
+		tagRepository:             tagRepository,
+		attachmentRepository:      attachmentRepository,
+		esOutboxRepository:        esOutboxRepository,
+		articleStatRepository:     articleStatRepository,
+		articleLikeRepository:     articleLikeRepository,
+		favoriteRepository:        favoriteRepository,
+		viewWindow:                viewWindow,
+		reviewEnabled:             conf.GetBool("article.review.enabled"),
+		searchBoosts:              newSearchBoosts(conf),
 	}
 }
 
 type articleService struct {
 	*service.Service
-	articleRepository repository.ArticleRepository
-	userRepo          repository.UserRepository
+	articleRepository         repository.ArticleRepository
+	userRepo                  repository.UserRepository
+	notificationRepository    repository.NotificationRepository
+	articleRevisionRepository repository.ArticleRevisionRepository
+	commentRepository         repository.CommentRepository
+	tagRepository             repository.TagRepository
+	attachmentRepository      repository.AttachmentRepository
+	esOutboxRepository        repository.EsOutboxRepository
+	articleStatRepository     repository.ArticleStatRepository
+	articleLikeRepository     repository.ArticleLikeRepository
+	favoriteRepository        repository.FavoriteRepository
+	reviewEnabled             bool          // 全局审核开关
+	searchBoosts              searchBoosts  // 关键字搜索的字段权重
+	viewWindow                time.Duration // 同一用户重复浏览只计一次的时间窗口
 }
 
 func (s *articleService) GetArticleById(ctx context.Context, id uint) (*model.Article, error) {
@@ -55,51 +119,28 @@ func (s *articleService) GetArticleById(ctx context.Context, id uint) (*model.Ar
 }
 
 func (s *articleService) GetArticle(ctx context.Context, userId string, id uint) (*v1.ArticleData, error) {
-	// 获取文章
-	article, err := s.articleRepository.GetArticle(ctx, id)
+	article, err := s.getReadableArticle(ctx, userId, id)
 	if err != nil {
-		return nil, v1.ErrArticleNotExist
+		return nil, err
 	}
-	if article.Status != enums.StatusPublished && article.Status != enums.StatusPendingReview {
-		return nil, v1.ErrArticleStatusError
+	s.recordView(ctx, userId, article)
+	viewer, err := getViewer(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
 	}
-
-	// 私有 判断是否为本人
-	if utils.Contains(article.VisibleRange, "private") {
-		if userId != article.UserID {
-			return nil, v1.ErrPermissionDenied
-		}
+	articleData, err := s.buildArticleData(ctx, viewer, article)
+	if err != nil {
+		return nil, err
 	}
-	Author, _ := s.userRepo.GetByUserId(ctx, article.UserID)
-	category, _ := s.articleRepository.GetCategory(ctx, article.CategoryID)
-
-	// 反序列化上传的文件列表
-	var uploadedFiles []v1.FileUpload
-	if len(article.UploadedFiles) > 0 {
-		err = json.Unmarshal(article.UploadedFiles, &uploadedFiles)
-		if err != nil {
-			return nil, v1.ErrDeserializeFileFailed
-		}
+	liked, err := s.articleLikeRepository.GetLikedArticleIds(ctx, userId, []uint{id})
+	if err != nil {
+		return nil, v1.ErrQueryFailed
 	}
-
-	// 映射
-	articleData := &v1.ArticleData{
-		ArticleID:       article.ArticleID,
-		Title:           article.Title,
-		Content:         article.Content,
-		ContentShort:    article.ContentShort,
-		Author:          Author.Nickname,
-		Category:        category.CategoryName,
-		CategoryID:      article.CategoryID,
-		Importance:      article.Importance,
-		VisibleRange:    article.VisibleRange,
-		CommentDisabled: article.CommentDisabled,
-		SourceURI:       article.SourceURI,
-		UploadedFiles:   uploadedFiles,
-		Status:          article.Status,
-		CreatedAt:       utils.TimeFormat(article.CreatedAt, utils.FormatDateTime),
-		UpdatedAt:       utils.TimeFormat(article.UpdatedAt, utils.FormatDateTime),
+	favorited, err := s.favoriteRepository.GetFavoritedArticleIds(ctx, userId, []uint{id})
+	if err != nil {
+		return nil, v1.ErrQueryFailed
 	}
+	articleData.Liked, articleData.Favorited = liked[id], favorited[id]
 	return articleData, nil
 }
 
@@ -109,9 +150,25 @@ func (s *articleService) CreateArticle(ctx context.Context, req *v1.CreateArticl
 	if article != nil {
 		return -1, v1.ErrArticleAlreadyExist
 	}
-	uploadedFilesData, err := json.Marshal(req.UploadedFiles)
+	uploadedFilesData, attachmentIds, err := s.resolveUploadedFiles(ctx, req.UploadedFiles, 0, req.AuthorID)
+	if err != nil {
+		return -1, err
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return -1, err
+	}
+	visibility, err := normalizeVisibility(req.VisibleRange, true)
+	if err != nil {
+		return -1, err
+	}
+	publishAt, err := parsePublishAt(req.PublishAt)
+	if err != nil {
+		return -1, err
+	}
+	status, err := s.getPublishStatus(ctx, req.CategoryID, publishAt)
 	if err != nil {
-		return -1, v1.ErrUploadFileFailed
+		return -1, err
 	}
 	article = &model.Article{
 		Title:           req.Title,
@@ -120,40 +177,34 @@ func (s *articleService) CreateArticle(ctx context.Context, req *v1.CreateArticl
 		UserID:          req.AuthorID,
 		CategoryID:      req.CategoryID,
 		Importance:      req.Importance,
-		VisibleRange:    req.VisibleRange,
 		CommentDisabled: req.CommentDisabled,
 		SourceURI:       req.SourceURI,
 		UploadedFiles:   uploadedFilesData,
-		Status:          enums.StatusPublished, // todo：后续设置审核开关
+		Status:          status,
+		PublishAt:       publishAt,
 	}
+	article.SetVisibility(visibility)
 	// 创建新文章
-	articleId, err := s.articleRepository.CreateArticle(ctx, article)
-	// 判断是否公开，如果公开则创建es文档
-	if strings.Contains(article.VisibleRange, "public") {
-		esArticle := &model.EsArticle{
-			ArticleID:    uint(articleId),
-			Title:        article.Title,
-			Content:      article.Content,
-			CategoryID:   article.CategoryID,
-			UserID:       article.UserID,
-			Status:       article.Status,
-			VisibleRange: article.VisibleRange,
-			UploadedFile: false,
-			CreatedAt:    article.CreatedAt,
-			UpdatedAt:    article.UpdatedAt,
+	var articleId int
+	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		articleId, err = s.articleRepository.CreateArticle(ctx, article)
+		if err != nil {
+			return v1.ErrCreateArticleFailed
 		}
-		esArticle.ArticleID = uint(articleId)
-		if article.UploadedFiles != nil {
-			esArticle.UploadedFile = true
+		if err = s.setArticleTags(ctx, article.ArticleID, tags); err != nil {
+			return err
 		}
-
-		// 创建es文档
-		if err = s.articleRepository.CreateEsArticle(ctx, esArticle); err != nil {
-			return -1, v1.ErrCreateEsArticleFailed
+		if err = s.attachmentRepository.BindArticle(ctx, article.ArticleID, attachmentIds); err != nil {
+			return v1.ErrUpdateFailed
 		}
-	}
+		// 写入es同步任务，已发布的文章才会写入es
+		if err = s.syncEsArticle(ctx, article); err != nil {
+			return v1.ErrCreateEsArticleFailed
+		}
+		return nil
+	})
 	if err != nil {
-		return -1, v1.ErrCreateArticleFailed
+		return -1, err
 	}
 	return articleId, nil
 }
@@ -166,81 +217,247 @@ func (s *articleService) GetArticleCategory(ctx context.Context) ([]vo.CategoryV
 	return categories, err
 }
 
-func (s *articleService) UpdateArticle(ctx context.Context, req *v1.UpdateArticleRequest) (*v1.ArticleData, error) {
-	article, err := s.articleRepository.GetArticle(ctx, req.ArticleID)
+func (s *articleService) CreateCategory(ctx context.Context, req *v1.CreateCategoryReq) (uint, error) {
+	// 父分类必须存在
+	if req.ParentId != 0 {
+		if _, err := s.articleRepository.GetCategoryById(ctx, req.ParentId); err != nil {
+			return 0, v1.ErrCategoryNotExist
+		}
+	}
+	// 同级分类不能重名
+	existing, err := s.articleRepository.GetCategoryByNameAndParent(ctx, req.CategoryName, req.ParentId)
+	if err != nil {
+		return 0, v1.ErrQueryFailed
+	}
+	if existing != nil {
+		return 0, v1.ErrCategoryExist
+	}
+	category := &model.Category{
+		CategoryName: req.CategoryName,
+		ParentId:     req.ParentId,
+	}
+	if req.NeedReview {
+		category.NeedReview = 1
+	}
+	if err = s.articleRepository.CreateCategory(ctx, category); err != nil {
+		return 0, v1.ErrInsertFailed
+	}
+	return category.CId, nil
+}
+
+// UpdateCategory 重命名或移动分类，移动时不能形成环
+func (s *articleService) UpdateCategory(ctx context.Context, req *v1.UpdateCategoryReq) error {
+	category, err := s.articleRepository.GetCategoryById(ctx, req.CategoryId)
+	if err != nil {
+		return v1.ErrCategoryNotExist
+	}
+	if req.CategoryName != "" {
+		category.CategoryName = req.CategoryName
+	}
+	if req.NeedReview != nil {
+		category.NeedReview = 0
+		if *req.NeedReview {
+			category.NeedReview = 1
+		}
+	}
+	if req.ParentId != nil && *req.ParentId != category.ParentId {
+		if err = s.checkCategoryParent(ctx, category.CId, *req.ParentId); err != nil {
+			return err
+		}
+		category.ParentId = *req.ParentId
+	}
+	// 同级分类不能重名
+	existing, err := s.articleRepository.GetCategoryByNameAndParent(ctx, category.CategoryName, category.ParentId)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	if existing != nil && existing.CId != category.CId {
+		return v1.ErrCategoryExist
+	}
+	if err = s.articleRepository.UpdateCategory(ctx, category); err != nil {
+		return v1.ErrUpdateFailed
+	}
+	return nil
+}
+
+// checkCategoryParent 校验新的父分类存在，且不是自身或自身的子孙分类
+func (s *articleService) checkCategoryParent(ctx context.Context, categoryId uint, parentId uint) error {
+	if parentId == 0 {
+		return nil
+	}
+	if parentId == categoryId {
+		return v1.ErrCategoryCycle
+	}
+	categories, err := s.articleRepository.GetAllCategories(ctx)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	parentMap := make(map[uint]uint, len(categories))
+	for _, category := range categories {
+		parentMap[category.CId] = category.ParentId
+	}
+	if _, ok := parentMap[parentId]; !ok {
+		return v1.ErrCategoryNotExist
+	}
+	// 沿新父分类向上查找祖先，遇到自身说明会形成环
+	visited := make(map[uint]bool)
+	for id := parentId; id != 0; id = parentMap[id] {
+		if id == categoryId || visited[id] {
+			return v1.ErrCategoryCycle
+		}
+		visited[id] = true
+	}
+	return nil
+}
+
+// DeleteCategory 删除分类，分类下仍有文章时需指定文章移动到的分类
+func (s *articleService) DeleteCategory(ctx context.Context, req *v1.DeleteCategoryReq) error {
+	if _, err := s.articleRepository.GetCategoryById(ctx, req.CategoryId); err != nil {
+		return v1.ErrCategoryNotExist
+	}
+	categories, err := s.articleRepository.GetAllCategories(ctx)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	for _, category := range categories {
+		if category.ParentId == req.CategoryId {
+			return v1.ErrCategoryHasChildren
+		}
+	}
+	articleCount, err := s.articleRepository.CountArticleByCategory(ctx, req.CategoryId)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	if articleCount > 0 {
+		if req.MoveToCategoryId == 0 {
+			return v1.ErrCategoryHasArticles
+		}
+		if req.MoveToCategoryId == req.CategoryId {
+			return v1.ErrBadRequest
+		}
+		if _, err = s.articleRepository.GetCategoryById(ctx, req.MoveToCategoryId); err != nil {
+			return v1.ErrCategoryNotExist
+		}
+	}
+
+	return s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		if articleCount > 0 {
+			articleIds, err := s.articleRepository.GetArticleIdsByCategory(ctx, req.CategoryId)
+			if err != nil {
+				return v1.ErrQueryFailed
+			}
+			if _, err := s.articleRepository.MoveArticleCategory(ctx, req.CategoryId, req.MoveToCategoryId); err != nil {
+				return v1.ErrUpdateFailed
+			}
+			if err := s.esOutboxRepository.Enqueue(ctx, articleIds...); err != nil {
+				return v1.ErrUpdateEsArticleFailed
+			}
+		}
+		if err := s.articleRepository.DeleteCategory(ctx, req.CategoryId); err != nil {
+			return v1.ErrDeleteFailed
+		}
+		return nil
+	})
+}
+
+// UpdateArticle 修改文章，只有作者和超级管理员可以修改
+func (s *articleService) UpdateArticle(ctx context.Context, userId string, roleType int, req *v1.UpdateArticleRequest) (*v1.ArticleData, error) {
+	article, err := s.articleRepository.GetArticleFromDB(ctx, req.ArticleID)
 	if err != nil {
 		return nil, v1.ErrArticleNotExist
 	}
-	// 更新文章
+	if article.UserID != userId && roleType != enums.SUPER_ADMIN {
+		return nil, v1.ErrPermissionDenied
+	}
+	// 草稿的发布需经过 PublishArticle 的校验
+	if article.Status == enums.StatusDraft {
+		return nil, v1.ErrArticleIsDraft
+	}
+	uploadedFilesData, attachmentIds, err := s.resolveUploadedFiles(ctx, req.UploadedFiles, article.ArticleID, article.UserID, userId)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := normalizeVisibility(req.VisibleRange, true)
+	if err != nil {
+		return nil, err
+	}
+	publishAt, err := parsePublishAt(req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	status, err := s.getPublishStatus(ctx, req.CategoryID, publishAt)
+	if err != nil {
+		return nil, err
+	}
+	// 更新文章，旧内容保存为历史版本
+	previous := *article
 	article.Title = req.Title
 	article.Content = req.Content
 	article.ContentShort = req.ContentShort
 	article.CategoryID = req.CategoryID
 	article.Importance = req.Importance
-	article.VisibleRange = req.VisibleRange
+	article.SetVisibility(visibility)
 	article.CommentDisabled = req.CommentDisabled
 	article.SourceURI = req.SourceURI
-	article.Status = enums.StatusPublished // todo：后续设置审核开关
-	updateArticle, err := s.articleRepository.UpdateArticle(ctx, article)
+	article.UploadedFiles = uploadedFilesData
+	article.Status = status
+	article.PublishAt = publishAt
+	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.saveRevision(ctx, &previous, article, tags, userId); err != nil {
+			return err
+		}
+		if _, err := s.articleRepository.UpdateArticle(ctx, article); err != nil {
+			return v1.ErrUpdateArticleFailed
+		}
+		if err := s.setArticleTags(ctx, article.ArticleID, tags); err != nil {
+			return err
+		}
+		if err := s.attachmentRepository.BindArticle(ctx, article.ArticleID, attachmentIds); err != nil {
+			return v1.ErrUpdateFailed
+		}
+		// 写入es同步任务，由 task 服务更新或删除es文档
+		if err := s.syncEsArticle(ctx, article); err != nil {
+			return v1.ErrUpdateEsArticleFailed
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, v1.ErrUpdateArticleFailed
+		return nil, err
 	}
 	// 映射
-	Author, _ := s.userRepo.GetByUserId(ctx, article.UserID)
-	category, _ := s.articleRepository.GetCategory(ctx, article.CategoryID)
-	// 反序列化上传的文件列表
-	var uploadedFiles []v1.FileUpload
-	if len(article.UploadedFiles) > 0 {
-		err = json.Unmarshal(article.UploadedFiles, &uploadedFiles)
-		if err != nil {
-			return nil, v1.ErrDeserializeFileFailed
-		}
-	}
-	articleData := &v1.ArticleData{
-		ArticleID:       updateArticle.ArticleID,
-		Title:           updateArticle.Title,
-		Content:         updateArticle.Content,
-		ContentShort:    updateArticle.ContentShort,
-		Author:          Author.Nickname,
-		Category:        category.CategoryName,
-		CategoryID:      updateArticle.CategoryID,
-		Importance:      updateArticle.Importance,
-		VisibleRange:    updateArticle.VisibleRange,
-		CommentDisabled: updateArticle.CommentDisabled,
-		SourceURI:       updateArticle.SourceURI,
-		UploadedFiles:   uploadedFiles,
-		Status:          updateArticle.Status,
-		CreatedAt:       utils.TimeFormat(updateArticle.CreatedAt, utils.FormatDateTime),
-		UpdatedAt:       utils.TimeFormat(updateArticle.UpdatedAt, utils.FormatDateTime),
-	}
-	// 判断是否公开，如果公开则更新es文档
-	if strings.Contains(article.VisibleRange, "public") {
-		esArticle := &model.EsArticle{
-			ArticleID:    uint(article.ArticleID),
-			Title:        article.Title,
-			Content:      article.Content,
-			CategoryID:   article.CategoryID,
-			UserID:       article.UserID,
-			Status:       article.Status,
-			VisibleRange: article.VisibleRange,
-			UploadedFile: false,
-			CreatedAt:    article.CreatedAt,
-			UpdatedAt:    article.UpdatedAt,
-		}
-		if article.UploadedFiles != nil {
-			esArticle.UploadedFile = true
-		}
-		// 更新es文档
-		if err = s.articleRepository.UpdateEsArticle(ctx, esArticle); err != nil {
-			return nil, v1.ErrUpdateEsArticleFailed
-		}
-	} else {
-		// 删除es文档
-		if err = s.articleRepository.DeleteEsArticle(ctx, uint(article.ArticleID)); err != nil {
-			return nil, v1.ErrDeleteEsArticleFailed
+	return s.buildArticleData(ctx, &model.User{UserId: userId, RoleType: roleType}, article)
+}
+
+// CheckArticleVisible 校验当前用户能否查看文章，只做权限校验，不记录浏览
+func (s *articleService) CheckArticleVisible(ctx context.Context, userId string, id uint) error {
+	_, err := s.getReadableArticle(ctx, userId, id)
+	return err
+}
+
+// getReadableArticle 获取当前用户可以查看的文章
+func (s *articleService) getReadableArticle(ctx context.Context, userId string, id uint) (*model.Article, error) {
+	article, err := s.articleRepository.GetArticle(ctx, id)
+	if err != nil {
+		return nil, v1.ErrArticleNotExist
+	}
+	// 未发布的文章（草稿、待审核、已驳回、定时发布）仅作者本人可见
+	if article.Status != enums.StatusPublished {
+		if article.Status != enums.StatusDraft && article.Status != enums.StatusPendingReview &&
+			article.Status != enums.StatusRejected && article.Status != enums.StatusScheduled {
+			return nil, v1.ErrArticleStatusError
+		}
+		if userId != article.UserID {
+			return nil, v1.ErrArticleStatusError
 		}
 	}
-	return articleData, nil
+	if err = checkArticleVisible(ctx, s.userRepo, userId, article); err != nil {
+		return nil, err
+	}
+	return article, nil
 }
 
 func (s *articleService) DeleteArticle(ctx context.Context, id uint) (int, error) {
@@ -249,68 +466,67 @@ func (s *articleService) DeleteArticle(ctx context.Context, id uint) (int, error
 	if err != nil {
 		return -1, v1.ErrArticleNotExist
 	}
-	// 删除文章
-	deletedCount, err := s.articleRepository.DeleteArticle(ctx, article.ArticleID)
+	// 删除文章，同时写入es同步任务删除es文档
+	var deletedCount int
+	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		deletedCount, err = s.articleRepository.DeleteArticle(ctx, article.ArticleID)
+		if err != nil {
+			return v1.ErrDeleteFailed
+		}
+		if err = s.esOutboxRepository.Enqueue(ctx, article.ArticleID); err != nil {
+			return v1.ErrDeleteEsArticleFailed
+		}
+		return nil
+	})
 	if err != nil {
-		return -1, v1.ErrDeleteFailed
-	}
-	// 删除es文档
-	if err = s.articleRepository.DeleteEsArticle(ctx, uint(article.ArticleID)); err != nil {
-		return -1, v1.ErrDeleteEsArticleFailed
+		return -1, err
 	}
 	return deletedCount, nil
 }
 
 func (s *articleService) DeleteArticleList(ctx context.Context, req *v1.DelArticleListReq) (int, error) {
-	// 批量删除文章
-	deletedCount, err := s.articleRepository.DeleteArticleList(ctx, req.ArticleIDList)
+	// 批量删除文章，同时写入es同步任务删除es文档
+	var deletedCount int
+	err := s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		deletedCount, err = s.articleRepository.DeleteArticleList(ctx, req.ArticleIDList)
+		if err != nil {
+			return v1.ErrDeleteFailed
+		}
+		if err = s.esOutboxRepository.Enqueue(ctx, req.ArticleIDList...); err != nil {
+			return v1.ErrDeleteEsArticleFailed
+		}
+		return nil
+	})
 	if err != nil {
-		return -1, v1.ErrDeleteFailed
+		return -1, err
 	}
 	return deletedCount, nil
 }
 
-func (s *articleService) GetArticleListByCategory(ctx context.Context, req *v1.GetArticleListByCategoryReq) (*v1.ArticleList, error) {
-	// 查询文章列表及分页信息
-	pageIndex, pageSize := initPage(req.PageIndex, req.PageSize)
-	articles, total, err := s.articleRepository.GetArticleListByCategory(ctx, req.CategoryID, pageIndex, pageSize)
+func (s *articleService) GetArticleListByCategory(ctx context.Context, userId string, req *v1.GetArticleListByCategoryReq) (*v1.ArticleList, error) {
+	viewer, err := getViewer(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	// 查询当前用户可见的文章列表及分页信息，设置游标时按游标继续查询
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	var afterId uint
+	if req.Cursor != "" {
+		if afterId, err = decodeIdCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+		pageIndex = 0
+	}
+	articles, total, err := s.articleRepository.GetArticleListByCategory(ctx, viewer, req.CategoryID, afterId, pageIndex, pageSize)
 	if err != nil {
 		return nil, v1.ErrQueryFailed
 	}
 
 	// 映射文章数据
-	var articleList []*v1.ArticleData
-	for _, article := range articles {
-		// 获取作者昵称
-		Author, _ := s.userRepo.GetByUserId(ctx, article.UserID)
-		// 获取分类名称
-		category, _ := s.articleRepository.GetCategory(ctx, article.CategoryID)
-		// 反序列化上传的文件列表
-		var uploadedFiles []v1.FileUpload
-		if len(article.UploadedFiles) > 0 {
-			err = json.Unmarshal(article.UploadedFiles, &uploadedFiles)
-			if err != nil {
-				return nil, v1.ErrDeserializeFileFailed
-			}
-		}
-		articleData := &v1.ArticleData{
-			ArticleID:       article.ArticleID,
-			Title:           article.Title,
-			Content:         article.Content,
-			ContentShort:    article.ContentShort,
-			Author:          Author.Nickname,
-			Category:        category.CategoryName,
-			CategoryID:      article.CategoryID,
-			Importance:      article.Importance,
-			VisibleRange:    article.VisibleRange,
-			CommentDisabled: article.CommentDisabled,
-			SourceURI:       article.SourceURI,
-			UploadedFiles:   uploadedFiles,
-			Status:          article.Status,
-			CreatedAt:       utils.TimeFormat(article.CreatedAt, utils.FormatDateTime),
-			UpdatedAt:       utils.TimeFormat(article.UpdatedAt, utils.FormatDateTime),
-		}
-		articleList = append(articleList, articleData)
+	articleList, err := s.buildArticleDataList(ctx, viewer, articles)
+	if err != nil {
+		return nil, err
 	}
 
 	// 构建返回结构
@@ -322,63 +538,42 @@ func (s *articleService) GetArticleListByCategory(ctx context.Context, req *v1.G
 			PageSize:   pageSize,
 		},
 	}
+	if len(articles) == pageSize {
+		response.NextCursor = service.EncodeCursor(articles[len(articles)-1].ArticleID)
+	}
 
 	return response, nil
 }
 
-// page初始化
-func initPage(pageIndex int, pageSize int) (int, int) {
-	if pageIndex < 1 {
-		pageIndex = 1
+// decodeIdCursor 解析按文章ID排序的分页游标
+func decodeIdCursor(cursor string) (uint, error) {
+	values, err := service.DecodeCursor(cursor)
+	if err != nil {
+		return 0, err
 	}
-	if pageSize < 10 {
-		pageSize = 10
+	number, ok := values[0].(json.Number)
+	if !ok {
+		return 0, v1.ErrCursorInvalid
 	}
-	if pageSize > 100 {
-		pageSize = 100
+	id, err := number.Int64()
+	if err != nil || id <= 0 {
+		return 0, v1.ErrCursorInvalid
 	}
-	return pageIndex, pageSize
+	return uint(id), nil
 }
+
 func (s *articleService) GetUserArticleList(ctx context.Context, userId string, req *v1.GetUserArticleListReq) (*v1.ArticleList, error) {
 	// 查询文章列表及分页信息
-	pageIndex, pageSize := initPage(req.PageIndex, req.PageSize)
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
 	// 查询文章列表
 	articles, total, err := s.articleRepository.GetUserArticleList(ctx, userId, req, pageIndex, pageSize)
 	if err != nil {
 		return nil, v1.ErrQueryFailed
 	}
-	// 映射文章数据
-	Author, _ := s.userRepo.GetByUserId(ctx, userId)
-	var articleList []*v1.ArticleData
-	for _, article := range articles {
-		// 获取分类名称
-		category, _ := s.articleRepository.GetCategory(ctx, article.CategoryID)
-		// 反序列化上传的文件列表
-		var uploadedFiles []v1.FileUpload
-		if len(article.UploadedFiles) > 0 {
-			err = json.Unmarshal(article.UploadedFiles, &uploadedFiles)
-			if err != nil {
-				return nil, v1.ErrDeserializeFileFailed
-			}
-		}
-		articleData := &v1.ArticleData{
-			ArticleID:       article.ArticleID,
-			Title:           article.Title,
-			Content:         article.Content,
-			ContentShort:    article.ContentShort,
-			Author:          Author.Nickname,
-			Category:        category.CategoryName,
-			CategoryID:      article.CategoryID,
-			Importance:      article.Importance,
-			VisibleRange:    article.VisibleRange,
-			CommentDisabled: article.CommentDisabled,
-			SourceURI:       article.SourceURI,
-			UploadedFiles:   uploadedFiles,
-			Status:          article.Status,
-			CreatedAt:       utils.TimeFormat(article.CreatedAt, utils.FormatDateTime),
-			UpdatedAt:       utils.TimeFormat(article.UpdatedAt, utils.FormatDateTime),
-		}
-		articleList = append(articleList, articleData)
+	// 映射文章数据，个人文章列表中都是自己的文章
+	articleList, err := s.buildArticleDataList(ctx, &model.User{UserId: userId}, articles)
+	if err != nil {
+		return nil, err
 	}
 	// 构建返回结构
 	response := &v1.ArticleList{
@@ -392,94 +587,80 @@ func (s *articleService) GetUserArticleList(ctx context.Context, userId string,
 	return response, nil
 }
 
-func (s *articleService) GetArticleListByEs(ctx context.Context, req *v1.GetArticleListByEsReq) (*v1.SearchArticleResp, error) {
+func (s *articleService) GetArticleListByEs(ctx context.Context, userId string, req *v1.GetArticleListByEsReq) (*v1.SearchArticleResp, error) {
 	// 1. 设置分页信息
-	pageNo, pageSize := initPage(req.PageIndex, req.PageSize)
-
-	// 2. 构建查询条件
-	query := elastic.NewBoolQuery()
-
-	if req.AdvSearch { // 高级搜索，必须满足所有条件
-		// 根据标题进行搜索
-		if req.Title != "" {
-			query = query.Should(elastic.NewMatchQuery("title", req.Title))
-		}
-
-		// 根据内容进行搜索
-		if req.Content != "" {
-			query = query.Should(elastic.NewMatchQuery("content", req.Content))
-		}
+	pageNo, pageSize := service.InitPage(req.PageIndex, req.PageSize)
 
-		// 根据关键字进行全文搜索
-		if len(req.Keywords) > 0 {
-			for _, keyword := range req.Keywords {
-				if req.PhraseMatch {
-					query = query.Must(elastic.NewMatchPhraseQuery("contentShort", keyword))
-				} else {
-					query = query.Should(elastic.NewMatchQuery("contentShort", keyword))
-				}
-			}
-		}
-		// 根据发布时间进行范围过滤
-		if req.CreateTimeStart != "" && req.CreateTimeEnd != "" {
-			query = query.Filter(elastic.NewRangeQuery("created_at").
-				Gte(req.CreateTimeStart).Lte(req.CreateTimeEnd))
-		}
-
-		// 根据重要性进行过滤
-		importance, _ := utils.ToInt(req.Importance)
-		if importance > 0 {
-			query = query.Filter(elastic.NewTermsQuery("importance", importance))
-		}
-	} else { // 普通搜索，只要满足一个条件即可
-		// 根据关键字进行全文搜索
-		if len(req.Keywords) > 0 {
-			for _, keyword := range req.Keywords {
-				if req.PhraseMatch {
-					query = query.Must(elastic.NewMatchPhraseQuery("content", keyword)).
-						Must(elastic.NewMatchPhraseQuery("title", keyword)).
-						Must(elastic.NewMatchPhraseQuery("contentShort", keyword))
-				} else {
-					query = query.Should(elastic.NewMatchQuery("content", keyword)).
-						Should(elastic.NewMatchQuery("title", keyword)).
-						Should(elastic.NewMatchQuery("contentShort", keyword))
-				}
-			}
-		}
+	// 2. 构建查询条件，按当前用户过滤可见的文章
+	viewer, err := getViewer(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
 	}
+	query := s.searchBoosts.buildSearchQuery(req, viewer)
 
-	// 根据分类 ID 进行过滤
-	if len(req.Categories) > 0 {
-		var categories []interface{}
-		for _, category := range req.Categories {
-			categories = append(categories, category)
-		}
-		query = query.Filter(elastic.NewTermsQuery("category_id", categories...))
+	// 统计命中文章的标签分布
+	aggs := map[string]elastic.Aggregation{
+		"tags": elastic.NewTermsAggregation().Field(esTagsField).Size(tagFacetSize),
 	}
 
 	// 3. 添加高亮查询
-	highlight := elastic.NewHighlight().
-		Field("content").PreTags("<mark>").PostTags("</mark>").
-		Field("title").PreTags("<mark>").PostTags("</mark>").
-		Field("contentShort").PreTags("<mark>").PostTags("</mark>")
+	highlight := s.searchBoosts.highlight()
 
-	// 4. 设置分页查询
+	// 4. 设置排序和分页，设置游标时使用 search_after 继续查询
+	sorters := buildSearchSorters(req.Column, req.Order)
 	from := (pageNo - 1) * pageSize
+	var searchAfter []interface{}
+	if req.Cursor != "" {
+		if searchAfter, err = service.DecodeCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+		if len(searchAfter) != len(sorters) {
+			return nil, v1.ErrCursorInvalid
+		}
+		pageNo, from = 0, 0
+	}
 
 	// 5. 调用 repository 中的查询方法
-	searchResult, err := s.articleRepository.GetArticleListByEs(ctx, query, highlight, from, pageSize)
+	searchResult, err := s.articleRepository.GetArticleListByEs(ctx, query, highlight, aggs, sorters, searchAfter, from, pageSize)
 	if err != nil {
 		return nil, err
 	}
 
-	// 6. 解析搜索结果，构建响应数据
-	var articles []v1.ArticleSearchInfo
+	// 6. 解析搜索结果，批量加载作者、分类和评论数后构建响应数据
+	hits := make([]*elastic.SearchHit, 0, len(searchResult.Hits.Hits))
+	esArticles := make([]model.EsArticle, 0, len(searchResult.Hits.Hits))
+	userIds := make([]string, 0, len(searchResult.Hits.Hits))
+	categoryIds := make([]uint, 0, len(searchResult.Hits.Hits))
+	articleIds := make([]uint, 0, len(searchResult.Hits.Hits))
 	for _, hit := range searchResult.Hits.Hits {
 		var esArticle model.EsArticle
 		if err := json.Unmarshal(hit.Source, &esArticle); err != nil {
 			continue
 		}
-
+		hits = append(hits, hit)
+		esArticles = append(esArticles, esArticle)
+		userIds = append(userIds, esArticle.UserID)
+		categoryIds = append(categoryIds, esArticle.CategoryID)
+		articleIds = append(articleIds, esArticle.ArticleID)
+	}
+	loader := s.newArticleLoader()
+	if err = loader.loadAuthors(ctx, userIds); err != nil {
+		return nil, err
+	}
+	if err = loader.loadCategories(ctx, categoryIds); err != nil {
+		return nil, err
+	}
+	commentCounts, err := s.commentRepository.CountByArticleIds(ctx, articleIds)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	views, err := s.articleStatRepository.GetViews(ctx, articleIds)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	var articles []v1.ArticleSearchInfo
+	for i, hit := range hits {
+		esArticle := esArticles[i]
 		article := v1.ArticleSearchInfo{
 			Title:           esArticle.Title,
 			Content:         esArticle.Content,
@@ -490,45 +671,149 @@ func (s *articleService) GetArticleListByEs(ctx context.Context, req *v1.GetArti
 			ArticleID:       esArticle.ArticleID,
 			CreatedAt:       esArticle.CreatedAt,
 			UpdatedAt:       esArticle.UpdatedAt,
-			Author:          "",
-			Category:        "",
+			Author:          loader.authors[esArticle.UserID],
+			Category:        loader.categories[esArticle.CategoryID],
 			Importance:      esArticle.Importance,
 			CommentDisabled: esArticle.CommentDisabled,
 			SourceURI:       esArticle.SourceURI,
+			Tags:            esArticle.Tags,
+			Comments:        commentCounts[esArticle.ArticleID],
+			Views:           views[esArticle.ArticleID],
 		}
 
 		// 获取并设置评分
-		user, _ := s.userRepo.GetByUserId(ctx, esArticle.UserID)
-		article.Author = user.Nickname
-		category, _ := s.articleRepository.GetCategory(ctx, esArticle.CategoryID)
-		article.Category = category.CategoryName
-		article.Score = *hit.Score
+		if hit.Score != nil {
+			article.Score = *hit.Score
+		}
 
 		// 获取高亮内容
-		if highlightFields, ok := hit.Highlight["content"]; ok {
-			article.Content = strings.Join(highlightFields, "...")
-		}
+		s.searchBoosts.applyHighlight(&article, hit)
+		article.AttachmentHits = parseAttachmentHits(hit, esArticle.Attachments)
 
-		if highlightFields, ok := hit.Highlight["title"]; ok {
-			article.Title = strings.Join(highlightFields, "...")
-		}
+		articles = append(articles, article)
+	}
 
-		if highlightFields, ok := hit.Highlight["contentShort"]; ok {
-			article.ContentShort = strings.Join(highlightFields, "...")
+	// 7. 解析标签聚合
+	var tagFacets []v1.TagFacet
+	if tagAgg, ok := searchResult.Aggregations.Terms("tags"); ok {
+		for _, bucket := range tagAgg.Buckets {
+			tagName, _ := bucket.Key.(string)
+			tagFacets = append(tagFacets, v1.TagFacet{TagName: tagName, Count: bucket.DocCount})
 		}
-
-		articles = append(articles, article)
 	}
 
-	// 7. 构建分页响应
+	// 8. 构建分页响应
 	resp := &v1.SearchArticleResp{
 		PageResponse: v1.PageResponse{
 			TotalCount: searchResult.Hits.TotalHits.Value,
 			PageIndex:  pageNo,
 			PageSize:   pageSize,
 		},
-		Articles: articles,
+		Articles:  articles,
+		TagFacets: tagFacets,
+	}
+	if hits := searchResult.Hits.Hits; len(hits) == pageSize {
+		resp.NextCursor = service.EncodeCursor(hits[len(hits)-1].Sort...)
 	}
 
 	return resp, nil
 }
+
+// buildArticleData 将文章映射为返回数据，viewer 为当前用户
+func (s *articleService) buildArticleData(ctx context.Context, viewer *model.User, article *model.Article) (*v1.ArticleData, error) {
+	articleList, err := s.buildArticleDataList(ctx, viewer, []model.Article{*article})
+	if err != nil {
+		return nil, err
+	}
+	return articleList[0], nil
+}
+
+// buildArticleDataList 将一页文章映射为返回数据，作者、分类、评论数和标签各批量查询一次
+func (s *articleService) buildArticleDataList(ctx context.Context, viewer *model.User, articles []model.Article) ([]*v1.ArticleData, error) {
+	loader := s.newArticleLoader()
+	loader.viewer = viewer
+	if err := loader.loadArticles(ctx, articles); err != nil {
+		return nil, err
+	}
+	var articleList []*v1.ArticleData
+	for i := range articles {
+		articleData, err := loader.buildArticleData(&articles[i])
+		if err != nil {
+			return nil, err
+		}
+		articleList = append(articleList, articleData)
+	}
+	return articleList, nil
+}
+
+// syncEsArticle 写入文章的es同步任务，需与文章修改在同一事务中调用
+// task 服务按文章最新状态同步：已发布的文章写入es，否则从es中删除，可见范围在搜索时过滤
+func (s *articleService) syncEsArticle(ctx context.Context, article *model.Article) error {
+	return s.esOutboxRepository.Enqueue(ctx, article.ArticleID)
+}
+
+// GetEsSyncStatus 查询es同步任务的积压和延迟
+func (s *articleService) GetEsSyncStatus(ctx context.Context) (*v1.EsSyncStatusData, error) {
+	stats, err := s.esOutboxRepository.GetOutboxStats(ctx)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	data := &v1.EsSyncStatusData{
+		Pending:  stats.Pending,
+		Retrying: stats.Retrying,
+	}
+	if stats.OldestCreatedAt != nil {
+		data.OldestCreatedAt = utils.TimeFormat(*stats.OldestCreatedAt, utils.FormatDateTime)
+		data.LagSeconds = int64(time.Since(*stats.OldestCreatedAt).Seconds())
+	}
+	return data, nil
+}
+
+// buildAttachmentQuery 由搜索关键字和高级搜索的内容条件构建附件文本的 nested 查询，没有条件时返回 nil
+func buildAttachmentQuery(req *v1.GetArticleListByEsReq, boost float64) elastic.Query {
+	texts := append([]string{}, req.Keywords...)
+	if req.AdvSearch && req.Content != "" {
+		texts = append(texts, req.Content)
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+	inner := elastic.NewBoolQuery()
+	for _, text := range texts {
+		if req.PhraseMatch {
+			inner = inner.Should(elastic.NewMatchPhraseQuery(esAttachmentContentField, text))
+		} else {
+			inner = inner.Should(elastic.NewMatchQuery(esAttachmentContentField, text))
+		}
+	}
+	return elastic.NewNestedQuery(esAttachmentsField, inner).
+		ScoreMode("max").
+		Boost(boost).
+		InnerHit(elastic.NewInnerHit().
+			Name(esAttachmentInnerHit).
+			Size(attachmentHitSize).
+			FetchSource(false).
+			Highlight(elastic.NewHighlight().
+				Field(esAttachmentContentField).PreTags("<mark>").PostTags("</mark>")))
+}
+
+// parseAttachmentHits 解析命中的附件，文件名从文档的附件列表中按 nested 下标获取
+func parseAttachmentHits(hit *elastic.SearchHit, attachments []model.EsAttachment) []v1.AttachmentHit {
+	innerHits, ok := hit.InnerHits[esAttachmentInnerHit]
+	if !ok || innerHits.Hits == nil {
+		return nil
+	}
+	var result []v1.AttachmentHit
+	for _, innerHit := range innerHits.Hits.Hits {
+		if innerHit.Nested == nil || innerHit.Nested.Offset >= len(attachments) {
+			continue
+		}
+		attachment := attachments[innerHit.Nested.Offset]
+		result = append(result, v1.AttachmentHit{
+			AttachmentId: attachment.AttachmentId,
+			FileName:     attachment.FileName,
+			Highlight:    innerHit.Highlight[esAttachmentContentField],
+		})
+	}
+	return result
+}
@@ -0,0 +1,379 @@
+package article
+
+import (
+	"context"
+	"encoding/base64"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/internal/service"
+	"projectName/pkg/log"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testEnv 使用内存 sqlite 和 miniredis 的文章服务，es 同步只写入 outbox
+type testEnv struct {
+	*articleService
+	db  *gorm.DB
+	rdb *redis.Client
+}
+
+func newTestEnv(t *testing.T, conf *viper.Viper) *testEnv {
+	if conf == nil {
+		conf = viper.New()
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库每个连接独立，只保留一个连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(
+		&model.User{},
+		&model.Category{},
+		&model.Article{},
+		&model.Notification{},
+		&model.ArticleRevision{},
+		&model.Comment{},
+		&model.Tag{},
+		&model.ArticleTag{},
+		&model.Attachment{},
+		&model.ArticleShare{},
+		&model.EsOutbox{},
+		&model.ArticleStat{},
+		&model.ArticleLike{},
+		&model.FavoriteFolder{},
+		&model.ArticleFavorite{},
+	))
+	// 分类树视图由数据库脚本创建，测试中只需要分类名称
+	require.NoError(t, db.Exec("CREATE VIEW view_category_tree AS "+
+		"SELECT category_id, category_name, parent_id, 0 AS level FROM kb_category WHERE is_deleted = 0").Error)
+
+	l := &log.Logger{Logger: zap.NewNop()}
+	repo := repository.NewRepository(l, db, rdb, nil)
+	svc := NewArticleService(
+		service.NewService(repository.NewTransaction(repo), l, nil, nil),
+		conf,
+		repository.NewArticleRepository(repo, conf),
+		repository.NewUserRepository(repo),
+		repository.NewNotificationRepository(repo),
+		repository.NewArticleRevisionRepository(repo),
+		repository.NewCommentRepository(repo),
+		repository.NewTagRepository(repo),
+		repository.NewAttachmentRepository(repo),
+		repository.NewEsOutboxRepository(repo),
+		repository.NewArticleStatRepository(repo),
+		repository.NewArticleLikeRepository(repo),
+		repository.NewFavoriteRepository(repo),
+	)
+	return &testEnv{articleService: svc.(*articleService), db: db, rdb: rdb}
+}
+
+func (e *testEnv) createUser(t *testing.T, userId string, roleType int, collegeId uint) *model.User {
+	user := &model.User{UserId: userId, Phone: userId, Nickname: userId, RoleType: roleType, CollegeId: collegeId}
+	require.NoError(t, e.db.Create(user).Error)
+	return user
+}
+
+func (e *testEnv) createCategory(t *testing.T, name string, needReview int) uint {
+	category := &model.Category{CategoryName: name, NeedReview: needReview}
+	require.NoError(t, e.db.Create(category).Error)
+	return category.CId
+}
+
+func (e *testEnv) createArticle(t *testing.T, authorId string, title string, categoryId uint) uint {
+	id, err := e.CreateArticle(context.Background(), &v1.CreateArticleRequest{
+		Title:        title,
+		Content:      "content of " + title,
+		AuthorID:     authorId,
+		CategoryID:   categoryId,
+		VisibleRange: v1.Visibility{Scope: enums.VisiblePublic},
+	})
+	require.NoError(t, err)
+	return uint(id)
+}
+
+func (e *testEnv) articleStatus(t *testing.T, articleId uint) int {
+	article, err := e.articleRepository.GetArticleFromDB(context.Background(), articleId)
+	require.NoError(t, err)
+	return article.Status
+}
+
+func TestUpdateArticle(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "other", enums.COMMON_USER, 1)
+	e.createUser(t, "super", enums.SUPER_ADMIN, 0)
+	categoryId := e.createCategory(t, "c", 0)
+	published := e.createArticle(t, "author", "published", categoryId)
+	draft, err := e.SaveDraft(ctx, "author", &v1.SaveDraftRequest{Title: "draft"})
+	require.NoError(t, err)
+
+	update := func(articleId uint, title string) *v1.UpdateArticleRequest {
+		return &v1.UpdateArticleRequest{
+			ArticleID: articleId,
+			CreateArticleRequest: v1.CreateArticleRequest{
+				Title:        title,
+				Content:      "new content",
+				CategoryID:   categoryId,
+				VisibleRange: v1.Visibility{Scope: enums.VisiblePublic},
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		userId   string
+		roleType int
+		req      *v1.UpdateArticleRequest
+		wantErr  error
+	}{
+		{"not exist", "author", enums.COMMON_USER, update(999, "x"), v1.ErrArticleNotExist},
+		{"not author", "other", enums.COMMON_USER, update(published, "x"), v1.ErrPermissionDenied},
+		{"draft must be published through PublishArticle", "author", enums.COMMON_USER, update(draft, "x"), v1.ErrArticleIsDraft},
+		{"category not exist", "author", enums.COMMON_USER, func() *v1.UpdateArticleRequest {
+			req := update(published, "x")
+			req.CategoryID = 999
+			return req
+		}(), v1.ErrCategoryNotExist},
+		{"author", "author", enums.COMMON_USER, update(published, "by author"), nil},
+		{"super admin", "super", enums.SUPER_ADMIN, update(published, "by super"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := e.UpdateArticle(ctx, tt.userId, tt.roleType, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				require.NotNil(t, data)
+				assert.Equal(t, tt.req.Title, data.Title)
+			}
+		})
+	}
+	assert.Equal(t, enums.StatusDraft, e.articleStatus(t, draft))
+}
+
+func TestDecodeIdCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		want    uint
+		wantErr error
+	}{
+		{"id", service.EncodeCursor(uint(42)), 42, nil},
+		{"extra values ignored", service.EncodeCursor(uint(7), "x"), 7, nil},
+		{"zero", service.EncodeCursor(0), 0, v1.ErrCursorInvalid},
+		{"negative", service.EncodeCursor(-1), 0, v1.ErrCursorInvalid},
+		{"fraction", service.EncodeCursor(1.5), 0, v1.ErrCursorInvalid},
+		{"overflow", base64.RawURLEncoding.EncodeToString([]byte("[99999999999999999999]")), 0, v1.ErrCursorInvalid},
+		{"string", service.EncodeCursor("42"), 0, v1.ErrCursorInvalid},
+		{"malformed", "!!!", 0, v1.ErrCursorInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeIdCursor(tt.cursor)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (e *testEnv) createChildCategory(t *testing.T, name string, parentId uint) uint {
+	category := &model.Category{CategoryName: name, ParentId: parentId}
+	require.NoError(t, e.db.Create(category).Error)
+	return category.CId
+}
+
+func TestCreateCategory(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	root := e.createCategory(t, "root", 0)
+	e.createChildCategory(t, "child", root)
+
+	tests := []struct {
+		name    string
+		req     v1.CreateCategoryReq
+		wantErr error
+	}{
+		{"parent not exist", v1.CreateCategoryReq{CategoryName: "a", ParentId: 999}, v1.ErrCategoryNotExist},
+		{"duplicate sibling", v1.CreateCategoryReq{CategoryName: "child", ParentId: root}, v1.ErrCategoryExist},
+		{"same name under other parent", v1.CreateCategoryReq{CategoryName: "child"}, nil},
+		{"need review", v1.CreateCategoryReq{CategoryName: "review", ParentId: root, NeedReview: true}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := e.CreateCategory(ctx, &tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			category, err := e.articleRepository.GetCategoryById(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.req.ParentId, category.ParentId)
+			assert.Equal(t, tt.req.NeedReview, category.NeedReview == 1)
+		})
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	// a -> b -> c，d 为另一个根分类
+	a := e.createCategory(t, "a", 0)
+	b := e.createChildCategory(t, "b", a)
+	c := e.createChildCategory(t, "c", b)
+	d := e.createCategory(t, "d", 0)
+	e.createChildCategory(t, "x", d)
+
+	uintPtr := func(i uint) *uint { return &i }
+	boolPtr := func(b bool) *bool { return &b }
+	tests := []struct {
+		name       string
+		req        v1.UpdateCategoryReq
+		wantErr    error
+		wantParent uint
+	}{
+		{"not exist", v1.UpdateCategoryReq{CategoryId: 999, CategoryName: "n"}, v1.ErrCategoryNotExist, 0},
+		{"move under itself", v1.UpdateCategoryReq{CategoryId: b, ParentId: uintPtr(b)}, v1.ErrCategoryCycle, a},
+		{"move under child", v1.UpdateCategoryReq{CategoryId: a, ParentId: uintPtr(b)}, v1.ErrCategoryCycle, 0},
+		{"move under grandchild", v1.UpdateCategoryReq{CategoryId: a, ParentId: uintPtr(c)}, v1.ErrCategoryCycle, 0},
+		{"parent not exist", v1.UpdateCategoryReq{CategoryId: b, ParentId: uintPtr(999)}, v1.ErrCategoryNotExist, a},
+		{"rename to sibling name", v1.UpdateCategoryReq{CategoryId: a, CategoryName: "d"}, v1.ErrCategoryExist, 0},
+		{"move next to same name", v1.UpdateCategoryReq{CategoryId: c, CategoryName: "x", ParentId: uintPtr(d)}, v1.ErrCategoryExist, b},
+		{"move subtree", v1.UpdateCategoryReq{CategoryId: b, ParentId: uintPtr(d)}, nil, d},
+		{"old ancestor under moved subtree", v1.UpdateCategoryReq{CategoryId: a, ParentId: uintPtr(c)}, nil, c},
+		{"move to root", v1.UpdateCategoryReq{CategoryId: a, ParentId: uintPtr(0), NeedReview: boolPtr(true)}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := e.UpdateCategory(ctx, &tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.req.CategoryId == 999 {
+				return
+			}
+			category, err := e.articleRepository.GetCategoryById(ctx, tt.req.CategoryId)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantParent, category.ParentId)
+			if tt.req.NeedReview != nil {
+				assert.Equal(t, 1, category.NeedReview)
+			}
+		})
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	parent := e.createCategory(t, "parent", 0)
+	e.createChildCategory(t, "child", parent)
+	full := e.createCategory(t, "full", 0)
+	target := e.createCategory(t, "target", 0)
+	empty := e.createCategory(t, "empty", 0)
+	articleId := e.createArticle(t, "author", "a", full)
+	require.NoError(t, e.db.Where("1 = 1").Delete(&model.EsOutbox{}).Error)
+
+	tests := []struct {
+		name    string
+		req     v1.DeleteCategoryReq
+		wantErr error
+	}{
+		{"not exist", v1.DeleteCategoryReq{CategoryId: 999}, v1.ErrCategoryNotExist},
+		{"has children", v1.DeleteCategoryReq{CategoryId: parent}, v1.ErrCategoryHasChildren},
+		{"has articles", v1.DeleteCategoryReq{CategoryId: full}, v1.ErrCategoryHasArticles},
+		{"move to itself", v1.DeleteCategoryReq{CategoryId: full, MoveToCategoryId: full}, v1.ErrBadRequest},
+		{"move to missing category", v1.DeleteCategoryReq{CategoryId: full, MoveToCategoryId: 999}, v1.ErrCategoryNotExist},
+		{"empty", v1.DeleteCategoryReq{CategoryId: empty}, nil},
+		{"move articles", v1.DeleteCategoryReq{CategoryId: full, MoveToCategoryId: target}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, e.DeleteCategory(ctx, &tt.req))
+		})
+	}
+
+	for _, id := range []uint{empty, full} {
+		_, err := e.articleRepository.GetCategoryById(ctx, id)
+		assert.Equal(t, v1.ErrNotFound, err)
+	}
+	article, err := e.articleRepository.GetArticleFromDB(ctx, articleId)
+	require.NoError(t, err)
+	assert.Equal(t, target, article.CategoryID)
+	// 移动后的文章需要重新同步到 es
+	var outbox []uint
+	require.NoError(t, e.db.Model(&model.EsOutbox{}).Pluck("article_id", &outbox).Error)
+	assert.Equal(t, []uint{articleId}, outbox)
+}
+
+func TestGetArticle_Visibility(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "classmate", enums.SUTDENT_USER, 1)
+	e.createUser(t, "other", enums.SUTDENT_USER, 2)
+	e.createUser(t, "admin", enums.SCHOOL_ADMIN, 2)
+	categoryId := e.createCategory(t, "c", 0)
+	create := func(title string, visibility v1.Visibility) uint {
+		id, err := e.CreateArticle(ctx, &v1.CreateArticleRequest{
+			Title:        title,
+			Content:      "content",
+			AuthorID:     "author",
+			CategoryID:   categoryId,
+			VisibleRange: visibility,
+		})
+		require.NoError(t, err)
+		return uint(id)
+	}
+	public := create("public", v1.Visibility{Scope: enums.VisiblePublic})
+	private := create("private", v1.Visibility{Scope: enums.VisiblePrivate})
+	college := create("college", v1.Visibility{Scope: enums.VisibleCollege})
+	role := create("role", v1.Visibility{Scope: enums.VisibleRole, MinRole: enums.SCHOOL_ADMIN})
+	draft, err := e.SaveDraft(ctx, "author", &v1.SaveDraftRequest{Title: "draft"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		userId    string
+		articleId uint
+		wantErr   error
+	}{
+		{"public", "other", public, nil},
+		{"private to author", "author", private, nil},
+		{"private to other", "classmate", private, v1.ErrPermissionDenied},
+		{"college to same college", "classmate", college, nil},
+		{"college to other college", "other", college, v1.ErrPermissionDenied},
+		{"role below min role", "classmate", role, v1.ErrPermissionDenied},
+		{"role at min role", "admin", role, nil},
+		{"draft to author", "author", draft, nil},
+		{"draft to other", "classmate", draft, v1.ErrArticleStatusError},
+		{"unknown viewer", "gone", public, v1.ErrPermissionDenied},
+		{"not exist", "author", 999, v1.ErrArticleNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := e.GetArticle(ctx, tt.userId, tt.articleId)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tt.articleId, data.ArticleID)
+			}
+		})
+	}
+
+	// 所有已发布的文章都写入es，搜索时按可见范围过滤
+	var outbox []uint
+	require.NoError(t, e.db.Model(&model.EsOutbox{}).Order("article_id").Pluck("article_id", &outbox).Error)
+	assert.Equal(t, []uint{public, private, college, role}, outbox)
+}
@@ -0,0 +1,287 @@
+package article
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	v1 "projectName/api/v1"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/internal/service"
+	"projectName/pkg/storage"
+	"projectName/pkg/utils"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxFileSize    = 20 << 20 // 默认单个附件最大 20MB
+	defaultDownloadExpire = 10 * time.Minute
+	signKeyEnv            = "STORAGE_SIGN_KEY" // 下载地址签名密钥的环境变量
+
+	attachmentHitSize        = 3 // 每篇文章返回的命中附件数量
+	esAttachmentInnerHit     = "attachments"
+	esAttachmentContentField = "attachments.content"
+)
+
+// 默认允许上传的文件类型
+var defaultAllowedTypes = []string{
+	"application/pdf",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"text/plain",
+	"text/markdown",
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+}
+
+type AttachmentService interface {
+	MaxFileSize() int64
+	UploadFile(ctx context.Context, userId string, fileHeader *multipart.FileHeader) (*v1.UploadFileResponseData, error)
+	GetDownloadUrl(ctx context.Context, userId string, attachmentId uint) (*v1.GetDownloadUrlResponseData, error)
+	OpenDownload(ctx context.Context, attachmentId uint, expires int64, sign string) (*model.Attachment, io.ReadCloser, error)
+}
+
+func NewAttachmentService(
+	service *service.Service,
+	conf *viper.Viper,
+	storage storage.Storage,
+	attachmentRepository repository.AttachmentRepository,
+	articleService ArticleService,
+) (AttachmentService, error) {
+	maxFileSize := int64(conf.GetSizeInBytes("storage.max_size"))
+	if maxFileSize <= 0 {
+		maxFileSize = defaultMaxFileSize
+	}
+	allowedTypes := conf.GetStringSlice("storage.allowed_types")
+	if len(allowedTypes) == 0 {
+		allowedTypes = defaultAllowedTypes
+	}
+	downloadExpire := conf.GetDuration("storage.download_expire")
+	if downloadExpire <= 0 {
+		downloadExpire = defaultDownloadExpire
+	}
+	// 生产环境的签名密钥通过环境变量设置，优先于配置文件；为空时任何人都能伪造下载地址，拒绝启动
+	_ = conf.BindEnv("storage.sign_key", signKeyEnv)
+	signKey := conf.GetString("storage.sign_key")
+	if signKey == "" {
+		return nil, fmt.Errorf("storage: download sign key is empty, set it via %s or storage.sign_key", signKeyEnv)
+	}
+	return &attachmentService{
+		Service:              service,
+		storage:              storage,
+		attachmentRepository: attachmentRepository,
+		articleService:       articleService,
+		maxFileSize:          maxFileSize,
+		allowedTypes:         allowedTypes,
+		downloadExpire:       downloadExpire,
+		signKey:              []byte(signKey),
+	}, nil
+}
+
+type attachmentService struct {
+	*service.Service
+	storage              storage.Storage
+	attachmentRepository repository.AttachmentRepository
+	articleService       ArticleService
+	maxFileSize          int64         // 单个附件最大字节数
+	allowedTypes         []string      // 允许上传的 MIME 类型
+	downloadExpire       time.Duration // 下载地址有效期
+	signKey              []byte        // 下载地址签名密钥
+}
+
+func (s *attachmentService) MaxFileSize() int64 {
+	return s.maxFileSize
+}
+
+// UploadFile 保存上传的附件，按内容校验和去重，相同内容只存储一份
+func (s *attachmentService) UploadFile(ctx context.Context, userId string, fileHeader *multipart.FileHeader) (*v1.UploadFileResponseData, error) {
+	if fileHeader.Size > s.maxFileSize {
+		return nil, v1.ErrFileTooLarge
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+	defer file.Close()
+
+	// 按文件内容识别类型，不信任客户端提供的 Content-Type
+	mime, err := mimetype.DetectReader(file)
+	if err != nil {
+		return nil, v1.ErrBadRequest
+	}
+	mimeType := s.matchAllowedType(mime, fileHeader.Filename)
+	if mimeType == "" {
+		return nil, v1.ErrFileTypeNotAllowed
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, v1.ErrStorageFailed
+	}
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return nil, v1.ErrStorageFailed
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	storageKey, err := s.attachmentRepository.GetStorageKeyByChecksum(ctx, checksum)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	if storageKey == "" {
+		storageKey = fmt.Sprintf("attachments/%s/%s%s", checksum[:2], checksum, strings.ToLower(filepath.Ext(fileHeader.Filename)))
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return nil, v1.ErrStorageFailed
+		}
+		if err = s.storage.Put(ctx, storageKey, file, fileHeader.Size, mimeType); err != nil {
+			s.Logger.WithContext(ctx).Error("attachmentService.UploadFile put error", zap.Error(err))
+			return nil, v1.ErrStorageFailed
+		}
+	}
+
+	attachment := &model.Attachment{
+		UserId:     userId,
+		FileName:   filepath.Base(fileHeader.Filename),
+		Checksum:   checksum,
+		Size:       fileHeader.Size,
+		MimeType:   mimeType,
+		StorageKey: storageKey,
+	}
+	if err = s.attachmentRepository.CreateAttachment(ctx, attachment); err != nil {
+		return nil, v1.ErrInsertFailed
+	}
+	return &v1.UploadFileResponseData{
+		AttachmentId: attachment.Id,
+		FileName:     attachment.FileName,
+		FileURL:      attachmentURL(attachment.Id),
+		Size:         attachment.Size,
+		MimeType:     attachment.MimeType,
+	}, nil
+}
+
+// matchAllowedType 返回识别出的类型或其父类型中被允许的一个，Markdown 会被识别为纯文本，按扩展名区分
+func (s *attachmentService) matchAllowedType(mime *mimetype.MIME, fileName string) string {
+	for m := mime; m != nil; m = m.Parent() {
+		mimeType := strings.Split(m.String(), ";")[0]
+		if mimeType == "text/plain" && strings.EqualFold(filepath.Ext(fileName), ".md") &&
+			utils.ContainsString(s.allowedTypes, "text/markdown") {
+			return "text/markdown"
+		}
+		if utils.ContainsString(s.allowedTypes, mimeType) {
+			return mimeType
+		}
+	}
+	return ""
+}
+
+// GetDownloadUrl 校验当前用户能否查看附件后，生成限时有效的签名下载地址
+// 未关联文章的附件仅上传人可以下载，已关联的附件与文章的可见范围一致
+func (s *attachmentService) GetDownloadUrl(ctx context.Context, userId string, attachmentId uint) (*v1.GetDownloadUrlResponseData, error) {
+	attachment, err := s.attachmentRepository.GetAttachmentById(ctx, attachmentId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrAttachmentNotExist
+		}
+		return nil, v1.ErrQueryFailed
+	}
+	if attachment.ArticleID == 0 {
+		if attachment.UserId != userId {
+			return nil, v1.ErrPermissionDenied
+		}
+	} else if err = s.articleService.CheckArticleVisible(ctx, userId, attachment.ArticleID); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.downloadExpire)
+	return &v1.GetDownloadUrlResponseData{
+		DownloadURL: fmt.Sprintf("/v1/file/download?id=%d&expires=%d&sign=%s",
+			attachment.Id, expiresAt.Unix(), s.sign(attachment.Id, expiresAt.Unix())),
+		ExpiresAt: utils.TimeFormat(expiresAt, utils.FormatDateTime),
+	}, nil
+}
+
+// OpenDownload 校验下载地址签名后打开附件内容，调用方负责关闭
+func (s *attachmentService) OpenDownload(ctx context.Context, attachmentId uint, expires int64, sign string) (*model.Attachment, io.ReadCloser, error) {
+	if time.Now().Unix() > expires || !hmac.Equal([]byte(sign), []byte(s.sign(attachmentId, expires))) {
+		return nil, nil, v1.ErrDownloadUrlInvalid
+	}
+	attachment, err := s.attachmentRepository.GetAttachmentById(ctx, attachmentId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, nil, v1.ErrAttachmentNotExist
+		}
+		return nil, nil, v1.ErrQueryFailed
+	}
+	reader, err := s.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		s.Logger.WithContext(ctx).Error("attachmentService.OpenDownload get error", zap.Error(err))
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, nil, v1.ErrAttachmentNotExist
+		}
+		return nil, nil, v1.ErrStorageFailed
+	}
+	return attachment, reader, nil
+}
+
+func (s *attachmentService) sign(attachmentId uint, expires int64) string {
+	h := hmac.New(sha256.New, s.signKey)
+	h.Write([]byte(fmt.Sprintf("%d:%d", attachmentId, expires)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// attachmentURL 文章中保存的附件地址，前端通过该接口换取签名下载地址
+func attachmentURL(attachmentId uint) string {
+	return fmt.Sprintf("/v1/file/getDownloadUrl?id=%d", attachmentId)
+}
+
+// resolveUploadedFiles 校验文章引用的附件属于 owners 且未被其他文章使用，返回序列化后的文件列表和需要关联的附件ID
+// 没有附件ID的文件视为外部链接，原样保存
+func (s *articleService) resolveUploadedFiles(ctx context.Context, files []v1.FileUpload, articleId uint, owners ...string) ([]byte, []uint, error) {
+	var attachmentIds []uint
+	for _, file := range files {
+		if file.AttachmentId != 0 {
+			attachmentIds = append(attachmentIds, file.AttachmentId)
+		}
+	}
+	attachments, err := s.attachmentRepository.GetAttachmentsByIds(ctx, attachmentIds)
+	if err != nil {
+		return nil, nil, v1.ErrQueryFailed
+	}
+	attachmentMap := make(map[uint]*model.Attachment, len(attachments))
+	for i := range attachments {
+		attachmentMap[attachments[i].Id] = &attachments[i]
+	}
+	for i := range files {
+		if files[i].AttachmentId == 0 {
+			continue
+		}
+		attachment, ok := attachmentMap[files[i].AttachmentId]
+		if !ok {
+			return nil, nil, v1.ErrAttachmentNotExist
+		}
+		if !utils.ContainsString(owners, attachment.UserId) ||
+			(attachment.ArticleID != 0 && attachment.ArticleID != articleId) {
+			return nil, nil, v1.ErrPermissionDenied
+		}
+		if files[i].FileName == "" {
+			files[i].FileName = attachment.FileName
+		}
+		files[i].FileURL = attachmentURL(attachment.Id)
+	}
+	uploadedFilesData, err := json.Marshal(files)
+	if err != nil {
+		return nil, nil, v1.ErrUploadFileFailed
+	}
+	return uploadedFilesData, attachmentIds, nil
+}
@@ -0,0 +1,38 @@
+package article
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAttachmentService_SignKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{"missing", "", "", "", true},
+		{"from config", "config-key", "", "config-key", false},
+		{"env overrides config", "config-key", "env-key", "env-key", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(signKeyEnv, tt.env)
+			conf := viper.New()
+			conf.SetDefault("storage.sign_key", tt.config)
+			svc, err := NewAttachmentService(nil, conf, nil, nil, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, svc)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []byte(tt.want), svc.(*attachmentService).signKey)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package article
+
+import (
+	"context"
+	"errors"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/internal/service"
+	"projectName/pkg/utils"
+	"strings"
+)
+
+type CommentService interface {
+	CreateComment(ctx context.Context, userId string, req *v1.CreateCommentReq) (uint, error)
+	GetCommentList(ctx context.Context, userId string, req *v1.GetCommentListReq) (*v1.CommentList, error)
+	DeleteComment(ctx context.Context, userId string, roleType int, req *v1.DeleteCommentReq) error
+}
+
+func NewCommentService(
+	service *service.Service,
+	articleRepository repository.ArticleRepository,
+	commentRepository repository.CommentRepository,
+	userRepo repository.UserRepository,
+) CommentService {
+	return &commentService{
+		Service:           service,
+		articleRepository: articleRepository,
+		commentRepository: commentRepository,
+		userRepo:          userRepo,
+	}
+}
+
+type commentService struct {
+	*service.Service
+	articleRepository repository.ArticleRepository
+	commentRepository repository.CommentRepository
+	userRepo          repository.UserRepository
+}
+
+// CreateComment 发表评论或回复，文章禁用评论时不允许发表
+func (s *commentService) CreateComment(ctx context.Context, userId string, req *v1.CreateCommentReq) (uint, error) {
+	article, err := s.getVisibleArticle(ctx, userId, req.ArticleID)
+	if err != nil {
+		return 0, err
+	}
+	if article.CommentDisabled {
+		return 0, v1.ErrCommentDisabled
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return 0, v1.ErrBadRequest
+	}
+
+	comment := &model.Comment{
+		ArticleID: article.ArticleID,
+		UserId:    userId,
+		Content:   content,
+	}
+	// 回复评论时记录所属顶层评论，回复的回复仍挂在同一个顶层评论下
+	if req.ParentId != 0 {
+		parent, err := s.commentRepository.GetCommentById(ctx, req.ParentId)
+		if err != nil {
+			if errors.Is(err, v1.ErrNotFound) {
+				return 0, v1.ErrCommentNotExist
+			}
+			return 0, v1.ErrQueryFailed
+		}
+		if parent.ArticleID != article.ArticleID {
+			return 0, v1.ErrBadRequest
+		}
+		comment.ParentId = parent.Id
+		comment.RootId = parent.RootId
+		if parent.RootId == 0 {
+			comment.RootId = parent.Id
+		}
+		comment.ReplyTo = parent.UserId
+	}
+	if err = s.commentRepository.CreateComment(ctx, comment); err != nil {
+		return 0, v1.ErrInsertFailed
+	}
+	return comment.Id, nil
+}
+
+// GetCommentList 分页获取顶层评论，并带出每条顶层评论下的全部回复
+func (s *commentService) GetCommentList(ctx context.Context, userId string, req *v1.GetCommentListReq) (*v1.CommentList, error) {
+	if _, err := s.getVisibleArticle(ctx, userId, req.ArticleID); err != nil {
+		return nil, err
+	}
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	roots, total, err := s.commentRepository.GetRootCommentList(ctx, req.ArticleID, pageIndex, pageSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	rootIds := make([]uint, 0, len(roots))
+	for _, root := range roots {
+		rootIds = append(rootIds, root.Id)
+	}
+	replies, err := s.commentRepository.GetRepliesByRootIds(ctx, rootIds)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+
+	nicknames := make(map[string]string)
+	var commentList []*v1.CommentData
+	rootMap := make(map[uint]*v1.CommentData, len(roots))
+	for i := range roots {
+		commentData := s.buildCommentData(ctx, &roots[i], nicknames)
+		rootMap[roots[i].Id] = commentData
+		commentList = append(commentList, commentData)
+	}
+	for i := range replies {
+		if root, ok := rootMap[replies[i].RootId]; ok {
+			root.ReplyList = append(root.ReplyList, s.buildCommentData(ctx, &replies[i], nicknames))
+		}
+	}
+	return &v1.CommentList{
+		CommentList: commentList,
+		PageResponse: v1.PageResponse{
+			TotalCount: total,
+			PageIndex:  pageIndex,
+			PageSize:   pageSize,
+		},
+	}, nil
+}
+
+// DeleteComment 删除评论，评论人、文章作者、本学院的学校管理员和超级管理员可以删除
+func (s *commentService) DeleteComment(ctx context.Context, userId string, roleType int, req *v1.DeleteCommentReq) error {
+	comment, err := s.commentRepository.GetCommentById(ctx, req.CommentId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return v1.ErrCommentNotExist
+		}
+		return v1.ErrQueryFailed
+	}
+	if comment.UserId != userId && roleType != enums.SUPER_ADMIN {
+		if err = s.checkArticleManager(ctx, userId, roleType, comment.ArticleID); err != nil {
+			return err
+		}
+	}
+	if err = s.commentRepository.DeleteComment(ctx, comment.Id); err != nil {
+		return v1.ErrDeleteFailed
+	}
+	return nil
+}
+
+// checkArticleManager 校验当前用户能否管理文章下的评论：文章作者，或作者所在学院的学校管理员
+func (s *commentService) checkArticleManager(ctx context.Context, userId string, roleType int, articleId uint) error {
+	article, err := s.articleRepository.GetArticle(ctx, articleId)
+	if err != nil {
+		return v1.ErrPermissionDenied
+	}
+	if article.UserID == userId {
+		return nil
+	}
+	if roleType != enums.SCHOOL_ADMIN {
+		return v1.ErrPermissionDenied
+	}
+	admin, err := s.userRepo.GetByUserId(ctx, userId)
+	if err != nil || admin.CollegeId == 0 {
+		return v1.ErrPermissionDenied
+	}
+	author, err := s.userRepo.GetByUserId(ctx, article.UserID)
+	if err != nil || author.CollegeId != admin.CollegeId {
+		return v1.ErrPermissionDenied
+	}
+	return nil
+}
+
+// getVisibleArticle 获取当前用户可以查看的已发布文章
+func (s *commentService) getVisibleArticle(ctx context.Context, userId string, articleId uint) (*model.Article, error) {
+	article, err := s.articleRepository.GetArticle(ctx, articleId)
+	if err != nil {
+		return nil, v1.ErrArticleNotExist
+	}
+	if article.Status != enums.StatusPublished {
+		return nil, v1.ErrArticleStatusError
+	}
+	if err = checkArticleVisible(ctx, s.userRepo, userId, article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// buildCommentData 映射评论数据，nicknames 缓存本次请求中已查询过的用户昵称
+func (s *commentService) buildCommentData(ctx context.Context, comment *model.Comment, nicknames map[string]string) *v1.CommentData {
+	return &v1.CommentData{
+		CommentId: comment.Id,
+		ParentId:  comment.ParentId,
+		UserId:    comment.UserId,
+		Nickname:  s.getNickname(ctx, comment.UserId, nicknames),
+		ReplyTo:   s.getNickname(ctx, comment.ReplyTo, nicknames),
+		Content:   comment.Content,
+		CreatedAt: utils.TimeFormat(comment.CreatedAt, utils.FormatDateTime),
+	}
+}
+
+func (s *commentService) getNickname(ctx context.Context, userId string, nicknames map[string]string) string {
+	if userId == "" {
+		return ""
+	}
+	if nickname, ok := nicknames[userId]; ok {
+		return nickname
+	}
+	var nickname string
+	if user, err := s.userRepo.GetByUserId(ctx, userId); err == nil {
+		nickname = user.Nickname
+	}
+	nicknames[userId] = nickname
+	return nickname
+}
@@ -0,0 +1,116 @@
+package article
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCommentService(t *testing.T) (*testEnv, CommentService) {
+	e := newTestEnv(t, nil)
+	return e, NewCommentService(e.Service, e.articleRepository, e.commentRepository, e.userRepo)
+}
+
+func TestCreateComment(t *testing.T) {
+	ctx := context.Background()
+	e, s := newTestCommentService(t)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "reader", enums.COMMON_USER, 2)
+	categoryId := e.createCategory(t, "c", 0)
+	articleId := e.createArticle(t, "author", "a", categoryId)
+	otherArticleId := e.createArticle(t, "author", "b", categoryId)
+	create := func(req v1.CreateArticleRequest) uint {
+		req.Content, req.AuthorID, req.CategoryID = "content", "author", categoryId
+		id, err := e.CreateArticle(ctx, &req)
+		require.NoError(t, err)
+		return uint(id)
+	}
+	disabled := create(v1.CreateArticleRequest{Title: "disabled", CommentDisabled: true, VisibleRange: v1.Visibility{Scope: enums.VisiblePublic}})
+	private := create(v1.CreateArticleRequest{Title: "private", VisibleRange: v1.Visibility{Scope: enums.VisiblePrivate}})
+	draft, err := e.SaveDraft(ctx, "author", &v1.SaveDraftRequest{Title: "draft"})
+	require.NoError(t, err)
+	otherRoot, err := s.CreateComment(ctx, "reader", &v1.CreateCommentReq{ArticleID: otherArticleId, Content: "x"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		userId  string
+		req     v1.CreateCommentReq
+		wantErr error
+	}{
+		{"article not exist", "reader", v1.CreateCommentReq{ArticleID: 999, Content: "x"}, v1.ErrArticleNotExist},
+		{"draft", "author", v1.CreateCommentReq{ArticleID: draft, Content: "x"}, v1.ErrArticleStatusError},
+		{"comment disabled", "reader", v1.CreateCommentReq{ArticleID: disabled, Content: "x"}, v1.ErrCommentDisabled},
+		{"comment disabled for author", "author", v1.CreateCommentReq{ArticleID: disabled, Content: "x"}, v1.ErrCommentDisabled},
+		{"private article", "reader", v1.CreateCommentReq{ArticleID: private, Content: "x"}, v1.ErrPermissionDenied},
+		{"blank content", "reader", v1.CreateCommentReq{ArticleID: articleId, Content: "  "}, v1.ErrBadRequest},
+		{"parent not exist", "reader", v1.CreateCommentReq{ArticleID: articleId, ParentId: 999, Content: "x"}, v1.ErrCommentNotExist},
+		{"parent on other article", "reader", v1.CreateCommentReq{ArticleID: articleId, ParentId: otherRoot, Content: "x"}, v1.ErrBadRequest},
+		{"author comments on private article", "author", v1.CreateCommentReq{ArticleID: private, Content: "x"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreateComment(ctx, tt.userId, &tt.req)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestCommentThread(t *testing.T) {
+	ctx := context.Background()
+	e, s := newTestCommentService(t)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "reader", enums.COMMON_USER, 2)
+	e.createUser(t, "admin1", enums.SCHOOL_ADMIN, 1)
+	e.createUser(t, "admin2", enums.SCHOOL_ADMIN, 2)
+	articleId := e.createArticle(t, "author", "a", e.createCategory(t, "c", 0))
+	comment := func(userId string, parentId uint, content string) uint {
+		id, err := s.CreateComment(ctx, userId, &v1.CreateCommentReq{ArticleID: articleId, ParentId: parentId, Content: content})
+		require.NoError(t, err)
+		return id
+	}
+	root := comment("reader", 0, "root")
+	reply := comment("author", root, "reply")
+	// 回复的回复仍挂在顶层评论下
+	comment("reader", reply, "reply to reply")
+	other := comment("author", 0, "other")
+
+	list, err := s.GetCommentList(ctx, "reader", &v1.GetCommentListReq{ArticleID: articleId})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), list.TotalCount)
+	thread := map[string][]string{}
+	for _, root := range list.CommentList {
+		for _, reply := range root.ReplyList {
+			thread[root.Content] = append(thread[root.Content], reply.Nickname+"->"+reply.ReplyTo+":"+reply.Content)
+		}
+	}
+	assert.Equal(t, map[string][]string{"root": {"author->reader:reply", "reader->author:reply to reply"}}, thread)
+
+	tests := []struct {
+		name      string
+		userId    string
+		roleType  int
+		commentId uint
+		wantErr   error
+	}{
+		{"not exist", "reader", enums.COMMON_USER, 999, v1.ErrCommentNotExist},
+		{"other user", "reader", enums.COMMON_USER, other, v1.ErrPermissionDenied},
+		{"admin of other college", "admin2", enums.SCHOOL_ADMIN, other, v1.ErrPermissionDenied},
+		{"admin of author college", "admin1", enums.SCHOOL_ADMIN, other, nil},
+		{"article author deletes thread", "author", enums.COMMON_USER, root, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, s.DeleteComment(ctx, tt.userId, tt.roleType, &v1.DeleteCommentReq{CommentId: tt.commentId}))
+		})
+	}
+	// 删除顶层评论时回复一并删除
+	list, err = s.GetCommentList(ctx, "reader", &v1.GetCommentListReq{ArticleID: articleId})
+	require.NoError(t, err)
+	assert.Empty(t, list.CommentList)
+	assert.Equal(t, v1.ErrCommentNotExist, s.DeleteComment(ctx, "reader", enums.COMMON_USER, &v1.DeleteCommentReq{CommentId: reply}))
+}
@@ -0,0 +1,129 @@
+package article
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"strings"
+)
+
+// SaveDraft 新建或更新草稿，草稿不校验必填字段和重复标题，也不写入es
+func (s *articleService) SaveDraft(ctx context.Context, userId string, req *v1.SaveDraftRequest) (uint, error) {
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return 0, err
+	}
+	visibility, err := normalizeVisibility(req.VisibleRange, false)
+	if err != nil {
+		return 0, err
+	}
+	publishAt, err := parsePublishAt(req.PublishAt)
+	if err != nil {
+		return 0, err
+	}
+
+	article := &model.Article{UserID: userId}
+	var previous model.Article
+	if req.ArticleID != 0 {
+		article, err = s.articleRepository.GetArticleFromDB(ctx, req.ArticleID)
+		if err != nil {
+			return 0, v1.ErrArticleNotExist
+		}
+		if article.UserID != userId {
+			return 0, v1.ErrPermissionDenied
+		}
+		// 只有草稿和被驳回的文章可以继续编辑为草稿，已发布的文章通过修改接口更新
+		if article.Status != enums.StatusDraft && article.Status != enums.StatusRejected {
+			return 0, v1.ErrArticleNotDraft
+		}
+		previous = *article
+	}
+	uploadedFilesData, attachmentIds, err := s.resolveUploadedFiles(ctx, req.UploadedFiles, article.ArticleID, userId)
+	if err != nil {
+		return 0, err
+	}
+	article.Title = req.Title
+	article.Content = req.Content
+	article.ContentShort = req.ContentShort
+	article.CategoryID = req.CategoryID
+	article.Importance = req.Importance
+	article.SetVisibility(visibility)
+	article.CommentDisabled = req.CommentDisabled
+	article.SourceURI = req.SourceURI
+	article.UploadedFiles = uploadedFilesData
+	article.Status = enums.StatusDraft
+	article.PublishAt = publishAt
+
+	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		if req.ArticleID == 0 {
+			if _, err := s.articleRepository.CreateArticle(ctx, article); err != nil {
+				return v1.ErrCreateArticleFailed
+			}
+		} else {
+			if err := s.saveRevision(ctx, &previous, article, tags, userId); err != nil {
+				return err
+			}
+			if _, err := s.articleRepository.UpdateArticle(ctx, article); err != nil {
+				return v1.ErrUpdateArticleFailed
+			}
+		}
+		if err := s.setArticleTags(ctx, article.ArticleID, tags); err != nil {
+			return err
+		}
+		if err := s.attachmentRepository.BindArticle(ctx, article.ArticleID, attachmentIds); err != nil {
+			return v1.ErrUpdateFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return article.ArticleID, nil
+}
+
+// PublishArticle 发布草稿，校验必填字段和重复标题后按审核开关和发布时间决定状态
+func (s *articleService) PublishArticle(ctx context.Context, userId string, req *v1.PublishArticleRequest) (*v1.PublishArticleResponseData, error) {
+	article, err := s.articleRepository.GetArticleFromDB(ctx, req.ArticleID)
+	if err != nil {
+		return nil, v1.ErrArticleNotExist
+	}
+	if article.UserID != userId {
+		return nil, v1.ErrPermissionDenied
+	}
+	if article.Status != enums.StatusDraft {
+		return nil, v1.ErrArticleNotDraft
+	}
+	if strings.TrimSpace(article.Title) == "" || strings.TrimSpace(article.Content) == "" ||
+		article.VisibleRange == "" {
+		return nil, v1.ErrArticleFieldEmpty
+	}
+	// 判断是否有重复的文章标题&userId
+	existing, _ := s.articleRepository.GetArticleByTitleAndUserId(ctx, article.Title, article.UserID)
+	if existing != nil && existing.ArticleID != article.ArticleID {
+		return nil, v1.ErrArticleAlreadyExist
+	}
+	status, err := s.getPublishStatus(ctx, article.CategoryID, article.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	article.Status = status
+
+	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.articleRepository.UpdateArticle(ctx, article); err != nil {
+			return v1.ErrUpdateArticleFailed
+		}
+		// 写入es同步任务，与文章修改一同提交
+		if err := s.syncEsArticle(ctx, article); err != nil {
+			return v1.ErrCreateEsArticleFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.PublishArticleResponseData{
+		ArticleID: article.ArticleID,
+		Status:    article.Status,
+	}, nil
+}
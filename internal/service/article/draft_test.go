@@ -0,0 +1,120 @@
+package article
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveDraft(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "other", enums.COMMON_USER, 1)
+	categoryId := e.createCategory(t, "c", 0)
+	published := e.createArticle(t, "author", "published", categoryId)
+	rejected := e.createArticle(t, "author", "rejected", categoryId)
+	require.NoError(t, e.db.Model(&model.Article{}).Where("article_id = ?", rejected).Update("status", enums.StatusRejected).Error)
+	require.NoError(t, e.db.Where("1 = 1").Delete(&model.EsOutbox{}).Error)
+
+	// 草稿不校验必填字段
+	draft, err := e.SaveDraft(ctx, "author", &v1.SaveDraftRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, enums.StatusDraft, e.articleStatus(t, draft))
+
+	tests := []struct {
+		name    string
+		userId  string
+		req     v1.SaveDraftRequest
+		wantErr error
+	}{
+		{"not exist", "author", v1.SaveDraftRequest{ArticleID: 999}, v1.ErrArticleNotExist},
+		{"not author", "other", v1.SaveDraftRequest{ArticleID: draft}, v1.ErrPermissionDenied},
+		{"published", "author", v1.SaveDraftRequest{ArticleID: published}, v1.ErrArticleNotDraft},
+		{"bad publish at", "author", v1.SaveDraftRequest{ArticleID: draft, PublishAt: "tomorrow"}, v1.ErrPublishAtFormat},
+		{"bad visibility", "author", v1.SaveDraftRequest{ArticleID: draft, VisibleRange: v1.Visibility{Scope: "friends"}}, v1.ErrVisibilityInvalid},
+		{"update draft", "author", v1.SaveDraftRequest{ArticleID: draft, Title: "draft"}, nil},
+		{"rejected back to draft", "author", v1.SaveDraftRequest{ArticleID: rejected, Title: "rejected"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := e.SaveDraft(ctx, tt.userId, &tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tt.req.ArticleID, id)
+				assert.Equal(t, enums.StatusDraft, e.articleStatus(t, id))
+			}
+		})
+	}
+
+	// 草稿不写入 es
+	var outbox int64
+	require.NoError(t, e.db.Model(&model.EsOutbox{}).Count(&outbox).Error)
+	assert.Zero(t, outbox)
+}
+
+func TestPublishArticle(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "other", enums.COMMON_USER, 1)
+	categoryId := e.createCategory(t, "c", 0)
+	published := e.createArticle(t, "author", "published", categoryId)
+	saveDraft := func(req v1.SaveDraftRequest) uint {
+		id, err := e.SaveDraft(ctx, "author", &req)
+		require.NoError(t, err)
+		return id
+	}
+	complete := func(title string) v1.SaveDraftRequest {
+		return v1.SaveDraftRequest{
+			Title:        title,
+			Content:      "content",
+			CategoryID:   categoryId,
+			VisibleRange: v1.Visibility{Scope: enums.VisiblePublic},
+		}
+	}
+	noContent := complete("no content")
+	noContent.Content = " "
+	noVisibility := complete("no visibility")
+	noVisibility.VisibleRange = v1.Visibility{}
+	missingCategory := complete("missing category")
+	missingCategory.CategoryID = 999
+	scheduled := complete("scheduled")
+	scheduled.PublishAt = "2999-01-01 00:00:00"
+
+	tests := []struct {
+		name       string
+		userId     string
+		articleId  uint
+		wantErr    error
+		wantStatus int
+	}{
+		{"not exist", "author", 999, v1.ErrArticleNotExist, 0},
+		{"not author", "other", saveDraft(complete("a")), v1.ErrPermissionDenied, enums.StatusDraft},
+		{"not draft", "author", published, v1.ErrArticleNotDraft, enums.StatusPublished},
+		{"empty title", "author", saveDraft(complete(" ")), v1.ErrArticleFieldEmpty, enums.StatusDraft},
+		{"empty content", "author", saveDraft(noContent), v1.ErrArticleFieldEmpty, enums.StatusDraft},
+		{"empty visibility", "author", saveDraft(noVisibility), v1.ErrArticleFieldEmpty, enums.StatusDraft},
+		{"duplicate title", "author", saveDraft(complete("published")), v1.ErrArticleAlreadyExist, enums.StatusDraft},
+		{"category not exist", "author", saveDraft(missingCategory), v1.ErrCategoryNotExist, enums.StatusDraft},
+		{"publish", "author", saveDraft(complete("b")), nil, enums.StatusPublished},
+		{"scheduled", "author", saveDraft(scheduled), nil, enums.StatusScheduled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := e.PublishArticle(ctx, tt.userId, &v1.PublishArticleRequest{ArticleID: tt.articleId})
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tt.wantStatus, data.Status)
+			}
+			if tt.wantStatus != 0 {
+				assert.Equal(t, tt.wantStatus, e.articleStatus(t, tt.articleId))
+			}
+		})
+	}
+}
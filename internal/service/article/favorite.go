@@ -0,0 +1,238 @@
+package article
+
+import (
+	"context"
+	"errors"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/service"
+	"projectName/pkg/utils"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	favoriteFolderMaxCount   = 50      // 每个用户最多创建的收藏夹数量
+	favoriteFolderNameMaxLen = 50      // 收藏夹名称最大字符数
+	defaultFavoriteFolder    = "默认收藏夹" // 默认收藏夹的名称，ID 为 0
+)
+
+// LikeArticle 点赞文章，重复点赞不会重复计数
+func (s *articleService) LikeArticle(ctx context.Context, userId string, articleId uint) (*v1.LikeArticleResponseData, error) {
+	if _, err := s.getVisibleArticle(ctx, userId, articleId); err != nil {
+		return nil, err
+	}
+	if _, err := s.articleLikeRepository.Like(ctx, articleId, userId); err != nil {
+		return nil, v1.ErrInsertFailed
+	}
+	return s.getLikeData(ctx, articleId, true)
+}
+
+// UnlikeArticle 取消点赞，文章已删除或不可见时也可以取消
+func (s *articleService) UnlikeArticle(ctx context.Context, userId string, articleId uint) (*v1.LikeArticleResponseData, error) {
+	if _, err := s.articleLikeRepository.Unlike(ctx, articleId, userId); err != nil {
+		return nil, v1.ErrDeleteFailed
+	}
+	return s.getLikeData(ctx, articleId, false)
+}
+
+func (s *articleService) getLikeData(ctx context.Context, articleId uint, liked bool) (*v1.LikeArticleResponseData, error) {
+	counts, err := s.articleLikeRepository.CountByArticleIds(ctx, []uint{articleId})
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	return &v1.LikeArticleResponseData{Liked: liked, Likes: counts[articleId]}, nil
+}
+
+// FavoriteArticle 收藏文章到指定的收藏夹，已收藏时移动到该收藏夹，不会重复计数
+func (s *articleService) FavoriteArticle(ctx context.Context, userId string, req *v1.FavoriteArticleReq) (*v1.FavoriteArticleResponseData, error) {
+	if _, err := s.getVisibleArticle(ctx, userId, req.ArticleID); err != nil {
+		return nil, err
+	}
+	if req.FolderId != 0 {
+		if _, err := s.getFolder(ctx, userId, req.FolderId); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.favoriteRepository.AddFavorite(ctx, req.ArticleID, userId, req.FolderId); err != nil {
+		return nil, v1.ErrInsertFailed
+	}
+	return s.getFavoriteData(ctx, req.ArticleID, true)
+}
+
+// UnfavoriteArticle 取消收藏，文章已删除或不可见时也可以取消
+func (s *articleService) UnfavoriteArticle(ctx context.Context, userId string, req *v1.UnfavoriteArticleReq) (*v1.FavoriteArticleResponseData, error) {
+	if _, err := s.favoriteRepository.RemoveFavorite(ctx, req.ArticleID, userId); err != nil {
+		return nil, v1.ErrDeleteFailed
+	}
+	return s.getFavoriteData(ctx, req.ArticleID, false)
+}
+
+func (s *articleService) getFavoriteData(ctx context.Context, articleId uint, favorited bool) (*v1.FavoriteArticleResponseData, error) {
+	counts, err := s.favoriteRepository.CountByArticleIds(ctx, []uint{articleId})
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	return &v1.FavoriteArticleResponseData{Favorited: favorited, Favorites: counts[articleId]}, nil
+}
+
+// GetFavoriteList 分页获取我的收藏，按收藏时间倒序，已删除或不再可见的文章不返回
+func (s *articleService) GetFavoriteList(ctx context.Context, userId string, req *v1.GetFavoriteListReq) (*v1.ArticleList, error) {
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	response := &v1.ArticleList{
+		ArticleDataList: []*v1.ArticleData{},
+		PageResponse: v1.PageResponse{
+			PageIndex: pageIndex,
+			PageSize:  pageSize,
+		},
+	}
+	if req.FolderId != nil && *req.FolderId != 0 {
+		if _, err := s.getFolder(ctx, userId, *req.FolderId); err != nil {
+			return nil, err
+		}
+	}
+	viewer, err := getViewer(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	articles, total, err := s.favoriteRepository.GetFavoriteArticleList(ctx, viewer, req.FolderId, pageIndex, pageSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	articleList, err := s.buildArticleDataList(ctx, viewer, articles)
+	if err != nil {
+		return nil, err
+	}
+	response.ArticleDataList = append(response.ArticleDataList, articleList...)
+	response.TotalCount = total
+	return response, nil
+}
+
+// CreateFavoriteFolder 新建收藏夹，同一用户下名称不能重复
+func (s *articleService) CreateFavoriteFolder(ctx context.Context, userId string, req *v1.CreateFavoriteFolderReq) (uint, error) {
+	name, err := s.checkFolderName(ctx, userId, req.Name, 0)
+	if err != nil {
+		return 0, err
+	}
+	total, err := s.favoriteRepository.CountFolders(ctx, userId)
+	if err != nil {
+		return 0, v1.ErrQueryFailed
+	}
+	if total >= favoriteFolderMaxCount {
+		return 0, v1.ErrFolderLimit
+	}
+	folder := &model.FavoriteFolder{UserId: userId, Name: name}
+	if err = s.favoriteRepository.CreateFolder(ctx, folder); err != nil {
+		return 0, v1.ErrInsertFailed
+	}
+	return folder.Id, nil
+}
+
+// UpdateFavoriteFolder 重命名收藏夹
+func (s *articleService) UpdateFavoriteFolder(ctx context.Context, userId string, req *v1.UpdateFavoriteFolderReq) error {
+	folder, err := s.getFolder(ctx, userId, req.FolderId)
+	if err != nil {
+		return err
+	}
+	name, err := s.checkFolderName(ctx, userId, req.Name, folder.Id)
+	if err != nil {
+		return err
+	}
+	folder.Name = name
+	if err = s.favoriteRepository.UpdateFolder(ctx, folder); err != nil {
+		return v1.ErrUpdateFailed
+	}
+	return nil
+}
+
+// DeleteFavoriteFolder 删除收藏夹，其中的收藏移到默认收藏夹
+func (s *articleService) DeleteFavoriteFolder(ctx context.Context, userId string, req *v1.DeleteFavoriteFolderReq) error {
+	folder, err := s.getFolder(ctx, userId, req.FolderId)
+	if err != nil {
+		return err
+	}
+	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.favoriteRepository.MoveFolderFavorites(ctx, userId, folder.Id, 0); err != nil {
+			return err
+		}
+		return s.favoriteRepository.DeleteFolder(ctx, folder.Id)
+	})
+	if err != nil {
+		return v1.ErrDeleteFailed
+	}
+	return nil
+}
+
+// GetFavoriteFolderList 获取收藏夹列表，默认收藏夹排在最前
+func (s *articleService) GetFavoriteFolderList(ctx context.Context, userId string) ([]*v1.FavoriteFolderData, error) {
+	folders, err := s.favoriteRepository.GetFolderList(ctx, userId)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	counts, err := s.favoriteRepository.CountByFolders(ctx, userId)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	folderList := make([]*v1.FavoriteFolderData, 0, len(folders)+1)
+	folderList = append(folderList, &v1.FavoriteFolderData{
+		FolderId: 0,
+		Name:     defaultFavoriteFolder,
+		Count:    counts[0],
+	})
+	for _, folder := range folders {
+		folderList = append(folderList, &v1.FavoriteFolderData{
+			FolderId:  folder.Id,
+			Name:      folder.Name,
+			Count:     counts[folder.Id],
+			CreatedAt: utils.TimeFormat(folder.CreatedAt, utils.FormatDateTime),
+		})
+	}
+	return folderList, nil
+}
+
+// getVisibleArticle 获取当前用户可见的已发布文章
+func (s *articleService) getVisibleArticle(ctx context.Context, userId string, articleId uint) (*model.Article, error) {
+	article, err := s.articleRepository.GetArticle(ctx, articleId)
+	if err != nil {
+		return nil, v1.ErrArticleNotExist
+	}
+	if article.Status != enums.StatusPublished {
+		return nil, v1.ErrArticleStatusError
+	}
+	if err = checkArticleVisible(ctx, s.userRepo, userId, article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// getFolder 获取当前用户的收藏夹
+func (s *articleService) getFolder(ctx context.Context, userId string, folderId uint) (*model.FavoriteFolder, error) {
+	folder, err := s.favoriteRepository.GetFolder(ctx, userId, folderId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrFolderNotExist
+		}
+		return nil, v1.ErrQueryFailed
+	}
+	return folder, nil
+}
+
+// checkFolderName 校验收藏夹名称，返回去除首尾空白后的名称，excludeId 为正在修改的收藏夹
+func (s *articleService) checkFolderName(ctx context.Context, userId string, name string, excludeId uint) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == defaultFavoriteFolder || utf8.RuneCountInString(name) > favoriteFolderNameMaxLen {
+		return "", v1.ErrBadRequest
+	}
+	existing, err := s.favoriteRepository.GetFolderByName(ctx, userId, name)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return name, nil
+		}
+		return "", v1.ErrQueryFailed
+	}
+	if existing.Id != excludeId {
+		return "", v1.ErrFolderNameExists
+	}
+	return name, nil
+}
@@ -0,0 +1,178 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLikeArticle(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "u1", enums.COMMON_USER, 2)
+	e.createUser(t, "u2", enums.COMMON_USER, 2)
+	categoryId := e.createCategory(t, "c", 0)
+	articleId := e.createArticle(t, "author", "public", categoryId)
+	privateId, err := e.CreateArticle(ctx, &v1.CreateArticleRequest{
+		Title:        "private",
+		Content:      "content",
+		AuthorID:     "author",
+		CategoryID:   categoryId,
+		VisibleRange: v1.Visibility{Scope: enums.VisiblePrivate},
+	})
+	require.NoError(t, err)
+
+	data, err := e.LikeArticle(ctx, "u1", articleId)
+	require.NoError(t, err)
+	assert.Equal(t, &v1.LikeArticleResponseData{Liked: true, Likes: 1}, data)
+	// 重复点赞不重复计数
+	data, err = e.LikeArticle(ctx, "u1", articleId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), data.Likes)
+	data, err = e.LikeArticle(ctx, "u2", articleId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), data.Likes)
+
+	data, err = e.UnlikeArticle(ctx, "u1", articleId)
+	require.NoError(t, err)
+	assert.Equal(t, &v1.LikeArticleResponseData{Liked: false, Likes: 1}, data)
+	data, err = e.UnlikeArticle(ctx, "u1", articleId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), data.Likes)
+
+	// 不可见和不存在的文章不能点赞
+	_, err = e.LikeArticle(ctx, "u1", uint(privateId))
+	assert.ErrorIs(t, err, v1.ErrPermissionDenied)
+	_, err = e.LikeArticle(ctx, "u1", 999)
+	assert.ErrorIs(t, err, v1.ErrArticleNotExist)
+}
+
+func TestFavoriteArticle(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "u1", enums.COMMON_USER, 2)
+	e.createUser(t, "u2", enums.COMMON_USER, 2)
+	categoryId := e.createCategory(t, "c", 0)
+	a1 := e.createArticle(t, "author", "a1", categoryId)
+	a2 := e.createArticle(t, "author", "a2", categoryId)
+	a3 := e.createArticle(t, "author", "a3", categoryId)
+	folderId, err := e.CreateFavoriteFolder(ctx, "u1", &v1.CreateFavoriteFolderReq{Name: "f1"})
+	require.NoError(t, err)
+	otherFolderId, err := e.CreateFavoriteFolder(ctx, "u2", &v1.CreateFavoriteFolderReq{Name: "f1"})
+	require.NoError(t, err)
+
+	data, err := e.FavoriteArticle(ctx, "u1", &v1.FavoriteArticleReq{ArticleID: a1})
+	require.NoError(t, err)
+	assert.Equal(t, &v1.FavoriteArticleResponseData{Favorited: true, Favorites: 1}, data)
+	// 已收藏时移动到新的收藏夹，不重复计数
+	data, err = e.FavoriteArticle(ctx, "u1", &v1.FavoriteArticleReq{ArticleID: a1, FolderId: folderId})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), data.Favorites)
+	_, err = e.FavoriteArticle(ctx, "u1", &v1.FavoriteArticleReq{ArticleID: a2})
+	require.NoError(t, err)
+	_, err = e.FavoriteArticle(ctx, "u1", &v1.FavoriteArticleReq{ArticleID: a3})
+	require.NoError(t, err)
+	// 不能收藏到其他用户的收藏夹
+	_, err = e.FavoriteArticle(ctx, "u1", &v1.FavoriteArticleReq{ArticleID: a2, FolderId: otherFolderId})
+	assert.ErrorIs(t, err, v1.ErrFolderNotExist)
+
+	listIds := func(folderId *uint) []uint {
+		list, err := e.GetFavoriteList(ctx, "u1", &v1.GetFavoriteListReq{FolderId: folderId})
+		require.NoError(t, err)
+		ids := make([]uint, 0, len(list.ArticleDataList))
+		for _, article := range list.ArticleDataList {
+			ids = append(ids, article.ArticleID)
+		}
+		assert.Equal(t, int64(len(ids)), list.TotalCount)
+		return ids
+	}
+	defaultFolder := uint(0)
+	assert.ElementsMatch(t, []uint{a1, a2, a3}, listIds(nil))
+	assert.Equal(t, []uint{a1}, listIds(&folderId))
+	assert.ElementsMatch(t, []uint{a2, a3}, listIds(&defaultFolder))
+	_, err = e.GetFavoriteList(ctx, "u1", &v1.GetFavoriteListReq{FolderId: &otherFolderId})
+	assert.ErrorIs(t, err, v1.ErrFolderNotExist)
+
+	// 已删除和不再可见的文章不返回，但仍可以取消收藏
+	require.NoError(t, e.db.Table("kb_article").Where("article_id = ?", a2).Update("deleted_at", time.Now()).Error)
+	require.NoError(t, e.db.Table("kb_article").Where("article_id = ?", a3).Update("visible_range", enums.VisiblePrivate).Error)
+	assert.Equal(t, []uint{a1}, listIds(nil))
+	data, err = e.UnfavoriteArticle(ctx, "u1", &v1.UnfavoriteArticleReq{ArticleID: a2})
+	require.NoError(t, err)
+	assert.Equal(t, &v1.FavoriteArticleResponseData{Favorited: false, Favorites: 0}, data)
+}
+
+func TestFavoriteFolders(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "u1", enums.COMMON_USER, 1)
+	categoryId := e.createCategory(t, "c", 0)
+	a1 := e.createArticle(t, "author", "a1", categoryId)
+	a2 := e.createArticle(t, "author", "a2", categoryId)
+
+	tests := []struct {
+		name    string
+		folder  string
+		wantErr error
+	}{
+		{"trim name", "  f1  ", nil},
+		{"duplicate name", "f1", v1.ErrFolderNameExists},
+		{"empty name", "  ", v1.ErrBadRequest},
+		{"default folder name", defaultFavoriteFolder, v1.ErrBadRequest},
+		{"name too long", strings.Repeat("名", favoriteFolderNameMaxLen+1), v1.ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := e.CreateFavoriteFolder(ctx, "u1", &v1.CreateFavoriteFolderReq{Name: tt.folder})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	f2, err := e.CreateFavoriteFolder(ctx, "u1", &v1.CreateFavoriteFolderReq{Name: "f2"})
+	require.NoError(t, err)
+	// 重命名为自己的名称不算重复，与其他收藏夹重名时拒绝
+	assert.NoError(t, e.UpdateFavoriteFolder(ctx, "u1", &v1.UpdateFavoriteFolderReq{FolderId: f2, Name: "f2"}))
+	assert.ErrorIs(t, e.UpdateFavoriteFolder(ctx, "u1", &v1.UpdateFavoriteFolderReq{FolderId: f2, Name: "f1"}), v1.ErrFolderNameExists)
+	assert.NoError(t, e.UpdateFavoriteFolder(ctx, "u1", &v1.UpdateFavoriteFolderReq{FolderId: f2, Name: "f2-new"}))
+	assert.ErrorIs(t, e.UpdateFavoriteFolder(ctx, "author", &v1.UpdateFavoriteFolderReq{FolderId: f2, Name: "x"}), v1.ErrFolderNotExist)
+
+	_, err = e.FavoriteArticle(ctx, "u1", &v1.FavoriteArticleReq{ArticleID: a1, FolderId: f2})
+	require.NoError(t, err)
+	_, err = e.FavoriteArticle(ctx, "u1", &v1.FavoriteArticleReq{ArticleID: a2})
+	require.NoError(t, err)
+	folders, err := e.GetFavoriteFolderList(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, folders, 3)
+	assert.Equal(t, defaultFavoriteFolder, folders[0].Name)
+	assert.Equal(t, int64(1), folders[0].Count)
+
+	// 删除收藏夹后其中的收藏移到默认收藏夹
+	assert.ErrorIs(t, e.DeleteFavoriteFolder(ctx, "author", &v1.DeleteFavoriteFolderReq{FolderId: f2}), v1.ErrFolderNotExist)
+	require.NoError(t, e.DeleteFavoriteFolder(ctx, "u1", &v1.DeleteFavoriteFolderReq{FolderId: f2}))
+	folders, err = e.GetFavoriteFolderList(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, folders, 2)
+	assert.Equal(t, int64(2), folders[0].Count)
+	assert.Equal(t, "f1", folders[1].Name)
+}
+
+func TestCreateFavoriteFolder_Limit(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	for i := 0; i < favoriteFolderMaxCount; i++ {
+		_, err := e.CreateFavoriteFolder(ctx, "u1", &v1.CreateFavoriteFolderReq{Name: fmt.Sprintf("f%d", i)})
+		require.NoError(t, err)
+	}
+	_, err := e.CreateFavoriteFolder(ctx, "u1", &v1.CreateFavoriteFolderReq{Name: "more"})
+	assert.ErrorIs(t, err, v1.ErrFolderLimit)
+}
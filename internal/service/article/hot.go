@@ -0,0 +1,76 @@
+package article
+
+import (
+	"context"
+	"go.uber.org/zap"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"time"
+)
+
+const (
+	defaultViewWindow     = 30 * time.Minute // 同一用户重复浏览只计一次的默认时间窗口
+	hotArticleDefaultSize = 10               // 热门文章默认返回的数量
+	hotArticleMaxSize     = 50               // 热门文章最多返回的数量
+	hotArticleCandidates  = 3                // 排行中可能有已下线或当前用户不可见的文章，按返回数量的倍数获取候选
+)
+
+// recordView 记录已发布文章的浏览，作者本人的浏览不计入，记录失败不影响查看文章
+func (s *articleService) recordView(ctx context.Context, userId string, article *model.Article) {
+	if article.Status != enums.StatusPublished || userId == article.UserID {
+		return
+	}
+	if _, err := s.articleStatRepository.RecordView(ctx, article.ArticleID, userId, s.viewWindow); err != nil {
+		s.Logger.Error("articleService.recordView error", zap.Uint("articleId", article.ArticleID), zap.Error(err))
+	}
+}
+
+// GetHotArticleList 按浏览量获取当天或最近 7 天的热门文章，只返回当前用户可见的已发布文章
+func (s *articleService) GetHotArticleList(ctx context.Context, userId string, period string, size int) ([]*v1.ArticleData, error) {
+	var days int
+	switch period {
+	case "", enums.HotPeriodDaily:
+		days = 1
+	case enums.HotPeriodWeekly:
+		days = 7
+	default:
+		return nil, v1.ErrBadRequest
+	}
+	if size <= 0 {
+		size = hotArticleDefaultSize
+	} else if size > hotArticleMaxSize {
+		size = hotArticleMaxSize
+	}
+
+	viewer, err := getViewer(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.articleStatRepository.GetHotArticleIds(ctx, days, size*hotArticleCandidates)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	candidates, err := s.articleRepository.GetVisibleArticlesByIds(ctx, viewer, ids)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	// 按排行顺序返回
+	articleMap := make(map[uint]model.Article, len(candidates))
+	for _, article := range candidates {
+		articleMap[article.ArticleID] = article
+	}
+	articles := make([]model.Article, 0, size)
+	for _, id := range ids {
+		if len(articles) == size {
+			break
+		}
+		if article, ok := articleMap[id]; ok {
+			articles = append(articles, article)
+		}
+	}
+	if len(articles) == 0 {
+		return []*v1.ArticleData{}, nil
+	}
+	return s.buildArticleDataList(ctx, viewer, articles)
+}
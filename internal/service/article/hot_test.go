@@ -0,0 +1,69 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHotArticleList(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "viewer", enums.SUTDENT_USER, 2)
+	categoryId := e.createCategory(t, "c", 0)
+
+	public := e.createArticle(t, "author", "public", categoryId)
+	popular := e.createArticle(t, "author", "popular", categoryId)
+	private := e.createArticle(t, "author", "private", categoryId)
+	_, err := e.UpdateArticle(ctx, "author", enums.COMMON_USER, &v1.UpdateArticleRequest{
+		ArticleID: private,
+		CreateArticleRequest: v1.CreateArticleRequest{
+			Title: "private", Content: "c", CategoryID: categoryId,
+			VisibleRange: v1.Visibility{Scope: enums.VisiblePrivate},
+		},
+	})
+	require.NoError(t, err)
+	deleted := e.createArticle(t, "author", "deleted", categoryId)
+	_, err = e.DeleteArticle(ctx, deleted)
+	require.NoError(t, err)
+
+	views := map[uint]int{public: 1, popular: 3, private: 4, deleted: 5}
+	for articleId, n := range views {
+		for i := 0; i < n; i++ {
+			_, err := e.articleStatRepository.RecordView(ctx, articleId, fmt.Sprintf("u%d", i), time.Hour)
+			require.NoError(t, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		userId string
+		size   int
+		want   []string
+	}{
+		{"viewer", "viewer", 10, []string{"popular", "public"}},
+		{"size", "viewer", 1, []string{"popular"}},
+		{"author sees private", "author", 10, []string{"private", "popular", "public"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := e.GetHotArticleList(ctx, tt.userId, enums.HotPeriodWeekly, tt.size)
+			require.NoError(t, err)
+			titles := make([]string, 0, len(list))
+			for _, article := range list {
+				titles = append(titles, article.Title)
+			}
+			assert.Equal(t, tt.want, titles)
+		})
+	}
+
+	_, err = e.GetHotArticleList(ctx, "viewer", "monthly", 10)
+	assert.Equal(t, v1.ErrBadRequest, err)
+}
@@ -0,0 +1,201 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/pkg/utils"
+)
+
+// articleLoader 批量加载文章列表所需的作者、分类、标签和各项统计数据，每类数据一次查询
+// 只在单个请求内使用，已加载的数据不会重复查询
+type articleLoader struct {
+	s          *articleService
+	authors    map[string]string // 用户ID -> 昵称
+	categories map[uint]string   // 分类ID -> 分类名称
+	comments   map[uint]int64    // 文章ID -> 评论数
+	tags       map[uint][]string // 文章ID -> 标签
+	views      map[uint]int64    // 文章ID -> 浏览量
+	likes      map[uint]int64    // 文章ID -> 点赞数
+	favorites  map[uint]int64    // 文章ID -> 收藏数
+	loaded     map[uint]bool     // 已加载统计数据的文章
+	viewer     *model.User       // 当前用户，决定是否返回可见范围中的共享列表
+}
+
+func (s *articleService) newArticleLoader() *articleLoader {
+	return &articleLoader{
+		s:          s,
+		authors:    make(map[string]string),
+		categories: make(map[uint]string),
+		comments:   make(map[uint]int64),
+		tags:       make(map[uint][]string),
+		views:      make(map[uint]int64),
+		likes:      make(map[uint]int64),
+		favorites:  make(map[uint]int64),
+		loaded:     make(map[uint]bool),
+	}
+}
+
+// loadAuthors 加载尚未加载的作者昵称，不存在的用户昵称为空
+func (l *articleLoader) loadAuthors(ctx context.Context, userIds []string) error {
+	var missing []string
+	for _, userId := range userIds {
+		if _, ok := l.authors[userId]; !ok {
+			l.authors[userId] = ""
+			missing = append(missing, userId)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	users, err := l.s.userRepo.GetByUserIds(ctx, missing)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	for _, user := range users {
+		l.authors[user.UserId] = user.Nickname
+	}
+	return nil
+}
+
+// loadCategories 加载尚未加载的分类名称，不存在的分类名称为空
+func (l *articleLoader) loadCategories(ctx context.Context, categoryIds []uint) error {
+	var missing []uint
+	for _, categoryId := range categoryIds {
+		if _, ok := l.categories[categoryId]; !ok {
+			l.categories[categoryId] = ""
+			missing = append(missing, categoryId)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	categories, err := l.s.articleRepository.GetCategoriesByIds(ctx, missing)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	for _, category := range categories {
+		l.categories[category.CId] = category.CategoryName
+	}
+	return nil
+}
+
+// loadStats 加载尚未加载的文章标签以及评论数、浏览量、点赞数和收藏数
+func (l *articleLoader) loadStats(ctx context.Context, articleIds []uint) error {
+	var missing []uint
+	for _, articleId := range articleIds {
+		if !l.loaded[articleId] {
+			l.loaded[articleId] = true
+			missing = append(missing, articleId)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	commentCounts, err := l.s.commentRepository.CountByArticleIds(ctx, missing)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	tagNames, err := l.s.tagRepository.GetTagNamesByArticleIds(ctx, missing)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	views, err := l.s.articleStatRepository.GetViews(ctx, missing)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	likes, err := l.s.articleLikeRepository.CountByArticleIds(ctx, missing)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	favorites, err := l.s.favoriteRepository.CountByArticleIds(ctx, missing)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	for articleId, count := range views {
+		l.views[articleId] = count
+	}
+	for articleId, count := range likes {
+		l.likes[articleId] = count
+	}
+	for articleId, count := range favorites {
+		l.favorites[articleId] = count
+	}
+	for articleId, count := range commentCounts {
+		l.comments[articleId] = count
+	}
+	for articleId, names := range tagNames {
+		l.tags[articleId] = names
+	}
+	return nil
+}
+
+// loadArticles 加载一组文章的作者、分类、评论数和标签
+func (l *articleLoader) loadArticles(ctx context.Context, articles []model.Article) error {
+	userIds := make([]string, 0, len(articles))
+	categoryIds := make([]uint, 0, len(articles))
+	articleIds := make([]uint, 0, len(articles))
+	for i := range articles {
+		userIds = append(userIds, articles[i].UserID)
+		categoryIds = append(categoryIds, articles[i].CategoryID)
+		articleIds = append(articleIds, articles[i].ArticleID)
+	}
+	if err := l.loadAuthors(ctx, userIds); err != nil {
+		return err
+	}
+	if err := l.loadCategories(ctx, categoryIds); err != nil {
+		return err
+	}
+	return l.loadStats(ctx, articleIds)
+}
+
+// buildArticleData 使用已加载的数据将文章映射为返回数据
+func (l *articleLoader) buildArticleData(article *model.Article) (*v1.ArticleData, error) {
+	// 反序列化上传的文件列表
+	var uploadedFiles []v1.FileUpload
+	if len(article.UploadedFiles) > 0 {
+		if err := json.Unmarshal(article.UploadedFiles, &uploadedFiles); err != nil {
+			return nil, v1.ErrDeserializeFileFailed
+		}
+	}
+	var publishAt string
+	if article.PublishAt != nil {
+		publishAt = utils.TimeFormat(*article.PublishAt, utils.FormatDateTime)
+	}
+	return &v1.ArticleData{
+		ArticleID:       article.ArticleID,
+		Title:           article.Title,
+		Content:         article.Content,
+		ContentShort:    article.ContentShort,
+		Author:          l.authors[article.UserID],
+		Category:        l.categories[article.CategoryID],
+		CategoryID:      article.CategoryID,
+		Importance:      article.Importance,
+		VisibleRange:    l.buildVisibilityData(article),
+		CommentDisabled: article.CommentDisabled,
+		SourceURI:       article.SourceURI,
+		UploadedFiles:   uploadedFiles,
+		Status:          article.Status,
+		ReviewRemark:    article.ReviewRemark,
+		PublishAt:       publishAt,
+		CreatedAt:       utils.TimeFormat(article.CreatedAt, utils.FormatDateTime),
+		UpdatedAt:       utils.TimeFormat(article.UpdatedAt, utils.FormatDateTime),
+		Comments:        l.comments[article.ArticleID],
+		Tags:            l.tags[article.ArticleID],
+		Views:           l.views[article.ArticleID],
+		Likes:           l.likes[article.ArticleID],
+		Favorites:       l.favorites[article.ArticleID],
+	}, nil
+}
+
+// buildVisibilityData 映射文章的可见范围，共享的用户和学院列表只返回给作者本人和管理员
+func (l *articleLoader) buildVisibilityData(article *model.Article) v1.Visibility {
+	visibility := buildVisibilityData(article.Visibility())
+	if l.viewer == nil || (l.viewer.UserId != article.UserID && l.viewer.RoleType < enums.SCHOOL_ADMIN) {
+		visibility.SharedUserIds = nil
+		visibility.SharedCollegeIds = nil
+	}
+	return visibility
+}
@@ -0,0 +1,82 @@
+package article
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// countQueries 统计数据库查询次数
+func countQueries(t *testing.T, db *gorm.DB) *int {
+	var count int
+	fn := func(*gorm.DB) { count++ }
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:count_query", fn))
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:count_row", fn))
+	return &count
+}
+
+func TestArticleLoader(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "a1", enums.COMMON_USER, 1)
+	e.createUser(t, "a2", enums.COMMON_USER, 1)
+	c1 := e.createCategory(t, "c1", 0)
+	c2 := e.createCategory(t, "c2", 0)
+	ids := []uint{
+		e.createArticle(t, "a1", "t1", c1),
+		e.createArticle(t, "a2", "t2", c2),
+		e.createArticle(t, "a1", "t3", c2),
+	}
+	tag := &model.Tag{TagName: "go"}
+	require.NoError(t, e.db.Create(tag).Error)
+	require.NoError(t, e.db.Create(&model.ArticleTag{ArticleID: ids[0], TagId: tag.Id}).Error)
+	require.NoError(t, e.db.Create([]model.Comment{
+		{ArticleID: ids[0], UserId: "a2", Content: "x"},
+		{ArticleID: ids[0], UserId: "a2", Content: "y"},
+		{ArticleID: ids[1], UserId: "a1", Content: "z"},
+	}).Error)
+	require.NoError(t, e.db.Create(&model.ArticleStat{ArticleID: ids[1], Views: 7}).Error)
+	require.NoError(t, e.db.Create(&model.ArticleLike{ArticleID: ids[2], UserId: "a2"}).Error)
+	require.NoError(t, e.db.Create(&model.ArticleFavorite{ArticleID: ids[2], UserId: "a2"}).Error)
+
+	var articles []model.Article
+	require.NoError(t, e.db.Order("article_id").Find(&articles, ids).Error)
+	// 作者不存在的文章昵称为空
+	articles = append(articles, model.Article{ArticleID: 999, UserID: "missing", CategoryID: 999})
+
+	queries := countQueries(t, e.db)
+	loader := e.newArticleLoader()
+	require.NoError(t, loader.loadArticles(ctx, articles))
+	// 作者、分类、评论数、标签、浏览量、点赞数、收藏数各一次查询，与文章数量无关
+	assert.Equal(t, 7, *queries)
+
+	data := make([]*v1.ArticleData, 0, len(articles))
+	for i := range articles {
+		d, err := loader.buildArticleData(&articles[i])
+		require.NoError(t, err)
+		data = append(data, d)
+	}
+	assert.Equal(t, "a1", data[0].Author)
+	assert.Equal(t, "c1", data[0].Category)
+	assert.Equal(t, int64(2), data[0].Comments)
+	assert.Equal(t, []string{"go"}, data[0].Tags)
+	assert.Equal(t, "a2", data[1].Author)
+	assert.Equal(t, int64(1), data[1].Comments)
+	assert.Equal(t, int64(7), data[1].Views)
+	assert.Equal(t, int64(1), data[2].Likes)
+	assert.Equal(t, int64(1), data[2].Favorites)
+	assert.Empty(t, data[2].Tags)
+	assert.Empty(t, data[3].Author)
+	assert.Empty(t, data[3].Category)
+
+	// 已加载的数据不再查询，包括不存在的作者和分类
+	*queries = 0
+	require.NoError(t, loader.loadArticles(ctx, articles))
+	assert.Zero(t, *queries)
+}
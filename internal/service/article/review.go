@@ -0,0 +1,155 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/service"
+	"projectName/pkg/utils"
+	"time"
+)
+
+// getPublishStatus 根据全局审核开关和分类审核开关决定文章提交后的状态，无需审核时按发布时间决定是否定时发布
+// 未设置分类（categoryId 为 0）时只按全局审核开关判断，分类不存在时返回错误
+func (s *articleService) getPublishStatus(ctx context.Context, categoryId uint, publishAt *time.Time) (int, error) {
+	var category *model.Category
+	if categoryId != 0 {
+		var err error
+		if category, err = s.articleRepository.GetCategoryById(ctx, categoryId); err != nil {
+			if errors.Is(err, v1.ErrNotFound) {
+				return -1, v1.ErrCategoryNotExist
+			}
+			return -1, v1.ErrQueryFailed
+		}
+	}
+	if s.reviewEnabled || (category != nil && category.NeedReview == 1) {
+		return enums.StatusPendingReview, nil
+	}
+	return releaseStatus(publishAt), nil
+}
+
+// releaseStatus 发布时间未到时为定时发布，否则立即发布
+func releaseStatus(publishAt *time.Time) int {
+	if publishAt != nil && publishAt.After(time.Now()) {
+		return enums.StatusScheduled
+	}
+	return enums.StatusPublished
+}
+
+// parsePublishAt 解析定时发布时间，为空时返回 nil
+func parsePublishAt(publishAt string) (*time.Time, error) {
+	if publishAt == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(utils.FormatDateTime, publishAt, time.Local)
+	if err != nil {
+		return nil, v1.ErrPublishAtFormat
+	}
+	return &t, nil
+}
+
+// GetReviewArticleList 学校管理员查看本学院作者的待审核文章，超级管理员查看全部
+func (s *articleService) GetReviewArticleList(ctx context.Context, reviewerId string, reviewerRole int, req *v1.PageRequest) (*v1.ArticleList, error) {
+	collegeId, err := s.getReviewerCollegeId(ctx, reviewerId, reviewerRole)
+	if err != nil {
+		return nil, err
+	}
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	articles, total, err := s.articleRepository.GetReviewArticleList(ctx, collegeId, pageIndex, pageSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	articleList, err := s.buildArticleDataList(ctx, &model.User{UserId: reviewerId, RoleType: reviewerRole}, articles)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ArticleList{
+		ArticleDataList: articleList,
+		PageResponse: v1.PageResponse{
+			TotalCount: total,
+			PageIndex:  pageIndex,
+			PageSize:   pageSize,
+		},
+	}, nil
+}
+
+// ReviewArticle 审核文章，通过后发布并写入es，驳回时记录原因，并通知作者
+func (s *articleService) ReviewArticle(ctx context.Context, reviewerId string, reviewerRole int, req *v1.ReviewArticleReq) error {
+	collegeId, err := s.getReviewerCollegeId(ctx, reviewerId, reviewerRole)
+	if err != nil {
+		return err
+	}
+	article, err := s.articleRepository.GetArticleFromDB(ctx, req.ArticleID)
+	if err != nil {
+		return v1.ErrArticleNotExist
+	}
+	if article.Status != enums.StatusPendingReview {
+		return v1.ErrArticleStatusError
+	}
+	// 学校管理员只能审核本学院作者的文章
+	if collegeId != 0 {
+		author, err := s.userRepo.GetByUserId(ctx, article.UserID)
+		if err != nil || author.CollegeId != collegeId {
+			return v1.ErrPermissionDenied
+		}
+	}
+	if !req.Approved && req.Reason == "" {
+		return v1.ErrRejectReasonEmpty
+	}
+
+	notification := &model.Notification{
+		UserId:    article.UserID,
+		RelatedId: article.ArticleID,
+	}
+	if req.Approved {
+		article.Status = releaseStatus(article.PublishAt)
+		article.ReviewRemark = ""
+		notification.Type = enums.NotifyArticleApproved
+		notification.Title = "文章审核通过"
+		notification.Content = fmt.Sprintf("您的文章《%s》已审核通过并发布", article.Title)
+		if article.Status == enums.StatusScheduled {
+			notification.Content = fmt.Sprintf("您的文章《%s》已审核通过，将于 %s 发布",
+				article.Title, utils.TimeFormat(*article.PublishAt, utils.FormatDateTime))
+		}
+	} else {
+		article.Status = enums.StatusRejected
+		article.ReviewRemark = req.Reason
+		notification.Type = enums.NotifyArticleRejected
+		notification.Title = "文章审核未通过"
+		notification.Content = fmt.Sprintf("您的文章《%s》未通过审核，原因：%s", article.Title, req.Reason)
+	}
+
+	return s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.articleRepository.UpdateArticle(ctx, article); err != nil {
+			return v1.ErrUpdateArticleFailed
+		}
+		if err := s.notificationRepository.CreateNotification(ctx, notification); err != nil {
+			s.Logger.Error("articleService.ReviewArticle notify error", zap.Error(err))
+			return v1.ErrInsertFailed
+		}
+		// 写入es同步任务，与文章修改一同提交
+		if err := s.syncEsArticle(ctx, article); err != nil {
+			return v1.ErrCreateEsArticleFailed
+		}
+		return nil
+	})
+}
+
+// getReviewerCollegeId 获取审核人所属学院，超级管理员返回 0 表示不限学院
+func (s *articleService) getReviewerCollegeId(ctx context.Context, reviewerId string, reviewerRole int) (uint, error) {
+	if reviewerRole == enums.SUPER_ADMIN {
+		return 0, nil
+	}
+	reviewer, err := s.userRepo.GetByUserId(ctx, reviewerId)
+	if err != nil {
+		return 0, v1.ErrUserNotExist
+	}
+	if reviewer.CollegeId == 0 {
+		return 0, v1.ErrPermissionDenied
+	}
+	return reviewer.CollegeId, nil
+}
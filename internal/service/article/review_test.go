@@ -0,0 +1,172 @@
+package article
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPublishStatus(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	normal := e.createCategory(t, "normal", 0)
+	needReview := e.createCategory(t, "review", 1)
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		reviewEnabled bool
+		categoryId    uint
+		publishAt     *time.Time
+		want          int
+		wantErr       error
+	}{
+		{"no category", false, 0, nil, enums.StatusPublished, nil},
+		{"normal category", false, normal, nil, enums.StatusPublished, nil},
+		{"publish at in the past", false, normal, &past, enums.StatusPublished, nil},
+		{"publish at in the future", false, normal, &future, enums.StatusScheduled, nil},
+		{"category needs review", false, needReview, &future, enums.StatusPendingReview, nil},
+		{"global review", true, normal, nil, enums.StatusPendingReview, nil},
+		{"global review without category", true, 0, nil, enums.StatusPendingReview, nil},
+		{"category not exist", false, 999, nil, -1, v1.ErrCategoryNotExist},
+		{"category not exist with global review", true, 999, nil, -1, v1.ErrCategoryNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e.reviewEnabled = tt.reviewEnabled
+			got, err := e.getPublishStatus(ctx, tt.categoryId, tt.publishAt)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreateArticle_Status(t *testing.T) {
+	ctx := context.Background()
+	conf := viper.New()
+	e := newTestEnv(t, conf)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	normal := e.createCategory(t, "normal", 0)
+	needReview := e.createCategory(t, "review", 1)
+
+	tests := []struct {
+		name       string
+		categoryId uint
+		publishAt  string
+		want       int
+		wantErr    error
+	}{
+		{"published", normal, "", enums.StatusPublished, nil},
+		{"scheduled", normal, time.Now().Add(time.Hour).Format("2006-01-02 15:04:05"), enums.StatusScheduled, nil},
+		{"pending review", needReview, "", enums.StatusPendingReview, nil},
+		{"category not exist", 999, "", 0, v1.ErrCategoryNotExist},
+		{"bad publish at", normal, "tomorrow", 0, v1.ErrPublishAtFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := e.CreateArticle(ctx, &v1.CreateArticleRequest{
+				Title:        tt.name,
+				Content:      "content",
+				AuthorID:     "author",
+				CategoryID:   tt.categoryId,
+				PublishAt:    tt.publishAt,
+				VisibleRange: v1.Visibility{Scope: enums.VisiblePublic},
+			})
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, e.articleStatus(t, uint(id)))
+			}
+		})
+	}
+}
+
+func TestReviewArticle(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author1", enums.COMMON_USER, 1)
+	e.createUser(t, "author2", enums.COMMON_USER, 2)
+	e.createUser(t, "admin1", enums.SCHOOL_ADMIN, 1)
+	e.createUser(t, "admin0", enums.SCHOOL_ADMIN, 0)
+	e.createUser(t, "super", enums.SUPER_ADMIN, 0)
+	needReview := e.createCategory(t, "review", 1)
+	a1 := e.createArticle(t, "author1", "a1", needReview)
+	a2 := e.createArticle(t, "author1", "a2", needReview)
+	a3 := e.createArticle(t, "author2", "a3", needReview)
+
+	// 学校管理员只能看到本学院作者的待审核文章
+	listIds := func(reviewerId string, reviewerRole int) []uint {
+		list, err := e.GetReviewArticleList(ctx, reviewerId, reviewerRole, &v1.PageRequest{})
+		require.NoError(t, err)
+		ids := make([]uint, 0, len(list.ArticleDataList))
+		for _, article := range list.ArticleDataList {
+			ids = append(ids, article.ArticleID)
+		}
+		return ids
+	}
+	assert.ElementsMatch(t, []uint{a1, a2}, listIds("admin1", enums.SCHOOL_ADMIN))
+	assert.ElementsMatch(t, []uint{a1, a2, a3}, listIds("super", enums.SUPER_ADMIN))
+	_, err := e.GetReviewArticleList(ctx, "admin0", enums.SCHOOL_ADMIN, &v1.PageRequest{})
+	assert.ErrorIs(t, err, v1.ErrPermissionDenied)
+	require.NoError(t, e.db.Where("1 = 1").Delete(&model.EsOutbox{}).Error)
+
+	tests := []struct {
+		name       string
+		reviewerId string
+		role       int
+		req        v1.ReviewArticleReq
+		wantErr    error
+		wantStatus int
+		wantNotify int
+	}{
+		{"other college", "admin1", enums.SCHOOL_ADMIN, v1.ReviewArticleReq{ArticleID: a3, Approved: true}, v1.ErrPermissionDenied, enums.StatusPendingReview, 0},
+		{"admin without college", "admin0", enums.SCHOOL_ADMIN, v1.ReviewArticleReq{ArticleID: a1, Approved: true}, v1.ErrPermissionDenied, enums.StatusPendingReview, 0},
+		{"reject without reason", "admin1", enums.SCHOOL_ADMIN, v1.ReviewArticleReq{ArticleID: a1}, v1.ErrRejectReasonEmpty, enums.StatusPendingReview, 0},
+		{"approve", "admin1", enums.SCHOOL_ADMIN, v1.ReviewArticleReq{ArticleID: a1, Approved: true}, nil, enums.StatusPublished, enums.NotifyArticleApproved},
+		{"already reviewed", "admin1", enums.SCHOOL_ADMIN, v1.ReviewArticleReq{ArticleID: a1, Approved: true}, v1.ErrArticleStatusError, enums.StatusPublished, 0},
+		{"reject", "admin1", enums.SCHOOL_ADMIN, v1.ReviewArticleReq{ArticleID: a2, Reason: "too short"}, nil, enums.StatusRejected, enums.NotifyArticleRejected},
+		{"super admin any college", "super", enums.SUPER_ADMIN, v1.ReviewArticleReq{ArticleID: a3, Approved: true}, nil, enums.StatusPublished, enums.NotifyArticleApproved},
+		{"not exist", "super", enums.SUPER_ADMIN, v1.ReviewArticleReq{ArticleID: 999, Approved: true}, v1.ErrArticleNotExist, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notifications, outbox int64
+			require.NoError(t, e.db.Model(&model.Notification{}).Count(&notifications).Error)
+			require.NoError(t, e.db.Model(&model.EsOutbox{}).Count(&outbox).Error)
+
+			err := e.ReviewArticle(ctx, tt.reviewerId, tt.role, &tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantStatus != 0 {
+				assert.Equal(t, tt.wantStatus, e.articleStatus(t, tt.req.ArticleID))
+			}
+
+			var newNotifications []model.Notification
+			require.NoError(t, e.db.Offset(int(notifications)).Find(&newNotifications).Error)
+			var newOutbox int64
+			require.NoError(t, e.db.Model(&model.EsOutbox{}).Count(&newOutbox).Error)
+			if tt.wantNotify == 0 {
+				assert.Empty(t, newNotifications)
+				assert.Equal(t, outbox, newOutbox)
+				return
+			}
+			// 通知作者并写入es同步任务
+			require.Len(t, newNotifications, 1)
+			assert.Equal(t, tt.wantNotify, newNotifications[0].Type)
+			assert.Equal(t, tt.req.ArticleID, newNotifications[0].RelatedId)
+			assert.Equal(t, outbox+1, newOutbox)
+			if !tt.req.Approved {
+				article, err := e.articleRepository.GetArticleFromDB(ctx, tt.req.ArticleID)
+				require.NoError(t, err)
+				assert.Equal(t, tt.req.Reason, article.ReviewRemark)
+				assert.Contains(t, newNotifications[0].Content, tt.req.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package article
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/service"
+	"projectName/pkg/utils"
+)
+
+// saveRevision 修改文章前保存旧内容和旧标签为新的历史版本，tags 为修改后的标签，内容和标签都未变化时不保存
+func (s *articleService) saveRevision(ctx context.Context, previous *model.Article, current *model.Article, tags []string, editorId string) error {
+	tagNames, err := s.tagRepository.GetTagNamesByArticleIds(ctx, []uint{previous.ArticleID})
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	previousTags := tagNames[previous.ArticleID]
+	if !articleContentChanged(previous, current) && sameTags(previousTags, tags) {
+		return nil
+	}
+	version, err := s.articleRevisionRepository.GetMaxVersion(ctx, previous.ArticleID)
+	if err != nil {
+		return v1.ErrQueryFailed
+	}
+	if err = s.articleRevisionRepository.CreateRevision(ctx, model.NewArticleRevision(previous, previousTags, version+1, editorId)); err != nil {
+		return v1.ErrInsertFailed
+	}
+	return nil
+}
+
+// sameTags 判断两组标签是否相同，不考虑顺序
+func sameTags(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, tag := range a {
+		set[tag] = true
+	}
+	for _, tag := range b {
+		if !set[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// articleContentChanged 判断文章的可编辑内容是否发生变化，状态变化不算
+func articleContentChanged(previous *model.Article, current *model.Article) bool {
+	previousVisibility, currentVisibility := previous.Visibility(), current.Visibility()
+	return previous.Title != current.Title ||
+		previous.Content != current.Content ||
+		previous.ContentShort != current.ContentShort ||
+		previous.CategoryID != current.CategoryID ||
+		previous.Importance != current.Importance ||
+		!previousVisibility.Equal(&currentVisibility) ||
+		previous.CommentDisabled != current.CommentDisabled ||
+		previous.SourceURI != current.SourceURI ||
+		!bytes.Equal(previous.UploadedFiles, current.UploadedFiles)
+}
+
+// checkRevisionPermission 历史版本仅作者本人和超级管理员可以查看和回滚
+func (s *articleService) checkRevisionPermission(ctx context.Context, userId string, roleType int, articleId uint) (*model.Article, error) {
+	article, err := s.articleRepository.GetArticle(ctx, articleId)
+	if err != nil {
+		return nil, v1.ErrArticleNotExist
+	}
+	if article.UserID != userId && roleType != enums.SUPER_ADMIN {
+		return nil, v1.ErrPermissionDenied
+	}
+	return article, nil
+}
+
+// GetArticleRevisionList 分页获取文章历史版本，按版本号倒序
+func (s *articleService) GetArticleRevisionList(ctx context.Context, userId string, roleType int, req *v1.GetArticleRevisionListReq) (*v1.ArticleRevisionList, error) {
+	if _, err := s.checkRevisionPermission(ctx, userId, roleType, req.ArticleID); err != nil {
+		return nil, err
+	}
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	revisions, total, err := s.articleRevisionRepository.GetRevisionList(ctx, req.ArticleID, pageIndex, pageSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	editorIds := make([]string, 0, len(revisions))
+	for _, revision := range revisions {
+		editorIds = append(editorIds, revision.EditorId)
+	}
+	loader := s.newArticleLoader()
+	if err = loader.loadAuthors(ctx, editorIds); err != nil {
+		return nil, err
+	}
+	var revisionList []*v1.ArticleRevisionData
+	for _, revision := range revisions {
+		editorName := loader.authors[revision.EditorId]
+		revisionList = append(revisionList, &v1.ArticleRevisionData{
+			Version:      revision.Version,
+			Title:        revision.Title,
+			ContentShort: revision.ContentShort,
+			CategoryID:   revision.CategoryID,
+			VisibleRange: buildVisibilityData(revision.Visibility()),
+			Editor:       editorName,
+			CreatedAt:    utils.TimeFormat(revision.CreatedAt, utils.FormatDateTime),
+		})
+	}
+	return &v1.ArticleRevisionList{
+		RevisionList: revisionList,
+		PageResponse: v1.PageResponse{
+			TotalCount: total,
+			PageIndex:  pageIndex,
+			PageSize:   pageSize,
+		},
+	}, nil
+}
+
+// GetArticleRevisionDiff 按行比较两个版本的正文，版本号为 0 表示文章当前内容
+func (s *articleService) GetArticleRevisionDiff(ctx context.Context, userId string, roleType int, req *v1.GetArticleRevisionDiffReq) (*v1.ArticleRevisionDiffData, error) {
+	article, err := s.checkRevisionPermission(ctx, userId, roleType, req.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	fromTitle, fromContent, err := s.getRevisionContent(ctx, article, req.FromVersion)
+	if err != nil {
+		return nil, err
+	}
+	toTitle, toContent, err := s.getRevisionContent(ctx, article, req.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	dmp := diffmatchpatch.New()
+	fromChars, toChars, lines := dmp.DiffLinesToChars(fromContent, toContent)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(fromChars, toChars, false), lines)
+	diffs = dmp.DiffCleanupSemantic(diffs)
+
+	diffItems := make([]v1.DiffItem, 0, len(diffs))
+	for _, diff := range diffs {
+		var diffType string
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			diffType = "insert"
+		case diffmatchpatch.DiffDelete:
+			diffType = "delete"
+		default:
+			diffType = "equal"
+		}
+		diffItems = append(diffItems, v1.DiffItem{Type: diffType, Text: diff.Text})
+	}
+	return &v1.ArticleRevisionDiffData{
+		FromVersion: req.FromVersion,
+		ToVersion:   req.ToVersion,
+		FromTitle:   fromTitle,
+		ToTitle:     toTitle,
+		Diffs:       diffItems,
+	}, nil
+}
+
+// getRevisionContent 获取指定版本的标题和正文，版本号为 0 时取文章当前内容
+func (s *articleService) getRevisionContent(ctx context.Context, article *model.Article, version int) (string, string, error) {
+	if version == 0 {
+		return article.Title, article.Content, nil
+	}
+	revision, err := s.articleRevisionRepository.GetRevision(ctx, article.ArticleID, version)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return "", "", v1.ErrRevisionNotExist
+		}
+		return "", "", v1.ErrQueryFailed
+	}
+	return revision.Title, revision.Content, nil
+}
+
+// RollbackArticle 将文章回滚到指定历史版本，回滚前的内容同样保存为历史版本
+// 标签一并回滚，早期未记录标签的版本保留当前标签；版本中的附件重新校验并关联到文章
+func (s *articleService) RollbackArticle(ctx context.Context, userId string, roleType int, req *v1.RollbackArticleReq) (*v1.ArticleData, error) {
+	// 回滚会整行保存文章，不读取缓存
+	article, err := s.articleRepository.GetArticleFromDB(ctx, req.ArticleID)
+	if err != nil {
+		return nil, v1.ErrArticleNotExist
+	}
+	if article.UserID != userId && roleType != enums.SUPER_ADMIN {
+		return nil, v1.ErrPermissionDenied
+	}
+	revision, err := s.articleRevisionRepository.GetRevision(ctx, req.ArticleID, req.Version)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrRevisionNotExist
+		}
+		return nil, v1.ErrQueryFailed
+	}
+
+	var files []v1.FileUpload
+	if len(revision.UploadedFiles) > 0 {
+		if err = json.Unmarshal(revision.UploadedFiles, &files); err != nil {
+			return nil, v1.ErrQueryFailed
+		}
+	}
+	uploadedFilesData, attachmentIds, err := s.resolveUploadedFiles(ctx, files, article.ArticleID, article.UserID, userId)
+	if err != nil {
+		return nil, err
+	}
+	tags := revision.Tags
+	if tags == nil {
+		tagNames, err := s.tagRepository.GetTagNamesByArticleIds(ctx, []uint{article.ArticleID})
+		if err != nil {
+			return nil, v1.ErrQueryFailed
+		}
+		tags = tagNames[article.ArticleID]
+	}
+
+	previous := *article
+	article.Title = revision.Title
+	article.Content = revision.Content
+	article.ContentShort = revision.ContentShort
+	article.CategoryID = revision.CategoryID
+	article.Importance = revision.Importance
+	article.SetVisibility(revision.Visibility())
+	article.CommentDisabled = revision.CommentDisabled
+	article.SourceURI = revision.SourceURI
+	article.UploadedFiles = uploadedFilesData
+	// 回滚等同于一次修改，草稿仍为草稿，其余按审核开关重新决定状态
+	if article.Status != enums.StatusDraft {
+		status, err := s.getPublishStatus(ctx, article.CategoryID, article.PublishAt)
+		if err != nil {
+			return nil, err
+		}
+		article.Status = status
+	}
+
+	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.saveRevision(ctx, &previous, article, tags, userId); err != nil {
+			return err
+		}
+		if _, err := s.articleRepository.UpdateArticle(ctx, article); err != nil {
+			return v1.ErrUpdateArticleFailed
+		}
+		if err := s.setArticleTags(ctx, article.ArticleID, tags); err != nil {
+			return err
+		}
+		if err := s.attachmentRepository.BindArticle(ctx, article.ArticleID, attachmentIds); err != nil {
+			return v1.ErrUpdateFailed
+		}
+		// 写入es同步任务，与文章修改一同提交
+		if err := s.syncEsArticle(ctx, article); err != nil {
+			return v1.ErrUpdateEsArticleFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildArticleData(ctx, &model.User{UserId: userId, RoleType: roleType}, article)
+}
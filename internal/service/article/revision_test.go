@@ -0,0 +1,115 @@
+package article
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameTags(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want bool
+	}{
+		{"both empty", nil, []string{}, true},
+		{"different order", []string{"a", "b"}, []string{"b", "a"}, true},
+		{"different length", []string{"a"}, []string{"a", "b"}, false},
+		{"different tag", []string{"a", "b"}, []string{"a", "c"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sameTags(tt.a, tt.b))
+		})
+	}
+}
+
+func TestArticleRevisions(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "other", enums.COMMON_USER, 1)
+	e.createUser(t, "super", enums.SUPER_ADMIN, 0)
+	categoryId := e.createCategory(t, "c", 0)
+	request := func(title, content string, tags ...string) v1.CreateArticleRequest {
+		return v1.CreateArticleRequest{
+			Title:        title,
+			Content:      content,
+			AuthorID:     "author",
+			CategoryID:   categoryId,
+			VisibleRange: v1.Visibility{Scope: enums.VisiblePublic},
+			Tags:         tags,
+		}
+	}
+	create := request("v1", "line1\nline2\n", "go")
+	id, err := e.CreateArticle(ctx, &create)
+	require.NoError(t, err)
+	articleId := uint(id)
+	update := func(userId string, req v1.CreateArticleRequest) {
+		_, err := e.UpdateArticle(ctx, userId, enums.COMMON_USER, &v1.UpdateArticleRequest{ArticleID: articleId, CreateArticleRequest: req})
+		require.NoError(t, err)
+	}
+	revisionVersions := func() []int {
+		list, err := e.GetArticleRevisionList(ctx, "author", enums.COMMON_USER, &v1.GetArticleRevisionListReq{ArticleID: articleId})
+		require.NoError(t, err)
+		var versions []int
+		for _, revision := range list.RevisionList {
+			versions = append(versions, revision.Version)
+		}
+		return versions
+	}
+
+	// 内容和标签都未变化时不保存版本
+	update("author", request("v1", "line1\nline2\n", "go"))
+	assert.Empty(t, revisionVersions())
+	update("author", request("v2", "line1\nline3\n", "go", "db"))
+	// 只修改标签也会保存版本
+	update("author", request("v2", "line1\nline3\n", "db"))
+	assert.Equal(t, []int{2, 1}, revisionVersions())
+
+	t.Run("permission", func(t *testing.T) {
+		_, err := e.GetArticleRevisionList(ctx, "other", enums.COMMON_USER, &v1.GetArticleRevisionListReq{ArticleID: articleId})
+		assert.Equal(t, v1.ErrPermissionDenied, err)
+		_, err = e.GetArticleRevisionList(ctx, "super", enums.SUPER_ADMIN, &v1.GetArticleRevisionListReq{ArticleID: articleId})
+		assert.NoError(t, err)
+		_, err = e.RollbackArticle(ctx, "other", enums.COMMON_USER, &v1.RollbackArticleReq{ArticleID: articleId, Version: 1})
+		assert.Equal(t, v1.ErrPermissionDenied, err)
+	})
+
+	t.Run("diff with current content", func(t *testing.T) {
+		diff, err := e.GetArticleRevisionDiff(ctx, "author", enums.COMMON_USER, &v1.GetArticleRevisionDiffReq{ArticleID: articleId, FromVersion: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "v1", diff.FromTitle)
+		assert.Equal(t, "v2", diff.ToTitle)
+		assert.Equal(t, []v1.DiffItem{
+			{Type: "equal", Text: "line1\n"},
+			{Type: "delete", Text: "line2\n"},
+			{Type: "insert", Text: "line3\n"},
+		}, diff.Diffs)
+
+		_, err = e.GetArticleRevisionDiff(ctx, "author", enums.COMMON_USER, &v1.GetArticleRevisionDiffReq{ArticleID: articleId, FromVersion: 9})
+		assert.Equal(t, v1.ErrRevisionNotExist, err)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		_, err := e.RollbackArticle(ctx, "author", enums.COMMON_USER, &v1.RollbackArticleReq{ArticleID: articleId, Version: 9})
+		assert.Equal(t, v1.ErrRevisionNotExist, err)
+
+		data, err := e.RollbackArticle(ctx, "author", enums.COMMON_USER, &v1.RollbackArticleReq{ArticleID: articleId, Version: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "v1", data.Title)
+		assert.Equal(t, "line1\nline2\n", data.Content)
+		assert.Equal(t, []string{"go"}, data.Tags)
+		assert.Equal(t, enums.StatusPublished, e.articleStatus(t, articleId))
+		// 回滚前的内容保存为新版本
+		assert.Equal(t, []int{3, 2, 1}, revisionVersions())
+		diff, err := e.GetArticleRevisionDiff(ctx, "author", enums.COMMON_USER, &v1.GetArticleRevisionDiffReq{ArticleID: articleId, FromVersion: 3, ToVersion: 2})
+		require.NoError(t, err)
+		assert.Equal(t, "v2", diff.FromTitle)
+		assert.Equal(t, "v2", diff.ToTitle)
+	})
+}
@@ -0,0 +1,238 @@
+package article
+
+import (
+	"github.com/olivere/elastic/v7"
+	"github.com/spf13/viper"
+	v1 "projectName/api/v1"
+	"projectName/internal/model"
+	"projectName/pkg/utils"
+	"strings"
+)
+
+const (
+	esTitleField        = "title"
+	esContentShortField = "content_short"
+	esContentField      = "content"
+	esAttachmentsField  = "attachments"
+	esArticleIdField    = "article_id"
+	esTitleSuggestField = "title.suggest" // 标题的边输入边搜索字段，用于匹配尚未输入完整的关键字
+)
+
+// searchField 参与关键字搜索和高亮的字段
+type searchField struct {
+	name  string                                            // es 字段名
+	boost float64                                           // 相关度权重，越大排序越靠前
+	apply func(info *v1.ArticleSearchInfo, fragment string) // 将高亮片段写入搜索结果
+}
+
+// searchBoosts 关键字搜索的字段权重，标题 > 摘要 > 正文 > 附件，可通过配置 search.boost 覆盖
+type searchBoosts struct {
+	fields     []searchField
+	attachment float64 // 附件文本的权重
+}
+
+// 默认的字段权重
+var defaultSearchBoosts = map[string]float64{
+	esTitleField:        3,
+	esContentShortField: 2,
+	esContentField:      1,
+	esAttachmentsField:  0.5,
+}
+
+// searchBoost 读取字段权重配置，未配置或不大于 0 时使用默认值
+func searchBoost(conf *viper.Viper, name string) float64 {
+	boost := conf.GetFloat64("search.boost." + name)
+	if boost <= 0 {
+		boost = defaultSearchBoosts[name]
+	}
+	return boost
+}
+
+func newSearchBoosts(conf *viper.Viper) searchBoosts {
+	return searchBoosts{
+		fields: []searchField{
+			{
+				name:  esTitleField,
+				boost: searchBoost(conf, esTitleField),
+				apply: func(info *v1.ArticleSearchInfo, fragment string) { info.Title = fragment },
+			},
+			{
+				name:  esContentShortField,
+				boost: searchBoost(conf, esContentShortField),
+				apply: func(info *v1.ArticleSearchInfo, fragment string) { info.ContentShort = fragment },
+			},
+			{
+				name:  esContentField,
+				boost: searchBoost(conf, esContentField),
+				apply: func(info *v1.ArticleSearchInfo, fragment string) { info.Content = fragment },
+			},
+		},
+		attachment: searchBoost(conf, esAttachmentsField),
+	}
+}
+
+// boost 返回字段的权重，未配置的字段权重为 1
+func (b searchBoosts) boost(name string) float64 {
+	for _, field := range b.fields {
+		if field.name == name {
+			return field.boost
+		}
+	}
+	return 1
+}
+
+// keywordQuery 在所有搜索字段中匹配关键字，phrase 为 true 时按短语匹配
+func (b searchBoosts) keywordQuery(keyword string, phrase bool) elastic.Query {
+	query := elastic.NewMultiMatchQuery(keyword)
+	for _, field := range b.fields {
+		query = query.FieldWithBoost(field.name, field.boost)
+	}
+	if phrase {
+		return query.Type("phrase")
+	}
+	return query.Type("best_fields").TieBreaker(0.3)
+}
+
+// fieldQuery 在单个字段中匹配关键字
+func (b searchBoosts) fieldQuery(name, keyword string, phrase bool) elastic.Query {
+	if phrase {
+		return elastic.NewMatchPhraseQuery(name, keyword).Boost(b.boost(name))
+	}
+	return elastic.NewMatchQuery(name, keyword).Boost(b.boost(name))
+}
+
+// highlight 为所有搜索字段添加高亮
+func (b searchBoosts) highlight() *elastic.Highlight {
+	highlight := elastic.NewHighlight().PreTags("<mark>").PostTags("</mark>")
+	for _, field := range b.fields {
+		highlight = highlight.Field(field.name)
+	}
+	return highlight
+}
+
+// applyHighlight 用高亮片段替换搜索结果中对应字段的内容
+func (b searchBoosts) applyHighlight(info *v1.ArticleSearchInfo, hit *elastic.SearchHit) {
+	for _, field := range b.fields {
+		if fragments, ok := hit.Highlight[field.name]; ok {
+			field.apply(info, strings.Join(fragments, "..."))
+		}
+	}
+}
+
+// buildTitlePrefixQuery 按前缀匹配标题，最后一个词可以是未输入完整的前缀
+func buildTitlePrefixQuery(keyword string) elastic.Query {
+	return elastic.NewMultiMatchQuery(keyword,
+		esTitleSuggestField, esTitleSuggestField+"._2gram", esTitleSuggestField+"._3gram").
+		Type("bool_prefix")
+}
+
+// searchSortFields 搜索结果允许的排序字段
+var searchSortFields = map[string]bool{
+	"_score":     true,
+	"created_at": true,
+	"updated_at": true,
+	"importance": true,
+	"views":      true, // 按热度（浏览量）排序
+}
+
+// buildSearchSorters 构建搜索结果的排序，默认按相关度降序，最后按文章ID排序保证游标分页的顺序稳定
+func buildSearchSorters(column, order string) []elastic.Sorter {
+	if !searchSortFields[column] {
+		column = "_score"
+	}
+	ascending := strings.ToLower(order) == "asc"
+	var primary elastic.Sorter
+	if column == "_score" {
+		primary = elastic.NewScoreSort().Order(ascending)
+	} else {
+		primary = elastic.NewFieldSort(column).Order(ascending)
+	}
+	return []elastic.Sorter{primary, elastic.NewFieldSort(esArticleIdField).Desc()}
+}
+
+// buildSearchQuery 构建文章搜索的查询条件，只返回 viewer 可见的文章
+// 关键字、标题和内容条件放在同一组中至少满足一个；短语匹配的关键字必须满足，附件文本命中时也算满足
+func (b searchBoosts) buildSearchQuery(req *v1.GetArticleListByEsReq, viewer *model.User) *elastic.BoolQuery {
+	query := elastic.NewBoolQuery()
+	// 至少满足其中一个的匹配条件，查询中有 filter 时 es 不再要求满足外层的 should，因此单独分组
+	match := elastic.NewBoolQuery()
+	hasMatch := false
+	should := func(q elastic.Query) {
+		match = match.Should(q)
+		hasMatch = true
+	}
+
+	if req.AdvSearch { // 高级搜索
+		// 根据标题进行搜索
+		if req.Title != "" {
+			should(b.fieldQuery(esTitleField, req.Title, false))
+		}
+
+		// 根据内容进行搜索
+		if req.Content != "" {
+			should(b.fieldQuery(esContentField, req.Content, false))
+		}
+
+		// 根据关键字进行全文搜索
+		for _, keyword := range req.Keywords {
+			if req.PhraseMatch {
+				query = query.Must(b.fieldQuery(esContentShortField, keyword, true))
+			} else {
+				should(b.fieldQuery(esContentShortField, keyword, false))
+			}
+		}
+		// 根据发布时间进行范围过滤
+		if req.CreateTimeStart != "" && req.CreateTimeEnd != "" {
+			query = query.Filter(elastic.NewRangeQuery("created_at").
+				Gte(req.CreateTimeStart).Lte(req.CreateTimeEnd))
+		}
+
+		// 根据重要性进行过滤
+		importance, _ := utils.ToInt(req.Importance)
+		if importance > 0 {
+			query = query.Filter(elastic.NewTermsQuery("importance", importance))
+		}
+	} else { // 普通搜索，只要满足一个条件即可
+		// 根据关键字进行全文搜索
+		for _, keyword := range req.Keywords {
+			if req.PhraseMatch {
+				// 短语出现在任一搜索字段即可
+				query = query.Must(b.keywordQuery(keyword, true))
+			} else {
+				should(b.keywordQuery(keyword, false))
+				should(buildTitlePrefixQuery(keyword))
+			}
+		}
+	}
+
+	// 搜索附件文本，命中的附件通过 inner_hits 返回文件名和高亮片段
+	// 没有其他匹配条件时（短语匹配）只用于提高相关度
+	if attachmentQuery := buildAttachmentQuery(req, b.attachment); attachmentQuery != nil {
+		if hasMatch {
+			should(attachmentQuery)
+		} else {
+			query = query.Should(attachmentQuery)
+		}
+	}
+	if hasMatch {
+		query = query.Must(match.MinimumNumberShouldMatch(1))
+	}
+
+	// 按当前用户过滤可见的文章
+	query = query.Filter(buildVisibilityQuery(viewer))
+
+	// 根据分类 ID 进行过滤
+	if len(req.Categories) > 0 {
+		var categories []interface{}
+		for _, category := range req.Categories {
+			categories = append(categories, category)
+		}
+		query = query.Filter(elastic.NewTermsQuery("category_id", categories...))
+	}
+
+	// 根据标签进行过滤，需同时包含全部标签
+	for _, tag := range req.Tags {
+		query = query.Filter(elastic.NewTermQuery(esTagsField, tag))
+	}
+	return query
+}
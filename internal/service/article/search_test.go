@@ -0,0 +1,157 @@
+package article
+
+import (
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"testing"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	viewer := &model.User{UserId: "u1", RoleType: enums.SUTDENT_USER, CollegeId: 1}
+	tests := []struct {
+		name string
+		req  v1.GetArticleListByEsReq
+		// 必须满足其一的匹配条件数量，0 表示没有匹配条件组
+		wantMatch int
+		// 外层 must 中除匹配条件组外的数量（短语匹配）
+		wantMust int
+		// 外层可选的 should 数量，只在有 must 时允许出现
+		wantShould int
+		// filter 数量，包含可见范围
+		wantFilter int
+	}{
+		{"no conditions", v1.GetArticleListByEsReq{}, 0, 0, 0, 1},
+		{"keyword", v1.GetArticleListByEsReq{Keywords: []string{"go"}}, 3, 0, 0, 1},
+		{"keywords", v1.GetArticleListByEsReq{Keywords: []string{"go", "es"}}, 5, 0, 0, 1},
+		{"phrase keyword", v1.GetArticleListByEsReq{Keywords: []string{"go es"}, PhraseMatch: true}, 0, 1, 1, 1},
+		{"keyword with filters", v1.GetArticleListByEsReq{Keywords: []string{"go"}, Categories: []int{1, 2}, Tags: []string{"a", "b"}}, 3, 0, 0, 4},
+		{"adv title", v1.GetArticleListByEsReq{AdvSearch: true, Title: "go"}, 1, 0, 0, 1},
+		{"adv title and content", v1.GetArticleListByEsReq{AdvSearch: true, Title: "go", Content: "es"}, 3, 0, 0, 1},
+		{"adv title and phrase keyword", v1.GetArticleListByEsReq{AdvSearch: true, Title: "go", Keywords: []string{"a b"}, PhraseMatch: true}, 2, 1, 0, 1},
+		{
+			"adv filters only",
+			v1.GetArticleListByEsReq{AdvSearch: true, Importance: "2", CreateTimeStart: "2024-01-01", CreateTimeEnd: "2024-02-01"},
+			0, 0, 0, 3,
+		},
+	}
+	boosts := newSearchBoosts(viper.New())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := boosts.buildSearchQuery(&tt.req, viewer).Source()
+			require.NoError(t, err)
+			query := toJson(t, src)["bool"].(map[string]interface{})
+
+			must := clauses(query["must"])
+			var matchGroups []map[string]interface{}
+			var others int
+			for _, q := range must {
+				if group, ok := q["bool"].(map[string]interface{}); ok {
+					matchGroups = append(matchGroups, group)
+				} else {
+					others++
+				}
+			}
+			if tt.wantMatch == 0 {
+				assert.Empty(t, matchGroups)
+			} else {
+				require.Len(t, matchGroups, 1)
+				assert.Equal(t, "1", matchGroups[0]["minimum_should_match"])
+				assert.Len(t, clauses(matchGroups[0]["should"]), tt.wantMatch)
+			}
+			assert.Equal(t, tt.wantMust, others)
+
+			should := clauses(query["should"])
+			assert.Len(t, should, tt.wantShould)
+			if len(should) > 0 {
+				// 有 filter 时外层的 should 是可选的，必须另有 must 条件限定结果
+				assert.NotEmpty(t, must)
+			}
+			assert.Len(t, clauses(query["filter"]), tt.wantFilter)
+		})
+	}
+}
+
+func TestNewSearchBoosts(t *testing.T) {
+	tests := []struct {
+		name           string
+		settings       map[string]interface{}
+		wantFields     map[string]float64
+		wantAttachment float64
+	}{
+		{"defaults", nil, map[string]float64{esTitleField: 3, esContentShortField: 2, esContentField: 1}, 0.5},
+		{
+			"configured",
+			map[string]interface{}{"search.boost.title": 5, "search.boost.content": 1.5, "search.boost.attachments": 2},
+			map[string]float64{esTitleField: 5, esContentShortField: 2, esContentField: 1.5},
+			2,
+		},
+		{
+			"non positive falls back",
+			map[string]interface{}{"search.boost.title": 0, "search.boost.content_short": -1},
+			map[string]float64{esTitleField: 3, esContentShortField: 2, esContentField: 1},
+			0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := viper.New()
+			for k, v := range tt.settings {
+				conf.Set(k, v)
+			}
+			boosts := newSearchBoosts(conf)
+			for name, want := range tt.wantFields {
+				assert.Equal(t, want, boosts.boost(name), name)
+			}
+			assert.Equal(t, tt.wantAttachment, boosts.attachment)
+			// 未参与搜索的字段权重为 1
+			assert.Equal(t, float64(1), boosts.boost("unknown"))
+		})
+	}
+}
+
+func TestSearchBoosts_Queries(t *testing.T) {
+	conf := viper.New()
+	conf.Set("search.boost.title", 4)
+	boosts := newSearchBoosts(conf)
+	source := func(q elastic.Query) map[string]interface{} {
+		src, err := q.Source()
+		require.NoError(t, err)
+		return toJson(t, src)
+	}
+
+	multiMatch := source(boosts.keywordQuery("go", false))["multi_match"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"title^4.000000", "content_short^2.000000", "content^1.000000"}, multiMatch["fields"])
+	assert.Equal(t, "best_fields", multiMatch["type"])
+	phrase := source(boosts.keywordQuery("go", true))["multi_match"].(map[string]interface{})
+	assert.Equal(t, "phrase", phrase["type"])
+
+	match := source(boosts.fieldQuery(esTitleField, "go", false))["match"].(map[string]interface{})
+	assert.Equal(t, float64(4), match[esTitleField].(map[string]interface{})["boost"])
+	matchPhrase := source(boosts.fieldQuery(esContentShortField, "go", true))["match_phrase"].(map[string]interface{})
+	assert.Equal(t, float64(2), matchPhrase[esContentShortField].(map[string]interface{})["boost"])
+
+	highlight := source(boosts.highlight())["fields"].(map[string]interface{})
+	assert.Len(t, highlight, 3)
+	for _, field := range []string{esTitleField, esContentShortField, esContentField} {
+		assert.Contains(t, highlight, field)
+	}
+}
+
+func TestSearchBoosts_ApplyHighlight(t *testing.T) {
+	boosts := newSearchBoosts(viper.New())
+	info := &v1.ArticleSearchInfo{Title: "title", ContentShort: "short", Content: "content"}
+	boosts.applyHighlight(info, &elastic.SearchHit{Highlight: elastic.SearchHitHighlight{
+		esTitleField:        {"<mark>ti</mark>tle"},
+		esContentShortField: {"a <mark>b</mark>", "c <mark>b</mark>"},
+	}})
+	assert.Equal(t, "<mark>ti</mark>tle", info.Title)
+	assert.Equal(t, "a <mark>b</mark>...c <mark>b</mark>", info.ContentShort)
+	// 没有高亮的字段保留原内容
+	assert.Equal(t, "content", info.Content)
+}
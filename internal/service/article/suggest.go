@@ -0,0 +1,149 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+	v1 "projectName/api/v1"
+	"projectName/internal/model"
+	"projectName/pkg/utils"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	suggestTitleSize       = 8                // 标题联想返回的数量
+	suggestCorrectionSize  = 3                // 拼写纠错返回的数量
+	suggestMaxKeywordLen   = 50               // 参与联想的关键字最大字符数
+	suggestCacheExpiration = 30 * time.Second // 联想结果的缓存时间，只用于缓解热门前缀的重复查询
+	suggestCachePrefix     = "search:suggest:"
+	esCorrectionSuggester  = "correction"
+)
+
+// GetSearchSuggest 按输入的关键字联想文章标题并给出拼写纠错，只返回当前用户可见的文章
+// 按可见类型可见的部分只取决于角色和学院，按角色和学院缓存；作者本人和共享给该用户的文章单独查询，不缓存
+func (s *articleService) GetSearchSuggest(ctx context.Context, userId string, keyword string) (*v1.SearchSuggestData, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return &v1.SearchSuggestData{Titles: []v1.SearchSuggestTitle{}, Corrections: []v1.SearchCorrection{}}, nil
+	}
+	if utf8.RuneCountInString(keyword) > suggestMaxKeywordLen {
+		keyword = string([]rune(keyword)[:suggestMaxKeywordLen])
+	}
+	viewer, err := getViewer(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.getScopeSearchSuggest(ctx, viewer, keyword)
+	if err != nil {
+		return nil, err
+	}
+	own, err := s.searchSuggest(ctx, keyword, buildUserVisibilityQuery(viewer), false)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uint]bool, len(data.Titles))
+	for _, title := range data.Titles {
+		seen[title.ArticleID] = true
+	}
+	for _, title := range own.Titles {
+		if len(data.Titles) >= suggestTitleSize {
+			break
+		}
+		if !seen[title.ArticleID] {
+			data.Titles = append(data.Titles, title)
+		}
+	}
+	return data, nil
+}
+
+// getScopeSearchSuggest 获取按可见类型可见的文章的联想结果，角色和学院相同的用户共享缓存
+func (s *articleService) getScopeSearchSuggest(ctx context.Context, viewer *model.User, keyword string) (*v1.SearchSuggestData, error) {
+	cacheKey := fmt.Sprintf("%s%d:%d:%s", suggestCachePrefix, viewer.RoleType, viewer.CollegeId, strings.ToLower(keyword))
+	if cached, ok, err := s.articleRepository.GetSearchSuggestCache(ctx, cacheKey); err == nil && ok {
+		var data v1.SearchSuggestData
+		if err = json.Unmarshal([]byte(cached), &data); err == nil {
+			return &data, nil
+		}
+	}
+	data, err := s.searchSuggest(ctx, keyword, buildScopeVisibilityQuery(viewer), true)
+	if err != nil {
+		return nil, err
+	}
+	if value, err := json.Marshal(data); err == nil {
+		_ = s.articleRepository.SetSearchSuggestCache(ctx, cacheKey, string(value), suggestCacheExpiration)
+	}
+	return data, nil
+}
+
+// searchSuggest 在 visibilityQuery 过滤后的文章中联想标题，correction 为 true 时同时给出拼写纠错
+func (s *articleService) searchSuggest(ctx context.Context, keyword string, visibilityQuery elastic.Query, correction bool) (*v1.SearchSuggestData, error) {
+	var suggester elastic.Suggester
+	if correction {
+		var err error
+		if suggester, err = buildCorrectionSuggester(keyword, visibilityQuery); err != nil {
+			s.Logger.Error("articleService.GetSearchSuggest build suggester error", zap.Error(err))
+			return nil, v1.ErrQueryFailed
+		}
+	}
+	query := elastic.NewBoolQuery().
+		Must(buildTitlePrefixQuery(keyword)).
+		Filter(visibilityQuery)
+	searchResult, err := s.articleRepository.SuggestEsArticles(ctx, query, suggester, suggestTitleSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+
+	data := &v1.SearchSuggestData{
+		Titles:      make([]v1.SearchSuggestTitle, 0, len(searchResult.Hits.Hits)),
+		Corrections: []v1.SearchCorrection{},
+	}
+	for _, hit := range searchResult.Hits.Hits {
+		var esArticle model.EsArticle
+		if err := json.Unmarshal(hit.Source, &esArticle); err != nil {
+			continue
+		}
+		id, err := utils.ToInt(hit.Id)
+		if err != nil {
+			continue
+		}
+		data.Titles = append(data.Titles, v1.SearchSuggestTitle{ArticleID: uint(id), Title: esArticle.Title})
+	}
+	for _, suggestion := range searchResult.Suggest[esCorrectionSuggester] {
+		for _, option := range suggestion.Options {
+			data.Corrections = append(data.Corrections, v1.SearchCorrection{
+				Text:        option.Text,
+				Highlighted: option.Highlighted,
+			})
+		}
+	}
+	return data, nil
+}
+
+// buildCorrectionSuggester 构建标题的短语纠错建议
+// 纠错候选词来自整个索引，通过 collate 只保留能匹配到当前用户可见文章的建议，避免泄露不可见文章中的词
+// 词项纠错（term suggester）无法按文档过滤，因此只使用短语纠错
+func buildCorrectionSuggester(keyword string, visibilityQuery elastic.Query) (elastic.Suggester, error) {
+	visibilitySource, err := visibilityQuery.Source()
+	if err != nil {
+		return nil, err
+	}
+	visibilityJSON, err := json.Marshal(visibilitySource)
+	if err != nil {
+		return nil, err
+	}
+	collate := fmt.Sprintf(`{"bool":{"must":{"match_phrase":{"%s":"{{suggestion}}"}},"filter":%s}}`,
+		esTitleField, visibilityJSON)
+	return elastic.NewPhraseSuggester(esCorrectionSuggester).
+		Text(keyword).
+		Field(esTitleField).
+		Size(suggestCorrectionSize).
+		CandidateGenerator(elastic.NewDirectCandidateGenerator(esTitleField).SuggestMode("always")).
+		Highlight("<mark>", "</mark>").
+		CollateQuery(elastic.NewScriptInline(collate)).
+		CollatePrune(false), nil
+}
@@ -0,0 +1,141 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"strings"
+	"testing"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSuggestRepository 替换 es 联想查询，缓存仍使用 miniredis
+type fakeSuggestRepository struct {
+	repository.ArticleRepository
+	scope       *elastic.SearchResult // 带纠错的按可见类型查询结果
+	own         *elastic.SearchResult // 作者本人和共享文章的查询结果
+	scopeCalls  int
+	ownCalls    int
+	suggesterOK bool
+}
+
+func (r *fakeSuggestRepository) SuggestEsArticles(_ context.Context, _ elastic.Query, suggester elastic.Suggester, _ int) (*elastic.SearchResult, error) {
+	if suggester != nil {
+		r.scopeCalls++
+		_, err := suggester.Source(false)
+		r.suggesterOK = err == nil
+		return r.scope, nil
+	}
+	r.ownCalls++
+	return r.own, nil
+}
+
+func suggestResult(ids ...uint) *elastic.SearchResult {
+	hits := make([]*elastic.SearchHit, 0, len(ids))
+	for _, id := range ids {
+		hits = append(hits, &elastic.SearchHit{
+			Id:     fmt.Sprint(id),
+			Source: json.RawMessage(fmt.Sprintf(`{"title":"title %d"}`, id)),
+		})
+	}
+	return &elastic.SearchResult{Hits: &elastic.SearchHits{Hits: hits}}
+}
+
+func TestGetSearchSuggest(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "u1", enums.SUTDENT_USER, 1)
+	e.createUser(t, "u2", enums.SUTDENT_USER, 1)
+
+	scope := suggestResult(1, 2)
+	scope.Suggest = elastic.SearchSuggest{esCorrectionSuggester: []elastic.SearchSuggestion{{
+		Options: []elastic.SearchSuggestionOption{{Text: "golang", Highlighted: "<mark>golang</mark>"}},
+	}}}
+	repo := &fakeSuggestRepository{
+		ArticleRepository: e.articleRepository,
+		scope:             scope,
+		own:               suggestResult(2, 3),
+	}
+	e.articleRepository = repo
+
+	// 空关键字不查询
+	data, err := e.GetSearchSuggest(ctx, "u1", "  ")
+	require.NoError(t, err)
+	assert.Empty(t, data.Titles)
+	assert.Empty(t, data.Corrections)
+	assert.Zero(t, repo.scopeCalls+repo.ownCalls)
+
+	// 不存在的用户
+	_, err = e.GetSearchSuggest(ctx, "missing", "go")
+	assert.Error(t, err)
+
+	data, err = e.GetSearchSuggest(ctx, "u1", "Golan")
+	require.NoError(t, err)
+	assert.True(t, repo.suggesterOK)
+	// 按可见类型的结果在前，本人的结果去重后追加
+	ids := make([]uint, 0, len(data.Titles))
+	for _, title := range data.Titles {
+		ids = append(ids, title.ArticleID)
+	}
+	assert.Equal(t, []uint{1, 2, 3}, ids)
+	assert.Equal(t, "title 1", data.Titles[0].Title)
+	require.Len(t, data.Corrections, 1)
+	assert.Equal(t, "golang", data.Corrections[0].Text)
+	assert.Equal(t, 1, repo.scopeCalls)
+	assert.Equal(t, 1, repo.ownCalls)
+
+	// 按角色、学院和小写的关键字缓存
+	assert.True(t, e.rdb.Exists(ctx, suggestCachePrefix+"1:1:golan").Val() == 1)
+
+	// 角色和学院相同的用户命中缓存，本人的部分仍然查询
+	_, err = e.GetSearchSuggest(ctx, "u2", "golan")
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.scopeCalls)
+	assert.Equal(t, 2, repo.ownCalls)
+
+	// 过长的关键字被截断
+	_, err = e.GetSearchSuggest(ctx, "u1", strings.Repeat("a", suggestMaxKeywordLen+10))
+	require.NoError(t, err)
+	assert.True(t, e.rdb.Exists(ctx, suggestCachePrefix+"1:1:"+strings.Repeat("a", suggestMaxKeywordLen)).Val() == 1)
+}
+
+func TestGetSearchSuggest_TitleLimit(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "u1", enums.SUTDENT_USER, 1)
+	repo := &fakeSuggestRepository{
+		ArticleRepository: e.articleRepository,
+		scope:             suggestResult(1, 2, 3, 4, 5, 6),
+		own:               suggestResult(7, 8, 9, 10),
+	}
+	e.articleRepository = repo
+
+	data, err := e.GetSearchSuggest(ctx, "u1", "go")
+	require.NoError(t, err)
+	assert.Len(t, data.Titles, suggestTitleSize)
+}
+
+func TestBuildCorrectionSuggester(t *testing.T) {
+	viewer := &model.User{UserId: "u1", RoleType: enums.SUTDENT_USER, CollegeId: 1}
+	suggester, err := buildCorrectionSuggester("golan", buildScopeVisibilityQuery(viewer))
+	require.NoError(t, err)
+	src, err := suggester.Source(true)
+	require.NoError(t, err)
+	phrase := toJson(t, src)[esCorrectionSuggester].(map[string]interface{})["phrase"].(map[string]interface{})
+
+	assert.Equal(t, esTitleField, phrase["field"])
+	// collate 查询带上可见范围，只保留能匹配到可见文章的纠错
+	collate := phrase["collate"].(map[string]interface{})
+	query := collate["query"].(map[string]interface{})["source"].(map[string]interface{})
+	filter := query["bool"].(map[string]interface{})["filter"]
+	visibility, err := buildScopeVisibilityQuery(viewer).Source()
+	require.NoError(t, err)
+	assert.Equal(t, toJson(t, visibility), filter)
+	assert.Equal(t, false, collate["prune"])
+}
@@ -0,0 +1,109 @@
+package article
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/service"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxArticleTags  = 10 // 每篇文章最多的标签数
+	maxTagLength    = 20 // 标签最大字符数
+	tagSuggestLimit = 10 // 标签联想返回的数量
+	tagFacetSize    = 20 // 搜索结果中返回的标签聚合数量
+	esTagsField     = "tags"
+)
+
+// normalizeTags 去除首尾空白、空标签和重复标签，并校验数量和长度
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, v1.ErrTagInvalid
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > maxArticleTags {
+		return nil, v1.ErrTagInvalid
+	}
+	return result, nil
+}
+
+// setArticleTags 保存文章标签，不存在的标签自动创建
+func (s *articleService) setArticleTags(ctx context.Context, articleId uint, tags []string) error {
+	tagModels, err := s.tagRepository.GetOrCreateTags(ctx, tags)
+	if err != nil {
+		return v1.ErrInsertFailed
+	}
+	tagIds := make([]uint, 0, len(tagModels))
+	for _, tag := range tagModels {
+		tagIds = append(tagIds, tag.Id)
+	}
+	if err = s.tagRepository.SetArticleTags(ctx, articleId, tagIds); err != nil {
+		return v1.ErrInsertFailed
+	}
+	return nil
+}
+
+// GetTagSuggest 按前缀联想标签，常用标签在前
+func (s *articleService) GetTagSuggest(ctx context.Context, keyword string) ([]*v1.TagData, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []*v1.TagData{}, nil
+	}
+	tags, err := s.tagRepository.SearchTags(ctx, keyword, tagSuggestLimit)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	tagList := make([]*v1.TagData, 0, len(tags))
+	for _, tag := range tags {
+		tagList = append(tagList, &v1.TagData{
+			TagId:        tag.Id,
+			TagName:      tag.TagName,
+			ArticleCount: tag.ArticleCount,
+		})
+	}
+	return tagList, nil
+}
+
+// GetArticleListByTag 分页获取带有指定标签且当前用户可以查看的已发布文章
+func (s *articleService) GetArticleListByTag(ctx context.Context, userId string, req *v1.GetArticleListByTagReq) (*v1.ArticleList, error) {
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	response := &v1.ArticleList{
+		ArticleDataList: []*v1.ArticleData{},
+		PageResponse: v1.PageResponse{
+			PageIndex: pageIndex,
+			PageSize:  pageSize,
+		},
+	}
+	viewer, err := getViewer(ctx, s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.tagRepository.GetTagByName(ctx, strings.TrimSpace(req.TagName))
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	if tag == nil {
+		return response, nil
+	}
+	articles, total, err := s.tagRepository.GetArticleListByTag(ctx, viewer, tag.Id, pageIndex, pageSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	articleList, err := s.buildArticleDataList(ctx, viewer, articles)
+	if err != nil {
+		return nil, err
+	}
+	response.ArticleDataList = append(response.ArticleDataList, articleList...)
+	response.TotalCount = total
+	return response, nil
+}
@@ -0,0 +1,108 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]string, 0, maxArticleTags+1)
+	for i := 0; i <= maxArticleTags; i++ {
+		tooMany = append(tooMany, string(rune('a'+i)))
+	}
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr error
+	}{
+		{"nil", nil, []string{}, nil},
+		{"trim, skip empty and duplicates", []string{" go ", "", "db", "go", "  "}, []string{"go", "db"}, nil},
+		{"max length in runes", []string{strings.Repeat("标", maxTagLength)}, []string{strings.Repeat("标", maxTagLength)}, nil},
+		{"too long", []string{strings.Repeat("a", maxTagLength+1)}, nil, v1.ErrTagInvalid},
+		{"too many", tooMany, nil, v1.ErrTagInvalid},
+		{"duplicates do not count", append(tooMany[:maxArticleTags:maxArticleTags], "a", " b"), tooMany[:maxArticleTags], nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.tags)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t, nil)
+	e.createUser(t, "author", enums.COMMON_USER, 1)
+	e.createUser(t, "reader", enums.COMMON_USER, 2)
+	categoryId := e.createCategory(t, "c", 0)
+	create := func(title string, scope string, tags ...string) uint {
+		id, err := e.CreateArticle(ctx, &v1.CreateArticleRequest{
+			Title:        title,
+			Content:      "content",
+			AuthorID:     "author",
+			CategoryID:   categoryId,
+			VisibleRange: v1.Visibility{Scope: scope},
+			Tags:         tags,
+		})
+		require.NoError(t, err)
+		return uint(id)
+	}
+	public := create("public", enums.VisiblePublic, "golang", "go")
+	private := create("private", enums.VisiblePrivate, "golang")
+	create("other", enums.VisiblePublic, "go_test")
+
+	t.Run("suggest", func(t *testing.T) {
+		tests := []struct {
+			keyword string
+			want    []string
+		}{
+			{"", nil},
+			{" go", []string{"golang:2", "go:1", "go_test:1"}},
+			{"x", nil},
+		}
+		for _, tt := range tests {
+			tags, err := e.GetTagSuggest(ctx, tt.keyword)
+			require.NoError(t, err)
+			var got []string
+			for _, tag := range tags {
+				got = append(got, fmt.Sprintf("%s:%d", tag.TagName, tag.ArticleCount))
+			}
+			assert.Equal(t, tt.want, got, tt.keyword)
+		}
+	})
+
+	t.Run("article list by tag", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			userId string
+			tag    string
+			want   []uint
+		}{
+			{"private article hidden", "reader", "golang", []uint{public}},
+			{"author sees private", "author", "golang", []uint{public, private}},
+			{"unknown tag", "reader", "rust", nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				list, err := e.GetArticleListByTag(ctx, tt.userId, &v1.GetArticleListByTagReq{TagName: tt.tag})
+				require.NoError(t, err)
+				var got []uint
+				for _, article := range list.ArticleDataList {
+					got = append(got, article.ArticleID)
+				}
+				assert.ElementsMatch(t, tt.want, got)
+				assert.Equal(t, int64(len(tt.want)), list.TotalCount)
+			})
+		}
+	})
+}
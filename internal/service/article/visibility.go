@@ -0,0 +1,144 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"github.com/olivere/elastic/v7"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"strings"
+)
+
+const (
+	esVisibilityField = "visibility"
+	esMinRoleField    = "min_role"
+	esCollegeIdField  = "college_id"
+	esUserIdField     = "user_id"
+
+	esSharedUserIdsField    = "shared_user_ids"
+	esSharedCollegeIdsField = "shared_college_ids"
+)
+
+// normalizeVisibility 校验并整理请求中的可见范围，去除共享列表中的空值和重复项
+// required 为 false 时允许不设置可见类型（草稿）
+func normalizeVisibility(req v1.Visibility, required bool) (model.Visibility, error) {
+	if req.Scope == "" {
+		if required {
+			return model.Visibility{}, v1.ErrVisibilityInvalid
+		}
+	} else if !model.IsVisibleScope(req.Scope) {
+		return model.Visibility{}, v1.ErrVisibilityInvalid
+	}
+	visibility := model.Visibility{Scope: req.Scope}
+	if req.Scope == enums.VisibleRole {
+		if req.MinRole < enums.COMMON_USER || req.MinRole > enums.SUPER_ADMIN {
+			return model.Visibility{}, v1.ErrVisibilityInvalid
+		}
+		visibility.MinRole = req.MinRole
+	}
+	seenUsers := make(map[string]bool, len(req.SharedUserIds))
+	for _, userId := range req.SharedUserIds {
+		userId = strings.TrimSpace(userId)
+		if userId == "" || seenUsers[userId] {
+			continue
+		}
+		seenUsers[userId] = true
+		visibility.SharedUserIds = append(visibility.SharedUserIds, userId)
+	}
+	seenColleges := make(map[uint]bool, len(req.SharedCollegeIds))
+	for _, collegeId := range req.SharedCollegeIds {
+		if collegeId == 0 || seenColleges[collegeId] {
+			continue
+		}
+		seenColleges[collegeId] = true
+		visibility.SharedCollegeIds = append(visibility.SharedCollegeIds, collegeId)
+	}
+	return visibility, nil
+}
+
+func buildVisibilityData(visibility model.Visibility) v1.Visibility {
+	return v1.Visibility{
+		Scope:            visibility.Scope,
+		MinRole:          visibility.MinRole,
+		SharedUserIds:    visibility.SharedUserIds,
+		SharedCollegeIds: visibility.SharedCollegeIds,
+	}
+}
+
+// checkArticleVisible 按文章的可见范围校验用户能否查看，作者本人始终可见
+func checkArticleVisible(ctx context.Context, userRepo repository.UserRepository, userId string, article *model.Article) error {
+	if userId == article.UserID {
+		return nil
+	}
+	viewer, err := getViewer(ctx, userRepo, userId)
+	if err != nil {
+		return err
+	}
+	return checkArticleVisibleTo(ctx, userRepo, viewer, article)
+}
+
+// checkArticleVisibleTo 与 checkArticleVisible 相同，用于已查询出当前用户的场景
+func checkArticleVisibleTo(ctx context.Context, userRepo repository.UserRepository, viewer *model.User, article *model.Article) error {
+	if viewer.UserId == article.UserID {
+		return nil
+	}
+	visibility := article.Visibility()
+	var authorCollegeId uint
+	if visibility.Scope == enums.VisibleCollege {
+		author, err := getViewer(ctx, userRepo, article.UserID)
+		if err != nil {
+			return err
+		}
+		authorCollegeId = author.CollegeId
+	}
+	if !visibility.CanView(viewer, article.UserID, authorCollegeId) {
+		return v1.ErrPermissionDenied
+	}
+	return nil
+}
+
+func getViewer(ctx context.Context, userRepo repository.UserRepository, userId string) (*model.User, error) {
+	user, err := userRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrPermissionDenied
+		}
+		return nil, v1.ErrQueryFailed
+	}
+	return user, nil
+}
+
+// buildVisibilityQuery 构建es中当前用户可见文章的过滤条件，与 model.Visibility.CanView 的规则保持一致
+func buildVisibilityQuery(viewer *model.User) elastic.Query {
+	return buildScopeVisibilityQuery(viewer).
+		Should(elastic.NewTermQuery(esUserIdField, viewer.UserId)).
+		Should(elastic.NewTermQuery(esSharedUserIdsField, viewer.UserId))
+}
+
+// buildScopeVisibilityQuery 按可见类型判断的部分，不包含作者本人和共享给该用户的文章，结果只取决于用户的角色和学院
+func buildScopeVisibilityQuery(viewer *model.User) *elastic.BoolQuery {
+	query := elastic.NewBoolQuery().
+		Should(elastic.NewTermsQuery(esVisibilityField, enums.VisiblePublic, enums.VisibleLogin)).
+		// 早期写入的文档没有可见类型字段，当时只有公开文章会写入es
+		Should(elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery(esVisibilityField))).
+		Should(elastic.NewBoolQuery().
+			Filter(elastic.NewTermQuery(esVisibilityField, enums.VisibleRole)).
+			Filter(elastic.NewRangeQuery(esMinRoleField).Lte(viewer.RoleType)))
+	if viewer.CollegeId != 0 {
+		query = query.Should(elastic.NewBoolQuery().
+			Filter(elastic.NewTermQuery(esVisibilityField, enums.VisibleCollege)).
+			Filter(elastic.NewTermQuery(esCollegeIdField, viewer.CollegeId))).
+			Should(elastic.NewTermQuery(esSharedCollegeIdsField, viewer.CollegeId))
+	}
+	return query.MinimumNumberShouldMatch(1)
+}
+
+// buildUserVisibilityQuery 作者本人和共享给该用户的文章
+func buildUserVisibilityQuery(viewer *model.User) *elastic.BoolQuery {
+	return elastic.NewBoolQuery().
+		Should(elastic.NewTermQuery(esUserIdField, viewer.UserId)).
+		Should(elastic.NewTermQuery(esSharedUserIdsField, viewer.UserId)).
+		MinimumNumberShouldMatch(1)
+}
@@ -0,0 +1,201 @@
+package article
+
+import (
+	"encoding/json"
+	"fmt"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeVisibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      v1.Visibility
+		required bool
+		want     model.Visibility
+		wantErr  error
+	}{
+		{"empty scope for draft", v1.Visibility{}, false, model.Visibility{}, nil},
+		{"empty scope required", v1.Visibility{}, true, model.Visibility{}, v1.ErrVisibilityInvalid},
+		{"unknown scope", v1.Visibility{Scope: "friends"}, false, model.Visibility{}, v1.ErrVisibilityInvalid},
+		{"public", v1.Visibility{Scope: enums.VisiblePublic}, true, model.Visibility{Scope: enums.VisiblePublic}, nil},
+		{"min role ignored outside role scope", v1.Visibility{Scope: enums.VisibleCollege, MinRole: 2}, true, model.Visibility{Scope: enums.VisibleCollege}, nil},
+		{"role", v1.Visibility{Scope: enums.VisibleRole, MinRole: enums.SCHOOL_ADMIN}, true, model.Visibility{Scope: enums.VisibleRole, MinRole: enums.SCHOOL_ADMIN}, nil},
+		{"role below range", v1.Visibility{Scope: enums.VisibleRole, MinRole: -1}, true, model.Visibility{}, v1.ErrVisibilityInvalid},
+		{"role above range", v1.Visibility{Scope: enums.VisibleRole, MinRole: enums.SUPER_ADMIN + 1}, true, model.Visibility{}, v1.ErrVisibilityInvalid},
+		{
+			"shared lists trimmed and deduplicated",
+			v1.Visibility{Scope: enums.VisiblePrivate, SharedUserIds: []string{" a ", "", "b", "a", "  "}, SharedCollegeIds: []uint{0, 2, 1, 2}},
+			true,
+			model.Visibility{Scope: enums.VisiblePrivate, SharedUserIds: []string{"a", "b"}, SharedCollegeIds: []uint{2, 1}},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeVisibility(tt.req, tt.required)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestBuildVisibilityQuery 在内存中按 es 的语义执行过滤条件，结果须与 CanView 一致
+func TestBuildVisibilityQuery(t *testing.T) {
+	const authorId = "author"
+	viewers := []*model.User{
+		{UserId: authorId, RoleType: enums.COMMON_USER, CollegeId: 1},
+		{UserId: "student", RoleType: enums.SUTDENT_USER, CollegeId: 1},
+		{UserId: "other", RoleType: enums.SUTDENT_USER, CollegeId: 2},
+		{UserId: "nocollege", RoleType: enums.COMMON_USER},
+		{UserId: "admin", RoleType: enums.SCHOOL_ADMIN, CollegeId: 2},
+		{UserId: "super", RoleType: enums.SUPER_ADMIN},
+	}
+	var visibilities []model.Visibility
+	for _, scope := range []string{enums.VisiblePublic, enums.VisibleLogin, enums.VisibleCollege, enums.VisiblePrivate} {
+		visibilities = append(visibilities,
+			model.Visibility{Scope: scope},
+			model.Visibility{Scope: scope, SharedUserIds: []string{"student", "nocollege"}},
+			model.Visibility{Scope: scope, SharedCollegeIds: []uint{2}},
+		)
+	}
+	for role := enums.COMMON_USER; role <= enums.SUPER_ADMIN; role++ {
+		visibilities = append(visibilities, model.Visibility{Scope: enums.VisibleRole, MinRole: role})
+	}
+
+	for _, authorCollegeId := range []uint{0, 1} {
+		for _, visibility := range visibilities {
+			doc := map[string]interface{}{
+				esUserIdField:           authorId,
+				esCollegeIdField:        authorCollegeId,
+				esVisibilityField:       visibility.Scope,
+				esMinRoleField:          visibility.MinRole,
+				esSharedUserIdsField:    visibility.SharedUserIds,
+				esSharedCollegeIdsField: visibility.SharedCollegeIds,
+			}
+			for _, viewer := range viewers {
+				name := fmt.Sprintf("college %d/%+v/%s", authorCollegeId, visibility, viewer.UserId)
+				t.Run(name, func(t *testing.T) {
+					src, err := buildVisibilityQuery(viewer).Source()
+					require.NoError(t, err)
+					want := visibility.CanView(viewer, authorId, authorCollegeId)
+					assert.Equal(t, want, matchQuery(t, toJson(t, src), toJson(t, doc)))
+				})
+			}
+		}
+	}
+}
+
+func TestBuildScopeVisibilityQuery_LegacyDocument(t *testing.T) {
+	viewer := &model.User{UserId: "student", RoleType: enums.SUTDENT_USER, CollegeId: 1}
+	src, err := buildScopeVisibilityQuery(viewer).Source()
+	require.NoError(t, err)
+	// 早期写入的文档没有可见类型字段，视为公开
+	assert.True(t, matchQuery(t, toJson(t, src), toJson(t, map[string]interface{}{esUserIdField: "author"})))
+}
+
+func toJson(t *testing.T, v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &m))
+	return m
+}
+
+// matchQuery 判断文档是否满足查询，只支持 buildVisibilityQuery 用到的查询类型
+func matchQuery(t *testing.T, query, doc map[string]interface{}) bool {
+	require.Len(t, query, 1)
+	for kind, body := range query {
+		params := body.(map[string]interface{})
+		switch kind {
+		case "bool":
+			return matchBool(t, params, doc)
+		case "term":
+			for field, value := range params {
+				return fieldValues(doc, field)[fmt.Sprint(value)]
+			}
+		case "terms":
+			for field, values := range params {
+				docValues := fieldValues(doc, field)
+				for _, value := range values.([]interface{}) {
+					if docValues[fmt.Sprint(value)] {
+						return true
+					}
+				}
+				return false
+			}
+		case "exists":
+			return len(fieldValues(doc, params["field"].(string))) > 0
+		case "range":
+			for field, bounds := range params {
+				value, ok := doc[field].(float64)
+				if !ok {
+					return false
+				}
+				to := bounds.(map[string]interface{})["to"]
+				require.Equal(t, true, bounds.(map[string]interface{})["include_upper"])
+				return to == nil || value <= to.(float64)
+			}
+		}
+		t.Fatalf("unsupported query %s", kind)
+	}
+	return false
+}
+
+func matchBool(t *testing.T, params, doc map[string]interface{}) bool {
+	for _, q := range clauses(params["filter"]) {
+		if !matchQuery(t, q, doc) {
+			return false
+		}
+	}
+	for _, q := range clauses(params["must_not"]) {
+		if matchQuery(t, q, doc) {
+			return false
+		}
+	}
+	should := clauses(params["should"])
+	if len(should) == 0 {
+		return true
+	}
+	for _, q := range should {
+		if matchQuery(t, q, doc) {
+			return true
+		}
+	}
+	return false
+}
+
+// clauses 只有一个子查询时 Source 不会输出数组
+func clauses(v interface{}) []map[string]interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(v))
+		for _, q := range v {
+			result = append(result, q.(map[string]interface{}))
+		}
+		return result
+	}
+	return nil
+}
+
+// fieldValues 文档字段的取值集合，数组字段按多值处理
+func fieldValues(doc map[string]interface{}, field string) map[string]bool {
+	values := make(map[string]bool)
+	switch v := doc[field].(type) {
+	case nil:
+	case []interface{}:
+		for _, item := range v {
+			values[fmt.Sprint(item)] = true
+		}
+	default:
+		values[fmt.Sprint(v)] = true
+	}
+	return values
+}
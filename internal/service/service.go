@@ -1,6 +1,10 @@
 package service
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	v1 "projectName/api/v1"
 	"projectName/internal/repository"
 	"projectName/pkg/jwt"
 	"projectName/pkg/log"
@@ -27,3 +31,41 @@ func NewService(
 		Tm:     tm,
 	}
 }
+
+// InitPage page初始化
+func InitPage(pageIndex int, pageSize int) (int, int) {
+	if pageIndex < 1 {
+		pageIndex = 1
+	}
+	if pageSize < 10 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return pageIndex, pageSize
+}
+
+// EncodeCursor 将最后一条记录的排序值编码为不透明的分页游标
+func EncodeCursor(values ...interface{}) string {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解析分页游标中的排序值，数字保留为 json.Number
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, v1.ErrCursorInvalid
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var values []interface{}
+	if err = decoder.Decode(&values); err != nil || len(values) == 0 {
+		return nil, v1.ErrCursorInvalid
+	}
+	return values, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	v1 "projectName/api/v1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		want   []interface{}
+	}{
+		{"id", []interface{}{uint(42)}, []interface{}{json.Number("42")}},
+		{"score and id", []interface{}{1.5, uint(7)}, []interface{}{json.Number("1.5"), json.Number("7")}},
+		{"large id keeps precision", []interface{}{uint64(1<<63 + 1)}, []interface{}{json.Number("9223372036854775809")}},
+		{"string", []interface{}{"2024-01-02 03:04:05", 3}, []interface{}{"2024-01-02 03:04:05", json.Number("3")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(EncodeCursor(tt.values...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"not json", encode("abc")},
+		{"not array", encode(`{"id":1}`)},
+		{"empty array", encode("[]")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.cursor)
+			assert.Equal(t, v1.ErrCursorInvalid, err)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package user
+
+import (
+	"context"
+	"go.uber.org/zap"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/repository"
+	"projectName/internal/service"
+	"projectName/pkg/utils"
+)
+
+type AdminService interface {
+	GetUserList(ctx context.Context, req *v1.GetUserListReq) (*v1.UserManageList, error)
+	UpdateUserRole(ctx context.Context, adminId string, req *v1.UpdateUserRoleReq) error
+	SetUserDisabled(ctx context.Context, adminId string, req *v1.SetUserDisabledReq) error
+	ForceLogout(ctx context.Context, userId string) error
+	RestoreUser(ctx context.Context, userId string) error
+}
+
+func NewAdminService(
+	service *service.Service,
+	userRepo repository.UserRepository,
+	collegeRepository repository.CollegeRepository,
+	userService UserService,
+) AdminService {
+	return &adminService{
+		Service:           service,
+		userRepo:          userRepo,
+		collegeRepository: collegeRepository,
+		userService:       userService,
+	}
+}
+
+type adminService struct {
+	*service.Service
+	userRepo          repository.UserRepository
+	collegeRepository repository.CollegeRepository
+	userService       UserService
+}
+
+func (s *adminService) GetUserList(ctx context.Context, req *v1.GetUserListReq) (*v1.UserManageList, error) {
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	users, total, err := s.userRepo.GetUserList(ctx, req, pageIndex, pageSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	var userList []*v1.UserManageData
+	for _, user := range users {
+		userList = append(userList, &v1.UserManageData{
+			UserId:     user.UserId,
+			Phone:      user.Phone,
+			Nickname:   user.Nickname,
+			RoleType:   user.RoleType,
+			Email:      user.Email,
+			CollegeId:  user.CollegeId,
+			StudentId:  user.StudentId,
+			IsDisabled: user.IsDisabled,
+			IsDeleted:  user.IsDeleted,
+			CreatedAt:  utils.TimeFormat(user.CreatedAt, utils.FormatDateTime),
+		})
+	}
+	return &v1.UserManageList{
+		UserList: userList,
+		PageResponse: v1.PageResponse{
+			TotalCount: total,
+			PageIndex:  pageIndex,
+			PageSize:   pageSize,
+		},
+	}, nil
+}
+
+// UpdateUserRole 修改用户角色，任命学校管理员时必须绑定学院
+func (s *adminService) UpdateUserRole(ctx context.Context, adminId string, req *v1.UpdateUserRoleReq) error {
+	if req.UserId == adminId {
+		return v1.ErrOperateSelf
+	}
+	if req.RoleType < enums.COMMON_USER || req.RoleType > enums.SUPER_ADMIN {
+		return v1.ErrRoleTypeInvalid
+	}
+	user, err := s.userRepo.GetByUserId(ctx, req.UserId)
+	if err != nil {
+		return v1.ErrUserNotExist
+	}
+	if req.RoleType == enums.SCHOOL_ADMIN {
+		if req.CollegeId == 0 {
+			return v1.ErrCollegeRequired
+		}
+		if _, err = s.collegeRepository.GetCollegeByCollegeId(ctx, int64(req.CollegeId)); err != nil {
+			return v1.ErrNotFound
+		}
+	}
+	if req.CollegeId != 0 {
+		user.CollegeId = req.CollegeId
+	}
+	user.RoleType = req.RoleType
+	if err = s.userRepo.Update(ctx, user); err != nil {
+		return v1.ErrUpdateFailed
+	}
+	// 旧角色的访问 token 失效，客户端通过刷新 token 获取新角色的 token
+	if err = s.userRepo.DeleteByPrefix(ctx, repository.LoginTokenUserPrefix(user.UserId)); err != nil {
+		s.Logger.Error("adminService.UpdateUserRole revoke token error", zap.Error(err))
+	}
+	return nil
+}
+
+// SetUserDisabled 禁用或启用用户，禁用时强制下线
+func (s *adminService) SetUserDisabled(ctx context.Context, adminId string, req *v1.SetUserDisabledReq) error {
+	if req.UserId == adminId {
+		return v1.ErrOperateSelf
+	}
+	user, err := s.userRepo.GetByUserId(ctx, req.UserId)
+	if err != nil {
+		return v1.ErrUserNotExist
+	}
+	if req.Disabled {
+		user.IsDisabled = 1
+	} else {
+		user.IsDisabled = 0
+	}
+	if err = s.userRepo.Update(ctx, user); err != nil {
+		return v1.ErrUpdateFailed
+	}
+	if req.Disabled {
+		return s.userService.LogoutAll(ctx, user.UserId)
+	}
+	return nil
+}
+
+// ForceLogout 强制用户所有设备下线
+func (s *adminService) ForceLogout(ctx context.Context, userId string) error {
+	if _, err := s.userRepo.GetByUserIdUnscoped(ctx, userId); err != nil {
+		return v1.ErrUserNotExist
+	}
+	return s.userService.LogoutAll(ctx, userId)
+}
+
+// RestoreUser 恢复已注销的用户
+func (s *adminService) RestoreUser(ctx context.Context, userId string) error {
+	user, err := s.userRepo.GetByUserIdUnscoped(ctx, userId)
+	if err != nil {
+		return v1.ErrUserNotExist
+	}
+	if user.IsDeleted == 0 && !user.DeletedAt.Valid {
+		return v1.ErrUserNotDeleted
+	}
+	// 注销后手机号可能已被重新注册
+	existing, err := s.userRepo.GetByPhone(ctx, user.Phone)
+	if err != nil {
+		return v1.ErrDatabase
+	}
+	if existing != nil && existing.UserId != user.UserId {
+		return v1.ErrPhoneAlreadyUse
+	}
+	if err = s.userRepo.RestoreByUserId(ctx, userId); err != nil {
+		return v1.ErrUpdateFailed
+	}
+	return nil
+}
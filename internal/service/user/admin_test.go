@@ -0,0 +1,186 @@
+package user
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/internal/service"
+	"projectName/pkg/log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type testAdminEnv struct {
+	AdminService
+	db       *gorm.DB
+	userRepo repository.UserRepository
+}
+
+func newTestAdminService(t *testing.T) *testAdminEnv {
+	repo, db := newTestRepository(t)
+	l := &log.Logger{Logger: zap.NewNop()}
+	userRepo := repository.NewUserRepository(repo)
+	svc := NewAdminService(service.NewService(repository.NewTransaction(repo), l, nil, nil), userRepo, repository.NewCollegeRepository(repo), newUserService(repo))
+	require.NoError(t, db.Create(&model.College{CollegeId: 1, CollegeName: "college"}).Error)
+	return &testAdminEnv{AdminService: svc, db: db, userRepo: userRepo}
+}
+
+// hasSession 用户是否还有登录会话
+func (e *testAdminEnv) hasSession(userId string, roleType int) bool {
+	_, err := e.userRepo.Get(context.Background(), repository.LoginTokenKey(userId, roleType, "t1"))
+	return err == nil
+}
+
+func (e *testAdminEnv) login(t *testing.T, userId string, roleType int) {
+	require.NoError(t, e.userRepo.Set(context.Background(), repository.LoginTokenKey(userId, roleType, "t1"), "token", time.Hour))
+}
+
+func (e *testAdminEnv) user(t *testing.T, userId string) model.User {
+	var user model.User
+	require.NoError(t, e.db.Unscoped().Where("user_id = ?", userId).First(&user).Error)
+	return user
+}
+
+func TestGetUserList(t *testing.T) {
+	ctx := context.Background()
+	e := newTestAdminService(t)
+	createUser(t, e.db, "alice", enums.COMMON_USER, 0)
+	createUser(t, e.db, "bob", enums.SUTDENT_USER, 1)
+	createUser(t, e.db, "carol", enums.SUTDENT_USER, 2)
+	require.NoError(t, e.db.Model(&model.User{}).Where("user_id = ?", "carol").Update("is_disabled", 1).Error)
+	require.NoError(t, e.userRepo.DeleteByUserId(ctx, "alice"))
+
+	intPtr := func(i int) *int { return &i }
+	tests := []struct {
+		name string
+		req  v1.GetUserListReq
+		want []string
+	}{
+		{"all including deleted", v1.GetUserListReq{}, []string{"alice", "bob", "carol"}},
+		{"keyword", v1.GetUserListReq{Keyword: "nick-b"}, []string{"bob"}},
+		{"keyword with other filters", v1.GetUserListReq{Keyword: "o", RoleType: intPtr(enums.SUTDENT_USER)}, []string{"bob", "carol"}},
+		{"role", v1.GetUserListReq{RoleType: intPtr(enums.SUTDENT_USER)}, []string{"bob", "carol"}},
+		{"role -1 means all", v1.GetUserListReq{RoleType: intPtr(-1)}, []string{"alice", "bob", "carol"}},
+		{"college", v1.GetUserListReq{CollegeId: 2}, []string{"carol"}},
+		{"disabled", v1.GetUserListReq{IsDisabled: intPtr(1)}, []string{"carol"}},
+		{"deleted", v1.GetUserListReq{IsDeleted: intPtr(1)}, []string{"alice"}},
+		{"not deleted", v1.GetUserListReq{IsDeleted: intPtr(0)}, []string{"bob", "carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := e.GetUserList(ctx, &tt.req)
+			require.NoError(t, err)
+			var got []string
+			for _, user := range list.UserList {
+				got = append(got, user.UserId)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+			assert.Equal(t, int64(len(tt.want)), list.TotalCount)
+		})
+	}
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	ctx := context.Background()
+	e := newTestAdminService(t)
+	createUser(t, e.db, "super", enums.SUPER_ADMIN, 0)
+	createUser(t, e.db, "u1", enums.COMMON_USER, 0)
+
+	tests := []struct {
+		name      string
+		req       v1.UpdateUserRoleReq
+		wantErr   error
+		wantRole  int
+		wantColl  uint
+		revokeOld bool
+	}{
+		{"self", v1.UpdateUserRoleReq{UserId: "super", RoleType: enums.COMMON_USER}, v1.ErrOperateSelf, enums.COMMON_USER, 0, false},
+		{"invalid role", v1.UpdateUserRoleReq{UserId: "u1", RoleType: enums.SUPER_ADMIN + 1}, v1.ErrRoleTypeInvalid, enums.COMMON_USER, 0, false},
+		{"user not exist", v1.UpdateUserRoleReq{UserId: "gone", RoleType: enums.SUTDENT_USER}, v1.ErrUserNotExist, enums.COMMON_USER, 0, false},
+		{"school admin without college", v1.UpdateUserRoleReq{UserId: "u1", RoleType: enums.SCHOOL_ADMIN}, v1.ErrCollegeRequired, enums.COMMON_USER, 0, false},
+		{"school admin of unknown college", v1.UpdateUserRoleReq{UserId: "u1", RoleType: enums.SCHOOL_ADMIN, CollegeId: 9}, v1.ErrNotFound, enums.COMMON_USER, 0, false},
+		{"school admin", v1.UpdateUserRoleReq{UserId: "u1", RoleType: enums.SCHOOL_ADMIN, CollegeId: 1}, nil, enums.SCHOOL_ADMIN, 1, true},
+		{"keeps college when not given", v1.UpdateUserRoleReq{UserId: "u1", RoleType: enums.SUTDENT_USER}, nil, enums.SUTDENT_USER, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := e.user(t, "u1")
+			e.login(t, "u1", before.RoleType)
+			err := e.UpdateUserRole(ctx, "super", &tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			user := e.user(t, "u1")
+			assert.Equal(t, tt.wantRole, user.RoleType)
+			assert.Equal(t, tt.wantColl, user.CollegeId)
+			assert.Equal(t, !tt.revokeOld, e.hasSession("u1", before.RoleType))
+		})
+	}
+}
+
+func TestSetUserDisabled(t *testing.T) {
+	ctx := context.Background()
+	e := newTestAdminService(t)
+	createUser(t, e.db, "super", enums.SUPER_ADMIN, 0)
+	createUser(t, e.db, "u1", enums.COMMON_USER, 0)
+	e.login(t, "u1", enums.COMMON_USER)
+
+	assert.Equal(t, v1.ErrOperateSelf, e.SetUserDisabled(ctx, "super", &v1.SetUserDisabledReq{UserId: "super", Disabled: true}))
+	assert.Equal(t, v1.ErrUserNotExist, e.SetUserDisabled(ctx, "super", &v1.SetUserDisabledReq{UserId: "gone", Disabled: true}))
+
+	require.NoError(t, e.SetUserDisabled(ctx, "super", &v1.SetUserDisabledReq{UserId: "u1", Disabled: true}))
+	assert.Equal(t, 1, e.user(t, "u1").IsDisabled)
+	assert.False(t, e.hasSession("u1", enums.COMMON_USER))
+
+	require.NoError(t, e.SetUserDisabled(ctx, "super", &v1.SetUserDisabledReq{UserId: "u1", Disabled: false}))
+	assert.Equal(t, 0, e.user(t, "u1").IsDisabled)
+}
+
+func TestForceLogout(t *testing.T) {
+	ctx := context.Background()
+	e := newTestAdminService(t)
+	createUser(t, e.db, "u1", enums.COMMON_USER, 0)
+	require.NoError(t, e.userRepo.DeleteByUserId(ctx, "u1"))
+	e.login(t, "u1", enums.COMMON_USER)
+
+	assert.Equal(t, v1.ErrUserNotExist, e.ForceLogout(ctx, "gone"))
+	// 已注销的用户也可以强制下线
+	require.NoError(t, e.ForceLogout(ctx, "u1"))
+	assert.False(t, e.hasSession("u1", enums.COMMON_USER))
+}
+
+func TestRestoreUser(t *testing.T) {
+	ctx := context.Background()
+	e := newTestAdminService(t)
+	createUser(t, e.db, "active", enums.COMMON_USER, 0)
+	createUser(t, e.db, "deleted", enums.COMMON_USER, 0)
+	createUser(t, e.db, "reused", enums.COMMON_USER, 0)
+	require.NoError(t, e.userRepo.DeleteByUserId(ctx, "deleted"))
+	require.NoError(t, e.userRepo.DeleteByUserId(ctx, "reused"))
+	// 注销后手机号被其他用户重新注册
+	require.NoError(t, e.db.Create(&model.User{UserId: "new", Phone: "reused", Nickname: "new"}).Error)
+
+	tests := []struct {
+		name    string
+		userId  string
+		wantErr error
+	}{
+		{"not exist", "gone", v1.ErrUserNotExist},
+		{"not deleted", "active", v1.ErrUserNotDeleted},
+		{"phone reused", "reused", v1.ErrPhoneAlreadyUse},
+		{"restore", "deleted", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, e.RestoreUser(ctx, tt.userId))
+		})
+	}
+	user := e.user(t, "deleted")
+	assert.Equal(t, 0, user.IsDeleted)
+	assert.False(t, user.DeletedAt.Valid)
+}
@@ -0,0 +1,69 @@
+package user
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/repository"
+	"projectName/internal/service"
+	"projectName/pkg/utils"
+)
+
+type NotificationService interface {
+	GetNotificationList(ctx context.Context, userId string, req *v1.PageRequest) (*v1.NotificationList, error)
+	ReadNotification(ctx context.Context, userId string, req *v1.ReadNotificationReq) error
+}
+
+func NewNotificationService(
+	service *service.Service,
+	notificationRepository repository.NotificationRepository,
+) NotificationService {
+	return &notificationService{
+		Service:                service,
+		notificationRepository: notificationRepository,
+	}
+}
+
+type notificationService struct {
+	*service.Service
+	notificationRepository repository.NotificationRepository
+}
+
+func (s *notificationService) GetNotificationList(ctx context.Context, userId string, req *v1.PageRequest) (*v1.NotificationList, error) {
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	notifications, total, err := s.notificationRepository.GetNotificationList(ctx, userId, pageIndex, pageSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	unreadCount, err := s.notificationRepository.CountUnread(ctx, userId)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	var list []*v1.NotificationData
+	for _, notification := range notifications {
+		list = append(list, &v1.NotificationData{
+			Id:        notification.Id,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Content:   notification.Content,
+			RelatedId: notification.RelatedId,
+			IsRead:    notification.IsRead,
+			CreatedAt: utils.TimeFormat(notification.CreatedAt, utils.FormatDateTime),
+		})
+	}
+	return &v1.NotificationList{
+		NotificationList: list,
+		UnreadCount:      unreadCount,
+		PageResponse: v1.PageResponse{
+			TotalCount: total,
+			PageIndex:  pageIndex,
+			PageSize:   pageSize,
+		},
+	}, nil
+}
+
+func (s *notificationService) ReadNotification(ctx context.Context, userId string, req *v1.ReadNotificationReq) error {
+	if err := s.notificationRepository.MarkRead(ctx, userId, req.Ids); err != nil {
+		return v1.ErrUpdateFailed
+	}
+	return nil
+}
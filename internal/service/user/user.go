@@ -2,7 +2,6 @@ package user
 
 import (
 	"context"
-	"fmt"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	v1 "projectName/api/v1"
@@ -10,6 +9,7 @@ import (
 	"projectName/internal/model"
 	"projectName/internal/repository"
 	"projectName/internal/service"
+	"projectName/pkg/jwt"
 	"projectName/pkg/utils"
 	"regexp"
 	"strconv"
@@ -20,12 +20,16 @@ import (
 
 type UserService interface {
 	Register(ctx context.Context, req *v1.RegisterRequest) error
-	PasswordLogin(ctx context.Context, req *v1.PasswordLoginRequest) (string, error)
+	PasswordLogin(ctx context.Context, req *v1.PasswordLoginRequest) (*v1.LoginResponseData, error)
+	RefreshToken(ctx context.Context, req *v1.RefreshTokenRequest) (*v1.LoginResponseData, error)
 	GetUserInfo(ctx context.Context, userId string) (*v1.GetUserInfoResponseData, error)
 	UpdateProfile(ctx context.Context, userId string, req *v1.UpdateProfileRequest) error
-	Logout(ctx context.Context, userId string, roleType int) error
+	Logout(ctx context.Context, userId string, roleType int, tokenId string) error
+	LogoutAll(ctx context.Context, userId string) error
 	Cancel(ctx context.Context, userId string) error
 	UserAuth(ctx context.Context, req *v1.UserAuthRequest, userId string, roleType int) error
+	GetUserAuthList(ctx context.Context, adminId string, adminRole int, req *v1.GetUserAuthListReq) (*v1.UserAuthList, error)
+	ReviewUserAuth(ctx context.Context, adminId string, adminRole int, req *v1.ReviewUserAuthReq) error
 }
 
 func NewUserService(
@@ -96,40 +100,112 @@ func (s *userService) Register(ctx context.Context, req *v1.RegisterRequest) err
 	return err
 }
 
-func (s *userService) PasswordLogin(ctx context.Context, req *v1.PasswordLoginRequest) (string, error) {
+func (s *userService) PasswordLogin(ctx context.Context, req *v1.PasswordLoginRequest) (*v1.LoginResponseData, error) {
 	// 校验参数
 	if !utils.IsPhoneNumber(req.Phone) {
-		return "", v1.ErrPhoneFormat
+		return nil, v1.ErrPhoneFormat
 	}
 	if !s.captchaService.VerifyCaptcha(req.CaptchaId, req.CaptchaAnswer) {
-		return "", v1.ErrInvalidCaptcha // 如果验证码验证失败，返回错误
+		return nil, v1.ErrInvalidCaptcha // 如果验证码验证失败，返回错误
 	}
 	user, err := s.userRepo.GetByPhone(ctx, req.Phone)
 	if err != nil || user == nil {
-		return "", v1.ErrUserNotExist
+		return nil, v1.ErrUserNotExist
 	}
 	// 校验密码
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return "", v1.ErrDecryptPassword
+		return nil, v1.ErrDecryptPassword
+	}
+	if user.IsDisabled == 1 {
+		return nil, v1.ErrUserDisabled
 	}
-	// 生成token 有效期7天
-	//todo: 后续可以改成后台可配置的天数
-	token, err := s.Jwt.GenToken(user.UserId, user.RoleType, time.Now().Add(time.Hour*24*7))
+	// 每次登录新建一个会话，tokenId 作为会话ID
+	tokenId, err := s.Sid.GenSonyflakeID()
 	if err != nil {
-		return "", v1.ErrGetTokenFail
+		return nil, v1.ErrGetTokenFail
 	}
-	// token存redis，后续注销和退出时候删除
-	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
-		// 将 token 存储到 Redis，设置过期时间为 7 天
-		key := fmt.Sprintf("%s%s:%d", enums.LOGIN_TOKEN_KEY, user.UserId, user.RoleType) // key="loginTokenKey:547519779070593342:0"
-		if err = s.userRepo.Set(ctx, key, token, time.Hour*24*7); err != nil {
-			return v1.ErrGetTokenFail // 存储失败
+	tokenIdStr := strconv.FormatInt(tokenId, 10)
+	refreshId, err := s.Sid.GenSonyflakeID()
+	if err != nil {
+		return nil, v1.ErrGetTokenFail
+	}
+	refreshIdStr := strconv.FormatInt(refreshId, 10)
+	// 刷新 token 存redis，值为当前有效的 refreshId，用于轮换和重复使用检测
+	// key="refreshToken:547519779070593342:547519779070593343"
+	refreshKey := repository.RefreshTokenKey(user.UserId, tokenIdStr)
+	if err = s.userRepo.Set(ctx, refreshKey, refreshIdStr, s.Jwt.RefreshExpire()); err != nil {
+		return nil, v1.ErrGetTokenFail // 存储失败
+	}
+	return s.issueTokens(ctx, user, tokenIdStr, refreshIdStr)
+}
+
+func (s *userService) RefreshToken(ctx context.Context, req *v1.RefreshTokenRequest) (*v1.LoginResponseData, error) {
+	claims, err := s.Jwt.ParseToken(req.RefreshToken)
+	if err != nil || claims.TokenType != jwt.TokenTypeRefresh || claims.ID == "" {
+		return nil, v1.ErrRefreshToken
+	}
+	refreshKey := repository.RefreshTokenKey(claims.UserId, claims.ID)
+	refreshId, err := s.Sid.GenSonyflakeID()
+	if err != nil {
+		return nil, v1.ErrGetTokenFail
+	}
+	refreshIdStr := strconv.FormatInt(refreshId, 10)
+	// 原子轮换 refreshId，只有持有当前 refreshId 的请求能成功
+	ok, err := s.userRepo.CompareAndSet(ctx, refreshKey, claims.RefreshId, refreshIdStr, s.Jwt.RefreshExpire())
+	if err != nil {
+		return nil, v1.ErrGetTokenFail
+	}
+	if !ok {
+		// 会话已失效，或旧的刷新 token 被重复使用
+		if _, err = s.userRepo.Get(ctx, refreshKey); err != nil {
+			return nil, v1.ErrRefreshToken
 		}
-		return nil
-	})
+		// 重复使用说明 token 可能已泄露，直接下线整个会话
+		s.Logger.Warn("userService.RefreshToken reuse detected", zap.String("userId", claims.UserId), zap.String("tokenId", claims.ID))
+		_ = s.userRepo.Delete(ctx, refreshKey)
+		_ = s.userRepo.Delete(ctx, repository.LoginTokenKey(claims.UserId, claims.RoleType, claims.ID))
+		return nil, v1.ErrRefreshReused
+	}
+	// 重新查询用户，保证角色变更后刷新得到的是最新角色
+	user, err := s.userRepo.GetByUserId(ctx, claims.UserId)
+	if err != nil || user.IsDeleted == 1 {
+		_ = s.userRepo.Delete(ctx, refreshKey)
+		return nil, v1.ErrUserNotExist
+	}
+	if user.IsDisabled == 1 {
+		_ = s.userRepo.Delete(ctx, refreshKey)
+		return nil, v1.ErrUserDisabled
+	}
+	// 旧角色对应的访问 token 立即失效
+	if user.RoleType != claims.RoleType {
+		_ = s.userRepo.Delete(ctx, repository.LoginTokenKey(claims.UserId, claims.RoleType, claims.ID))
+	}
+	return s.issueTokens(ctx, user, claims.ID, refreshIdStr)
+}
 
-	return token, nil
+// issueTokens 签发访问 token 和刷新 token，并保存访问 token 会话
+func (s *userService) issueTokens(ctx context.Context, user *model.User, tokenId string, refreshId string) (*v1.LoginResponseData, error) {
+	now := time.Now()
+	accessToken, err := s.Jwt.GenToken(user.UserId, user.RoleType, tokenId, now.Add(s.Jwt.AccessExpire()))
+	if err != nil {
+		return nil, v1.ErrGetTokenFail
+	}
+	refreshToken, err := s.Jwt.GenRefreshToken(user.UserId, user.RoleType, tokenId, refreshId, now.Add(s.Jwt.RefreshExpire()))
+	if err != nil {
+		return nil, v1.ErrGetTokenFail
+	}
+	// 访问 token 存redis，每个设备一个会话，后续注销和退出时候删除
+	// key="loginToken:547519779070593342:0:547519779070593343"
+	key := repository.LoginTokenKey(user.UserId, user.RoleType, tokenId)
+	if err = s.userRepo.Set(ctx, key, accessToken, s.Jwt.AccessExpire()); err != nil {
+		return nil, v1.ErrGetTokenFail // 存储失败
+	}
+	return &v1.LoginResponseData{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.Jwt.AccessExpire().Seconds()),
+	}, nil
 }
 
 func (s *userService) GetUserInfo(ctx context.Context, userId string) (*v1.GetUserInfoResponseData, error) {
@@ -181,13 +257,30 @@ func isValidPassword(password string) bool {
 	return matched
 }
 
-func (s *userService) Logout(ctx context.Context, userId string, roleType int) error {
-	// 从 Redis 中删除 token
-	key := fmt.Sprintf("%s%s:%d", enums.LOGIN_TOKEN_KEY, userId, roleType)
+func (s *userService) Logout(ctx context.Context, userId string, roleType int, tokenId string) error {
+	// 从 Redis 中删除当前设备的访问 token 和刷新 token
+	key := repository.LoginTokenKey(userId, roleType, tokenId)
 	if err := s.userRepo.Delete(ctx, key); err != nil {
 		s.Logger.Error("userService.Logout error", zap.Error(err))
 		return v1.ErrLogoutFail
 	}
+	if err := s.userRepo.Delete(ctx, repository.RefreshTokenKey(userId, tokenId)); err != nil {
+		s.Logger.Error("userService.Logout error", zap.Error(err))
+		return v1.ErrLogoutFail
+	}
+	return nil
+}
+
+// LogoutAll 退出所有设备，删除该用户的全部登录会话
+func (s *userService) LogoutAll(ctx context.Context, userId string) error {
+	if err := s.userRepo.DeleteByPrefix(ctx, repository.LoginTokenUserPrefix(userId)); err != nil {
+		s.Logger.Error("userService.LogoutAll error", zap.Error(err))
+		return v1.ErrLogoutFail
+	}
+	if err := s.userRepo.DeleteByPrefix(ctx, repository.RefreshTokenUserPrefix(userId)); err != nil {
+		s.Logger.Error("userService.LogoutAll error", zap.Error(err))
+		return v1.ErrLogoutFail
+	}
 	return nil
 }
 
@@ -231,9 +324,166 @@ func (s *userService) UserAuth(ctx context.Context, req *v1.UserAuthRequest, use
 		}
 	} else if existingAuthRequest.Status == enums.REJECTED || existingAuthRequest.Status == enums.FAILED {
 		// 认证请求状态是已拒绝或认证失败，更新认证请求信息到数据库
+		userAuth.Id = existingAuthRequest.Id
 		if err = s.userRepo.UpdateUserAuth(ctx, userAuth); err != nil {
 			return v1.ErrUserAuthFailed
 		}
 	}
 	return nil
 }
+
+// GetUserAuthList 学校管理员查看本学院的认证请求，超级管理员可查看全部
+func (s *userService) GetUserAuthList(ctx context.Context, adminId string, adminRole int, req *v1.GetUserAuthListReq) (*v1.UserAuthList, error) {
+	collegeId, err := s.getAdminCollegeId(ctx, adminId, adminRole)
+	if err != nil {
+		return nil, err
+	}
+	pageIndex, pageSize := service.InitPage(req.PageIndex, req.PageSize)
+	userAuthList, total, err := s.userRepo.GetUserAuthList(ctx, collegeId, req.Status, pageIndex, pageSize)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	nicknames, err := s.getNicknames(ctx, userAuthList)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*v1.UserAuthData
+	for _, userAuth := range userAuthList {
+		data := &v1.UserAuthData{
+			Id:          userAuth.Id,
+			UserId:      userAuth.UserId,
+			RequestType: userAuth.RequestType,
+			Status:      userAuth.Status,
+			CollegeId:   utils.DerefUint(userAuth.CollegeId),
+			StudentId:   utils.DerefString(userAuth.StudentId),
+			Remarks:     utils.DerefString(userAuth.Remarks),
+			ApplyTime:   utils.TimeFormat(userAuth.ApplyTime, utils.FormatDateTime),
+			AdminId:     utils.DerefString(userAuth.AdminId),
+			AdminRemark: utils.DerefString(userAuth.AdminRemark),
+		}
+		if userAuth.DisposeTime != nil {
+			data.DisposeTime = utils.TimeFormat(*userAuth.DisposeTime, utils.FormatDateTime)
+		}
+		data.Nickname = nicknames[userAuth.UserId]
+		list = append(list, data)
+	}
+	return &v1.UserAuthList{
+		UserAuthList: list,
+		PageResponse: v1.PageResponse{
+			TotalCount: total,
+			PageIndex:  pageIndex,
+			PageSize:   pageSize,
+		},
+	}, nil
+}
+
+// getNicknames 批量获取认证请求中用户的昵称
+func (s *userService) getNicknames(ctx context.Context, userAuthList []model.UserAuth) (map[string]string, error) {
+	userIds := make([]string, 0, len(userAuthList))
+	seen := make(map[string]bool, len(userAuthList))
+	for _, userAuth := range userAuthList {
+		if !seen[userAuth.UserId] {
+			seen[userAuth.UserId] = true
+			userIds = append(userIds, userAuth.UserId)
+		}
+	}
+	nicknames := make(map[string]string, len(userIds))
+	if len(userIds) == 0 {
+		return nicknames, nil
+	}
+	users, err := s.userRepo.GetByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, v1.ErrQueryFailed
+	}
+	for _, user := range users {
+		nicknames[user.UserId] = user.Nickname
+	}
+	return nicknames, nil
+}
+
+// ReviewUserAuth 审核认证请求，通过后将用户升级为学生用户
+func (s *userService) ReviewUserAuth(ctx context.Context, adminId string, adminRole int, req *v1.ReviewUserAuthReq) error {
+	collegeId, err := s.getAdminCollegeId(ctx, adminId, adminRole)
+	if err != nil {
+		return err
+	}
+	userAuth, err := s.userRepo.GetUserAuthById(ctx, req.Id)
+	if err != nil {
+		return v1.ErrUserAuthNotExist
+	}
+	// 学校管理员只能处理本学院的认证请求
+	if collegeId != 0 && utils.DerefUint(userAuth.CollegeId) != collegeId {
+		return v1.ErrPermissionDenied
+	}
+	if userAuth.Status != enums.WAITING {
+		return v1.ErrUserAuthProcessed
+	}
+
+	now := time.Now()
+	userAuth.AdminId = &adminId
+	userAuth.DisposeTime = &now
+	if utils.IsNotEmpty(req.Remark) {
+		userAuth.AdminRemark = &req.Remark
+	}
+	if req.Approved {
+		userAuth.Status = enums.APPROVED
+	} else {
+		userAuth.Status = enums.REJECTED
+	}
+
+	err = s.Tm.Transaction(ctx, func(ctx context.Context) error {
+		// 并发审核同一请求时只有一次能更新成功
+		ok, err := s.userRepo.DisposeUserAuth(ctx, userAuth)
+		if err != nil {
+			return v1.ErrUpdateFailed
+		}
+		if !ok {
+			return v1.ErrUserAuthProcessed
+		}
+		if !req.Approved {
+			return nil
+		}
+		user, err := s.userRepo.GetByUserId(ctx, userAuth.UserId)
+		if err != nil {
+			return v1.ErrUserNotExist
+		}
+		// 申请后已被设为学生或管理员的用户不再修改，避免降级
+		if user.RoleType >= enums.SUTDENT_USER {
+			return nil
+		}
+		user.RoleType = enums.SUTDENT_USER
+		user.CollegeId = utils.DerefUint(userAuth.CollegeId)
+		user.StudentId = utils.DerefString(userAuth.StudentId)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return v1.ErrUpdateFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if req.Approved {
+		// 旧角色的访问 token 失效，客户端通过刷新 token 获取新角色的 token
+		if err = s.userRepo.DeleteByPrefix(ctx, repository.LoginTokenUserPrefix(userAuth.UserId)); err != nil {
+			s.Logger.Error("userService.ReviewUserAuth revoke token error", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// getAdminCollegeId 获取管理员所属学院，超级管理员返回 0 表示不限学院
+func (s *userService) getAdminCollegeId(ctx context.Context, adminId string, adminRole int) (uint, error) {
+	if adminRole == enums.SUPER_ADMIN {
+		return 0, nil
+	}
+	admin, err := s.userRepo.GetByUserId(ctx, adminId)
+	if err != nil {
+		return 0, v1.ErrUserNotExist
+	}
+	if admin.CollegeId == 0 {
+		return 0, v1.ErrPermissionDenied
+	}
+	return admin.CollegeId, nil
+}
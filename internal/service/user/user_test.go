@@ -0,0 +1,134 @@
+package user
+
+import (
+	"context"
+	v1 "projectName/api/v1"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/internal/service"
+	"projectName/pkg/log"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRepository 使用内存 sqlite 和 miniredis 的仓储
+func newTestRepository(t *testing.T) (*repository.Repository, *gorm.DB) {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库每个连接独立，只保留一个连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&model.User{}, &model.UserAuth{}, &model.College{}))
+	return repository.NewRepository(&log.Logger{Logger: zap.NewNop()}, db, rdb, nil), db
+}
+
+func newTestUserService(t *testing.T) (*userService, *gorm.DB) {
+	repo, db := newTestRepository(t)
+	return newUserService(repo).(*userService), db
+}
+
+func newUserService(repo *repository.Repository) UserService {
+	l := &log.Logger{Logger: zap.NewNop()}
+	return NewUserService(service.NewService(repository.NewTransaction(repo), l, nil, nil), repository.NewUserRepository(repo), nil)
+}
+
+func createUser(t *testing.T, db *gorm.DB, userId string, roleType int, collegeId uint) {
+	require.NoError(t, db.Create(&model.User{UserId: userId, Phone: userId, Nickname: "nick-" + userId, RoleType: roleType, CollegeId: collegeId}).Error)
+}
+
+func createUserAuth(t *testing.T, db *gorm.DB, userId string, collegeId uint) uint {
+	userAuth := &model.UserAuth{UserId: userId, RequestType: 1, Status: enums.WAITING, CollegeId: &collegeId}
+	require.NoError(t, db.Create(userAuth).Error)
+	return userAuth.Id
+}
+
+func TestGetUserAuthList(t *testing.T) {
+	ctx := context.Background()
+	s, db := newTestUserService(t)
+	createUser(t, db, "admin1", enums.SCHOOL_ADMIN, 1)
+	createUser(t, db, "admin0", enums.SCHOOL_ADMIN, 0)
+	createUser(t, db, "super", enums.SUPER_ADMIN, 0)
+	createUser(t, db, "a", enums.COMMON_USER, 0)
+	createUser(t, db, "b", enums.COMMON_USER, 0)
+	createUserAuth(t, db, "a", 1)
+	createUserAuth(t, db, "b", 2)
+	createUserAuth(t, db, "a", 1)
+	createUserAuth(t, db, "gone", 1)
+
+	tests := []struct {
+		name      string
+		adminId   string
+		adminRole int
+		want      []string // 每条请求的 userId:nickname
+		wantErr   error
+	}{
+		{"school admin sees own college", "admin1", enums.SCHOOL_ADMIN, []string{"a:nick-a", "a:nick-a", "gone:"}, nil},
+		{"super admin sees all", "super", enums.SUPER_ADMIN, []string{"a:nick-a", "b:nick-b", "a:nick-a", "gone:"}, nil},
+		{"school admin without college", "admin0", enums.SCHOOL_ADMIN, nil, v1.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := s.GetUserAuthList(ctx, tt.adminId, tt.adminRole, &v1.GetUserAuthListReq{})
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, data := range list.UserAuthList {
+				got = append(got, data.UserId+":"+data.Nickname)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, int64(len(tt.want)), list.TotalCount)
+		})
+	}
+}
+
+func TestReviewUserAuth(t *testing.T) {
+	ctx := context.Background()
+	s, db := newTestUserService(t)
+	createUser(t, db, "admin1", enums.SCHOOL_ADMIN, 1)
+	createUser(t, db, "a", enums.COMMON_USER, 0)
+	createUser(t, db, "b", enums.COMMON_USER, 0)
+	createUser(t, db, "promoted", enums.SCHOOL_ADMIN, 1)
+	authA := createUserAuth(t, db, "a", 1)
+	authB := createUserAuth(t, db, "b", 1)
+	authOther := createUserAuth(t, db, "b", 2)
+	authPromoted := createUserAuth(t, db, "promoted", 1)
+
+	tests := []struct {
+		name     string
+		req      v1.ReviewUserAuthReq
+		wantErr  error
+		userId   string
+		wantRole int
+	}{
+		{"approve", v1.ReviewUserAuthReq{Id: authA, Approved: true}, nil, "a", enums.SUTDENT_USER},
+		{"already processed", v1.ReviewUserAuthReq{Id: authA, Approved: false}, v1.ErrUserAuthProcessed, "a", enums.SUTDENT_USER},
+		{"reject", v1.ReviewUserAuthReq{Id: authB, Remark: "no"}, nil, "b", enums.COMMON_USER},
+		{"other college", v1.ReviewUserAuthReq{Id: authOther, Approved: true}, v1.ErrPermissionDenied, "b", enums.COMMON_USER},
+		{"not exist", v1.ReviewUserAuthReq{Id: 999, Approved: true}, v1.ErrUserAuthNotExist, "b", enums.COMMON_USER},
+		{"approve never demotes", v1.ReviewUserAuthReq{Id: authPromoted, Approved: true}, nil, "promoted", enums.SCHOOL_ADMIN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ReviewUserAuth(ctx, "admin1", enums.SCHOOL_ADMIN, &tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			var user model.User
+			require.NoError(t, db.Where("user_id = ?", tt.userId).First(&user).Error)
+			assert.Equal(t, tt.wantRole, user.RoleType)
+		})
+	}
+}
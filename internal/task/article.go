@@ -0,0 +1,94 @@
+package task
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"projectName/internal/repository"
+	"time"
+)
+
+// 每次处理的定时发布文章数量
+const scheduledPublishBatchSize = 100
+
+type ArticleTask interface {
+	PublishScheduledArticles(ctx context.Context) error
+	FlushArticleViews(ctx context.Context) error
+}
+
+func NewArticleTask(
+	task *Task,
+	articleRepository repository.ArticleRepository,
+	esOutboxRepository repository.EsOutboxRepository,
+	articleStatRepository repository.ArticleStatRepository,
+) ArticleTask {
+	return &articleTask{
+		articleRepository:     articleRepository,
+		esOutboxRepository:    esOutboxRepository,
+		articleStatRepository: articleStatRepository,
+		Task:                  task,
+	}
+}
+
+type articleTask struct {
+	articleRepository     repository.ArticleRepository
+	esOutboxRepository    repository.EsOutboxRepository
+	articleStatRepository repository.ArticleStatRepository
+	*Task
+}
+
+// PublishScheduledArticles 发布已到时间的定时发布文章，并写入es同步任务
+// 通过带状态条件的更新抢占文章，多个 task 实例同时运行时每篇文章只会被发布一次
+func (t articleTask) PublishScheduledArticles(ctx context.Context) error {
+	articles, err := t.articleRepository.GetDueScheduledArticles(ctx, time.Now(), scheduledPublishBatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range articles {
+		article := &articles[i]
+		err = t.tm.Transaction(ctx, func(ctx context.Context) error {
+			ok, err := t.articleRepository.PublishScheduledArticle(ctx, article.ArticleID)
+			if err != nil || !ok {
+				// 已被其他实例发布或状态已变更
+				return err
+			}
+			// 写入es同步任务，与发布一同提交
+			return t.esOutboxRepository.Enqueue(ctx, article.ArticleID)
+		})
+		if err != nil {
+			t.logger.Error("PublishScheduledArticles error", zap.Uint("articleId", article.ArticleID), zap.Error(err))
+			continue
+		}
+		t.logger.Info("PublishScheduledArticles", zap.Uint("articleId", article.ArticleID))
+	}
+	return nil
+}
+
+// FlushArticleViews 将 redis 中累计的浏览量写入数据库，并写入es同步任务以更新搜索排序使用的浏览量
+// 写入失败时保留已取出的浏览量，下次执行时重试；同一批次只会累加一次，清除 redis 失败或执行超过锁的过期时间时不会重复累加
+func (t articleTask) FlushArticleViews(ctx context.Context) error {
+	owner := t.lockOwner()
+	batchId, views, err := t.articleStatRepository.TakePendingViews(ctx, owner)
+	if err != nil || len(views) == 0 {
+		return err
+	}
+	articleIds := make([]uint, 0, len(views))
+	for articleId := range views {
+		articleIds = append(articleIds, articleId)
+	}
+	var applied bool
+	err = t.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if applied, err = t.articleStatRepository.AddViews(ctx, batchId, views); err != nil || !applied {
+			return err
+		}
+		return t.esOutboxRepository.Enqueue(ctx, articleIds...)
+	})
+	if err != nil {
+		return err
+	}
+	if err = t.articleStatRepository.ClearFlushingViews(ctx, owner); err != nil {
+		return err
+	}
+	t.logger.Info("FlushArticleViews", zap.String("batchId", batchId), zap.Int("articles", len(articleIds)), zap.Bool("applied", applied))
+	return nil
+}
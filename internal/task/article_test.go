@@ -0,0 +1,111 @@
+package task
+
+import (
+	"context"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/pkg/log"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestArticleTask(t *testing.T) (*articleTask, repository.ArticleStatRepository, *gorm.DB) {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库每个连接独立，只保留一个连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&model.Article{}, &model.EsOutbox{}, &model.ArticleStat{}, &model.ArticleViewBatch{}))
+
+	l := &log.Logger{Logger: zap.NewNop()}
+	repo := repository.NewRepository(l, db, rdb, nil)
+	statRepository := repository.NewArticleStatRepository(repo)
+	task := NewArticleTask(
+		NewTask(repository.NewTransaction(repo), l, nil),
+		repository.NewArticleRepository(repo, viper.New()),
+		repository.NewEsOutboxRepository(repo),
+		statRepository,
+	).(*articleTask)
+	return task, statRepository, db
+}
+
+func TestPublishScheduledArticles(t *testing.T) {
+	ctx := context.Background()
+	task, _, db := newTestArticleTask(t)
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	articles := []*model.Article{
+		{Title: "due", Status: enums.StatusScheduled, PublishAt: &past},
+		{Title: "future", Status: enums.StatusScheduled, PublishAt: &future},
+		{Title: "draft", Status: enums.StatusDraft, PublishAt: &past},
+		{Title: "published", Status: enums.StatusPublished, PublishAt: &past},
+	}
+	for _, article := range articles {
+		require.NoError(t, db.Create(article).Error)
+	}
+
+	require.NoError(t, task.PublishScheduledArticles(ctx))
+	wantStatus := []int{enums.StatusPublished, enums.StatusScheduled, enums.StatusDraft, enums.StatusPublished}
+	for i, article := range articles {
+		var status int
+		require.NoError(t, db.Model(&model.Article{}).Where("article_id = ?", article.ArticleID).Pluck("status", &status).Error)
+		assert.Equal(t, wantStatus[i], status, article.Title)
+	}
+	var outbox []uint
+	require.NoError(t, db.Model(&model.EsOutbox{}).Pluck("article_id", &outbox).Error)
+	assert.Equal(t, []uint{articles[0].ArticleID}, outbox)
+
+	// 已发布的文章不会被再次发布和同步
+	require.NoError(t, task.PublishScheduledArticles(ctx))
+	require.NoError(t, db.Model(&model.EsOutbox{}).Pluck("article_id", &outbox).Error)
+	assert.Equal(t, []uint{articles[0].ArticleID}, outbox)
+	ok, err := task.articleRepository.PublishScheduledArticle(ctx, articles[0].ArticleID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFlushArticleViews(t *testing.T) {
+	ctx := context.Background()
+	task, statRepository, db := newTestArticleTask(t)
+	for _, viewerId := range []string{"u1", "u2", "u1"} {
+		_, err := statRepository.RecordView(ctx, 1, viewerId, time.Hour)
+		require.NoError(t, err)
+	}
+	_, err := statRepository.RecordView(ctx, 2, "u1", time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, task.FlushArticleViews(ctx))
+	var stats []model.ArticleStat
+	require.NoError(t, db.Order("article_id").Find(&stats).Error)
+	require.Len(t, stats, 2)
+	assert.Equal(t, int64(2), stats[0].Views)
+	assert.Equal(t, int64(1), stats[1].Views)
+	var outbox int64
+	require.NoError(t, db.Model(&model.EsOutbox{}).Count(&outbox).Error)
+	assert.Equal(t, int64(2), outbox)
+
+	// 没有新的浏览时不写入
+	require.NoError(t, task.FlushArticleViews(ctx))
+	require.NoError(t, db.Order("article_id").Find(&stats).Error)
+	assert.Equal(t, int64(2), stats[0].Views)
+	require.NoError(t, db.Model(&model.EsOutbox{}).Count(&outbox).Error)
+	assert.Equal(t, int64(2), outbox)
+
+	views, err := statRepository.GetViews(ctx, []uint{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, map[uint]int64{1: 2, 2: 1}, views)
+}
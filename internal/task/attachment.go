@@ -0,0 +1,115 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"io"
+	"projectName/internal/enums"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"projectName/pkg/extractor"
+	"projectName/pkg/storage"
+)
+
+const (
+	extractBatchSize   = 20       // 每次处理的附件数量
+	extractMaxAttempts = 3        // 提取失败的最大重试次数
+	extractMaxFileSize = 50 << 20 // 超过该大小的附件不提取文本
+)
+
+type AttachmentTask interface {
+	ExtractAttachments(ctx context.Context) error
+}
+
+func NewAttachmentTask(
+	task *Task,
+	storage storage.Storage,
+	attachmentRepository repository.AttachmentRepository,
+	esOutboxRepository repository.EsOutboxRepository,
+) AttachmentTask {
+	return &attachmentTask{
+		storage:              storage,
+		attachmentRepository: attachmentRepository,
+		esOutboxRepository:   esOutboxRepository,
+		Task:                 task,
+	}
+}
+
+type attachmentTask struct {
+	storage              storage.Storage
+	attachmentRepository repository.AttachmentRepository
+	esOutboxRepository   repository.EsOutboxRepository
+	*Task
+}
+
+// ExtractAttachments 提取待处理附件的文本，并写入所属文章的es同步任务
+// 失败的附件会在后续调度中重试，超过次数后不再处理
+func (t *attachmentTask) ExtractAttachments(ctx context.Context) error {
+	attachments, err := t.attachmentRepository.GetPendingExtractAttachments(ctx, extractMaxAttempts, extractBatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range attachments {
+		attachment := &attachments[i]
+		t.extract(ctx, attachment)
+		// 提取结果与es同步任务一同提交，同一文章的多个任务由同步服务合并处理
+		err = t.tm.Transaction(ctx, func(ctx context.Context) error {
+			if err := t.attachmentRepository.UpdateExtractResult(ctx, attachment); err != nil {
+				return err
+			}
+			if attachment.ExtractStatus == enums.ExtractDone && attachment.ArticleID != 0 {
+				return t.esOutboxRepository.Enqueue(ctx, attachment.ArticleID)
+			}
+			return nil
+		})
+		if err != nil {
+			t.logger.Error("ExtractAttachments save error", zap.Uint("attachmentId", attachment.Id), zap.Error(err))
+			continue
+		}
+		t.logger.Info("ExtractAttachments", zap.Uint("attachmentId", attachment.Id), zap.Int("status", attachment.ExtractStatus))
+	}
+	return nil
+}
+
+// extract 提取单个附件的文本并设置提取状态，相同内容的文件复用已提取的文本
+func (t *attachmentTask) extract(ctx context.Context, attachment *model.Attachment) {
+	content, ok, err := t.attachmentRepository.GetExtractedContentByChecksum(ctx, attachment.Checksum)
+	if err == nil && ok {
+		attachment.Content = content
+		attachment.ExtractStatus = enums.ExtractDone
+		return
+	}
+	if attachment.Size > extractMaxFileSize {
+		attachment.ExtractStatus = enums.ExtractUnsupported
+		return
+	}
+
+	attachment.ExtractAttempts++
+	reader, err := t.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		t.logger.Error("ExtractAttachments get error", zap.Uint("attachmentId", attachment.Id), zap.Error(err))
+		attachment.ExtractStatus = enums.ExtractFailed
+		return
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(io.LimitReader(reader, extractMaxFileSize))
+	if err != nil {
+		t.logger.Error("ExtractAttachments read error", zap.Uint("attachmentId", attachment.Id), zap.Error(err))
+		attachment.ExtractStatus = enums.ExtractFailed
+		return
+	}
+	text, err := extractor.Extract(attachment.MimeType, data)
+	if err != nil {
+		// 没有可读文本的文件重试也无法提取
+		if errors.Is(err, extractor.ErrUnsupported) || errors.Is(err, extractor.ErrNoText) {
+			attachment.ExtractStatus = enums.ExtractUnsupported
+			return
+		}
+		t.logger.Error("ExtractAttachments extract error", zap.Uint("attachmentId", attachment.Id), zap.Error(err))
+		attachment.ExtractStatus = enums.ExtractFailed
+		return
+	}
+	attachment.Content = text
+	attachment.ExtractStatus = enums.ExtractDone
+}
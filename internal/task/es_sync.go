@@ -0,0 +1,149 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	v1 "projectName/api/v1"
+	"projectName/internal/model"
+	"projectName/internal/repository"
+	"time"
+)
+
+const (
+	esSyncBatchSize    = 200              // 每次处理的同步任务数量
+	esSyncRetryBase    = 5 * time.Second  // 首次重试的等待时间，之后按失败次数翻倍
+	esSyncRetryMax     = 10 * time.Minute // 重试等待时间上限
+	esSyncLagThreshold = time.Minute      // 同步延迟超过该时间时记录告警日志
+	esSyncLockTTL      = 5 * time.Minute  // 同步锁的过期时间，持有锁的实例异常退出时过期后由其他实例继续处理
+)
+
+type EsSyncTask interface {
+	SyncEsArticles(ctx context.Context) error
+}
+
+func NewEsSyncTask(
+	task *Task,
+	articleRepository repository.ArticleRepository,
+	esOutboxRepository repository.EsOutboxRepository,
+) EsSyncTask {
+	return &esSyncTask{
+		articleRepository:  articleRepository,
+		esOutboxRepository: esOutboxRepository,
+		Task:               task,
+	}
+}
+
+type esSyncTask struct {
+	articleRepository  repository.ArticleRepository
+	esOutboxRepository repository.EsOutboxRepository
+	mappingReady       bool // es 字段映射是否已创建
+	*Task
+}
+
+// SyncEsArticles 处理到期的es同步任务，按文章的最新状态写入或删除es文档，同一文章的多个任务合并处理
+// 多个 task 实例并发同步同一文章时，先读到旧数据的实例可能后写入es，因此通过锁保证同一时间只有一个实例处理
+func (t *esSyncTask) SyncEsArticles(ctx context.Context) error {
+	owner := t.lockOwner()
+	ok, err := t.esOutboxRepository.AcquireSyncLock(ctx, owner, esSyncLockTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	defer func() {
+		_ = t.esOutboxRepository.ReleaseSyncLock(ctx, owner)
+	}()
+
+	if !t.mappingReady {
+		if err := t.articleRepository.EnsureEsArticleMapping(ctx); err != nil {
+			return err
+		}
+		t.mappingReady = true
+	}
+	outboxes, err := t.esOutboxRepository.GetDueOutbox(ctx, time.Now(), esSyncBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(outboxes) == 0 {
+		return nil
+	}
+	t.reportLag(outboxes[0].CreatedAt, len(outboxes))
+
+	var articleIds []uint
+	groups := make(map[uint][]model.EsOutbox)
+	for _, outbox := range outboxes {
+		if _, ok := groups[outbox.ArticleID]; !ok {
+			articleIds = append(articleIds, outbox.ArticleID)
+		}
+		groups[outbox.ArticleID] = append(groups[outbox.ArticleID], outbox)
+	}
+	for _, articleId := range articleIds {
+		group := groups[articleId]
+		ids := make([]uint, 0, len(group))
+		attempts := 0
+		for _, outbox := range group {
+			ids = append(ids, outbox.Id)
+			if outbox.Attempts > attempts {
+				attempts = outbox.Attempts
+			}
+		}
+		if err = t.syncArticle(ctx, articleId); err != nil {
+			attempts++
+			nextRetryAt := time.Now().Add(esSyncBackoff(attempts))
+			t.logger.Error("SyncEsArticles error", zap.Uint("articleId", articleId), zap.Int("attempts", attempts),
+				zap.Time("nextRetryAt", nextRetryAt), zap.Error(err))
+			if err = t.esOutboxRepository.MarkOutboxFailed(ctx, ids, attempts, nextRetryAt, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = t.esOutboxRepository.DeleteOutbox(ctx, ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncArticle 文章已发布时写入es，否则（未发布、已删除或不存在）从es中删除
+func (t *esSyncTask) syncArticle(ctx context.Context, articleId uint) error {
+	article, err := t.articleRepository.GetArticleFromDB(ctx, articleId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return t.articleRepository.DeleteEsArticle(ctx, articleId)
+		}
+		return err
+	}
+	if !article.IsSearchable() {
+		return t.articleRepository.DeleteEsArticle(ctx, articleId)
+	}
+	esArticle, err := t.articleRepository.GetEsArticle(ctx, article)
+	if err != nil {
+		return err
+	}
+	return t.articleRepository.CreateEsArticle(ctx, esArticle)
+}
+
+// reportLag 记录同步延迟，即最早的待同步任务已等待的时间
+func (t *esSyncTask) reportLag(oldest time.Time, batchSize int) {
+	lag := time.Since(oldest)
+	fields := []zap.Field{zap.Duration("lag", lag), zap.Int("batchSize", batchSize)}
+	if lag > esSyncLagThreshold {
+		t.logger.Warn("SyncEsArticles lag", fields...)
+		return
+	}
+	t.logger.Info("SyncEsArticles lag", fields...)
+}
+
+// esSyncBackoff 按失败次数计算下次重试的等待时间
+func esSyncBackoff(attempts int) time.Duration {
+	delay := esSyncRetryBase
+	for i := 1; i < attempts && delay < esSyncRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > esSyncRetryMax {
+		delay = esSyncRetryMax
+	}
+	return delay
+}
@@ -0,0 +1,27 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEsSyncBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, esSyncRetryBase},
+		{1, esSyncRetryBase},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{7, 320 * time.Second},
+		{8, esSyncRetryMax},
+		{9, esSyncRetryMax},
+		{1000, esSyncRetryMax},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, esSyncBackoff(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"projectName/pkg/jwt"
 	"projectName/pkg/log"
 	"projectName/pkg/sid"
+	"strconv"
+	"time"
 )
 
 type Task struct {
@@ -25,3 +27,13 @@ func NewTask(
 		tm:     tm,
 	}
 }
+
+// lockOwner 生成本次执行持有分布式锁时使用的唯一标识
+func (t *Task) lockOwner() string {
+	if t.sid != nil {
+		if id, err := t.sid.GenSonyflakeID(); err == nil {
+			return strconv.FormatInt(id, 10)
+		}
+	}
+	return strconv.FormatInt(time.Now().UnixNano(), 10)
+}
@@ -0,0 +1,65 @@
+package extractor
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTextLength 单个附件提取文本的最大字符数，超出部分截断
+const MaxTextLength = 200000
+
+// maxXmlSize 解压后单个文件的最大字节数，防止压缩炸弹
+const maxXmlSize = 64 << 20
+
+var (
+	ErrUnsupported = errors.New("extractor: unsupported file type")
+	ErrNoText      = errors.New("extractor: no extractable text") // 文件中没有可读文本，如扫描件
+)
+
+// Extract 按 MIME 类型从文件内容中提取纯文本，支持 PDF、DOCX、Markdown 和纯文本
+func Extract(mimeType string, data []byte) (string, error) {
+	var text string
+	var err error
+	switch mimeType {
+	case "text/plain", "text/markdown":
+		text = strings.ToValidUTF8(string(data), "")
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		text, err = extractDocx(data)
+	case "application/pdf":
+		text, err = extractPdf(data)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+	return truncate(normalizeSpace(text), MaxTextLength), nil
+}
+
+// normalizeSpace 合并多余的空行和行尾空白
+func normalizeSpace(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	result := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		result = append(result, line)
+	}
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
+
+func truncate(text string, maxRunes int) string {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	return string([]rune(text)[:maxRunes])
+}
@@ -0,0 +1,122 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mimeDocx = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	mimePdf  = "application/pdf"
+
+	helveticaFont = "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"
+	// CID 字体且没有 ToUnicode 映射，无法还原文字
+	cidFont = "<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /Encoding /Identity-H >>"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		data     []byte
+		want     string
+		wantErr  error
+	}{
+		{"plain text", "text/plain", []byte("hello  \r\n\r\n\r\n\r\nworld\n"), "hello\n\nworld", nil},
+		{"markdown drops invalid utf8", "text/markdown", []byte("# 标题\xff\n正文"), "# 标题\n正文", nil},
+		{"unsupported", "image/png", []byte{0x89, 'P', 'N', 'G'}, "", ErrUnsupported},
+		{
+			"docx",
+			mimeDocx,
+			buildDocx(t, `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`+
+				`<w:p><w:r><w:t>第一段</w:t></w:r><w:r><w:tab/><w:t>续</w:t></w:r></w:p>`+
+				`<w:p><w:r><w:t>第二段</w:t><w:br/><w:t>换行</w:t></w:r></w:p>`+
+				`</w:body></w:document>`),
+			"第一段\t续\n第二段\n换行",
+			nil,
+		},
+		{"pdf", mimePdf, buildPdf("BT /F1 12 Tf 72 720 Td (Hello PDF World) Tj T* (Second line) Tj ET", helveticaFont), "Hello PDF World\nSecond line", nil},
+		{"pdf without text", mimePdf, buildPdf("BT ET", helveticaFont), "", ErrNoText},
+		{"pdf with cid font without ToUnicode", mimePdf, buildPdf("BT /F1 12 Tf 72 720 Td <0001> Tj ET", cidFont), "", ErrNoText},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(tt.mimeType, tt.data)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtract_Malformed(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		data     []byte
+	}{
+		{"docx not zip", mimeDocx, []byte("not a zip")},
+		{"docx without document", mimeDocx, buildZip(t, "word/styles.xml", "<w:styles/>")},
+		{"docx broken xml", mimeDocx, buildDocx(t, "<w:document><w:body>")},
+		{"pdf garbage", mimePdf, []byte("%PDF-1.4\ngarbage")},
+		{"pdf truncated", mimePdf, buildPdf("BT /F1 12 Tf (x) Tj ET", helveticaFont)[:200]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(tt.mimeType, tt.data)
+			assert.Error(t, err)
+			assert.Empty(t, got)
+		})
+	}
+}
+
+func TestExtract_Truncate(t *testing.T) {
+	got, err := Extract("text/plain", []byte(strings.Repeat("文", MaxTextLength+10)))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("文", MaxTextLength), got)
+}
+
+func buildDocx(t *testing.T, documentXml string) []byte {
+	return buildZip(t, "word/document.xml", documentXml)
+}
+
+func buildZip(t *testing.T, name string, content string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create(name)
+	require.NoError(t, err)
+	_, err = f.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// buildPdf 生成只有一页的 PDF，页面使用字体 F1
+func buildPdf(content string, font string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		font,
+	}
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, 0, len(objects))
+	for i, object := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
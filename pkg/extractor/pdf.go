@@ -0,0 +1,54 @@
+package extractor
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/ledongthuc/pdf"
+	"strings"
+)
+
+// extractPdf 逐页提取 PDF 中的文本，按字体的 Encoding 和 ToUnicode 映射解码为 UTF-8
+// 使用 CID 字体（多见于中文文档）但缺少 ToUnicode 映射的页面无法还原文字，直接跳过；
+// 所有页面都没有可读文本时（如扫描件）返回 ErrNoText
+func extractPdf(data []byte) (text string, err error) {
+	// 解析器遇到损坏的文件会 panic
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("extractor: malformed pdf: %v", r)
+		}
+	}()
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() || !hasReadableFont(page) {
+			continue
+		}
+		pageText, err := page.GetPlainText(nil)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(pageText)
+		b.WriteByte('\n')
+	}
+	// 无法解码的字符会变成替换字符，不计入文本
+	text = strings.TrimSpace(strings.ReplaceAll(strings.ToValidUTF8(b.String(), ""), "\uFFFD", ""))
+	if text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+// hasReadableFont 页面中是否有能解码为 Unicode 的字体，CID 字体（Type0）必须带 ToUnicode 映射
+func hasReadableFont(page pdf.Page) bool {
+	for _, name := range page.Fonts() {
+		font := page.Font(name)
+		if font.V.Key("Subtype").Name() != "Type0" || font.V.Key("ToUnicode").Kind() == pdf.Stream {
+			return true
+		}
+	}
+	return false
+}
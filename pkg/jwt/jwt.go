@@ -11,35 +11,86 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	TokenTypeAccess  = "access"  // 访问 token
+	TokenTypeRefresh = "refresh" // 刷新 token
+
+	defaultAccessExpire  = time.Hour * 2
+	defaultRefreshExpire = time.Hour * 24 * 7
+)
+
 type JWT struct {
-	key    []byte
-	logger *log.Logger
+	key           []byte
+	logger        *log.Logger
+	accessExpire  time.Duration // 访问 token 有效期
+	refreshExpire time.Duration // 刷新 token 有效期
 }
 
 type MyCustomClaims struct {
-	UserId   string
-	RoleType int
+	UserId    string
+	RoleType  int
+	TokenType string // access / refresh
+	RefreshId string // 刷新 token 的轮换ID，仅刷新 token 有值
 	jwt.RegisteredClaims
 }
 
-func NewJwt(conf *viper.Viper) *JWT {
-	return &JWT{key: []byte(conf.GetString("security.jwt.key"))}
+func NewJwt(conf *viper.Viper, logger *log.Logger) *JWT {
+	accessExpire := conf.GetDuration("security.jwt.access_expire")
+	if accessExpire <= 0 {
+		accessExpire = defaultAccessExpire
+	}
+	refreshExpire := conf.GetDuration("security.jwt.refresh_expire")
+	if refreshExpire <= 0 {
+		refreshExpire = defaultRefreshExpire
+	}
+	return &JWT{
+		key:           []byte(conf.GetString("security.jwt.key")),
+		logger:        logger,
+		accessExpire:  accessExpire,
+		refreshExpire: refreshExpire,
+	}
 }
 
-func (j *JWT) GenToken(userId string, roleType int, expiresAt time.Time) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, MyCustomClaims{
-		UserId:   userId,
-		RoleType: roleType, // 设置 roleType
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "",
-			Subject:   "",
-			ID:        "",
-			Audience:  []string{},
-		},
-	})
+// AccessExpire 访问 token 有效期
+func (j *JWT) AccessExpire() time.Duration {
+	return j.accessExpire
+}
+
+// RefreshExpire 刷新 token 有效期
+func (j *JWT) RefreshExpire() time.Duration {
+	return j.refreshExpire
+}
+
+// GenToken 生成访问 token，tokenId 写入 jti 用于服务端会话校验
+func (j *JWT) GenToken(userId string, roleType int, tokenId string, expiresAt time.Time) (string, error) {
+	return j.signToken(MyCustomClaims{
+		UserId:    userId,
+		RoleType:  roleType, // 设置 roleType
+		TokenType: TokenTypeAccess,
+	}, tokenId, expiresAt)
+}
+
+// GenRefreshToken 生成刷新 token，tokenId 与访问 token 相同，refreshId 每次轮换都会变化
+func (j *JWT) GenRefreshToken(userId string, roleType int, tokenId string, refreshId string, expiresAt time.Time) (string, error) {
+	return j.signToken(MyCustomClaims{
+		UserId:    userId,
+		RoleType:  roleType,
+		TokenType: TokenTypeRefresh,
+		RefreshId: refreshId,
+	}, tokenId, expiresAt)
+}
+
+func (j *JWT) signToken(claims MyCustomClaims, tokenId string, expiresAt time.Time) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		NotBefore: jwt.NewNumericDate(time.Now()),
+		Issuer:    "",
+		Subject:   "",
+		ID:        tokenId,
+		Audience:  []string{},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign and get the complete encoded token as a string using the key
 	tokenString, err := token.SignedString(j.key)
@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"projectName/pkg/log"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestJwt(key string, settings map[string]interface{}) *JWT {
+	conf := viper.New()
+	conf.Set("security.jwt.key", key)
+	for k, v := range settings {
+		conf.Set(k, v)
+	}
+	return NewJwt(conf, &log.Logger{Logger: zap.NewNop()})
+}
+
+func TestNewJwt_Expire(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    map[string]interface{}
+		wantAccess  time.Duration
+		wantRefresh time.Duration
+	}{
+		{"defaults", nil, defaultAccessExpire, defaultRefreshExpire},
+		{"configured", map[string]interface{}{"security.jwt.access_expire": "15m", "security.jwt.refresh_expire": "24h"}, 15 * time.Minute, 24 * time.Hour},
+		{"non positive falls back", map[string]interface{}{"security.jwt.access_expire": "-1s", "security.jwt.refresh_expire": "0"}, defaultAccessExpire, defaultRefreshExpire},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := newTestJwt("key", tt.settings)
+			assert.Equal(t, tt.wantAccess, j.AccessExpire())
+			assert.Equal(t, tt.wantRefresh, j.RefreshExpire())
+		})
+	}
+}
+
+func TestGenToken_Claims(t *testing.T) {
+	j := newTestJwt("key", nil)
+	expiresAt := time.Now().Add(time.Hour)
+
+	access, err := j.GenToken("u1", 2, "t1", expiresAt)
+	require.NoError(t, err)
+	claims, err := j.ParseToken("Bearer " + access)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.UserId)
+	assert.Equal(t, 2, claims.RoleType)
+	assert.Equal(t, TokenTypeAccess, claims.TokenType)
+	assert.Equal(t, "t1", claims.ID)
+	assert.Empty(t, claims.RefreshId)
+	assert.Equal(t, expiresAt.Unix(), claims.ExpiresAt.Unix())
+
+	refresh, err := j.GenRefreshToken("u1", 2, "t1", "r1", expiresAt)
+	require.NoError(t, err)
+	claims, err = j.ParseToken(refresh)
+	require.NoError(t, err)
+	assert.Equal(t, TokenTypeRefresh, claims.TokenType)
+	assert.Equal(t, "t1", claims.ID)
+	assert.Equal(t, "r1", claims.RefreshId)
+}
+
+func TestParseToken_Invalid(t *testing.T) {
+	j := newTestJwt("key", nil)
+	expired, err := j.GenToken("u1", 0, "t1", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	otherKey, err := newTestJwt("other", nil).GenToken("u1", 0, "t1", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"bearer only", "Bearer "},
+		{"malformed", "abc"},
+		{"expired", expired},
+		{"signed with other key", otherKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := j.ParseToken(tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
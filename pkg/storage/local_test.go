@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_Path(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "uploads")
+	s := NewLocalStorage(root)
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{"attachments/2024/a.pdf", filepath.Join(root, "attachments", "2024", "a.pdf"), false},
+		{"/attachments/a.pdf", filepath.Join(root, "attachments", "a.pdf"), false},
+		{"attachments//./a.pdf", filepath.Join(root, "attachments", "a.pdf"), false},
+		{"../a.pdf", "", true},
+		{"attachments/../../a.pdf", "", true},
+		{"attachments/..", "", true},
+		{"..", "", true},
+		{"a..pdf", "", true},
+		{"", "", true},
+		{"/", "", true},
+		{".", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := s.path(tt.key)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, strings.HasPrefix(got, root+string(filepath.Separator)))
+		})
+	}
+}
+
+func TestLocalStorage_PutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewLocalStorage(filepath.Join(dir, "uploads"))
+
+	require.NoError(t, s.Put(ctx, "a/b.txt", strings.NewReader("hello"), 5, "text/plain"))
+	rc, err := s.Get(ctx, "a/b.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, rc.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, s.Delete(ctx, "a/b.txt"))
+	_, err = s.Get(ctx, "a/b.txt")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+	// 重复删除不报错
+	assert.NoError(t, s.Delete(ctx, "a/b.txt"))
+
+	assert.Error(t, s.Put(ctx, "../escape.txt", strings.NewReader("x"), 1, "text/plain"))
+	_, err = os.Stat(filepath.Join(dir, "escape.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNewStorage(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		env     map[string]string
+		want    interface{}
+		wantErr bool
+	}{
+		{"local by default", nil, nil, &LocalStorage{}, false},
+		{"s3 without credentials", map[string]interface{}{"storage.driver": DriverS3}, nil, nil, true},
+		{
+			"s3 credentials from env",
+			map[string]interface{}{"storage.driver": DriverS3, "storage.s3.endpoint": "http://127.0.0.1:9000"},
+			map[string]string{S3AccessKeyEnv: "ak", S3SecretKeyEnv: "sk"},
+			&S3Storage{},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			conf := viper.New()
+			for key, value := range tt.config {
+				conf.Set(key, value)
+			}
+			got, err := NewStorage(conf)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	DriverLocal = "local" // 本地磁盘
+	DriverS3    = "s3"    // S3 兼容的对象存储，如 MinIO
+
+	S3AccessKeyEnv = "STORAGE_S3_ACCESS_KEY" // S3 访问密钥的环境变量
+	S3SecretKeyEnv = "STORAGE_S3_SECRET_KEY" // S3 私有密钥的环境变量
+)
+
+var ErrObjectNotFound = errors.New("storage: object not found")
+
+// Storage 附件存储后端，key 为存储中的对象路径
+type Storage interface {
+	Put(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage 根据 storage.driver 配置创建存储后端，默认使用本地磁盘
+// S3 的密钥通过环境变量设置，优先于配置文件
+func NewStorage(conf *viper.Viper) (Storage, error) {
+	switch conf.GetString("storage.driver") {
+	case DriverS3:
+		_ = conf.BindEnv("storage.s3.access_key", S3AccessKeyEnv)
+		_ = conf.BindEnv("storage.s3.secret_key", S3SecretKeyEnv)
+		accessKey := conf.GetString("storage.s3.access_key")
+		secretKey := conf.GetString("storage.s3.secret_key")
+		if accessKey == "" || secretKey == "" {
+			return nil, fmt.Errorf("storage: s3 credentials are empty, set them via %s and %s", S3AccessKeyEnv, S3SecretKeyEnv)
+		}
+		return NewS3Storage(
+			conf.GetString("storage.s3.endpoint"),
+			conf.GetString("storage.s3.region"),
+			conf.GetString("storage.s3.bucket"),
+			accessKey,
+			secretKey,
+		), nil
+	default:
+		root := conf.GetString("storage.local_root")
+		if root == "" {
+			root = "./storage/files"
+		}
+		return NewLocalStorage(root), nil
+	}
+}
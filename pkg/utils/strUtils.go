@@ -182,3 +182,29 @@ func PadRight(s string, length int, padChar rune) string {
 	}
 	return s + string(padChar)
 }
+
+// DerefString 获取字符串指针的值，nil 时返回空字符串
+func DerefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// DerefUint 获取 uint 指针的值，nil 时返回 0
+func DerefUint(n *uint) uint {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// ContainsString 判断字符串切片中是否包含指定字符串
+func ContainsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
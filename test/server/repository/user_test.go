@@ -32,7 +32,7 @@ func setupRepository(t *testing.T) (repository.UserRepository, sqlmock.Sqlmock)
 
 	//rdb, _ := redismock.NewClientMock()
 
-	repo := repository.NewRepository(logger, db)
+	repo := repository.NewRepository(logger, db, nil, nil)
 	userRepo := repository.NewUserRepository(repo)
 
 	return userRepo, mock
@@ -41,7 +41,7 @@ func TestMain(m *testing.M) {
 	conf := config.NewConfig(*envConf)
 
 	logger = log.NewLog(conf)
-	j = jwt.NewJwt(conf)
+	j = jwt.NewJwt(conf, logger)
 	sf = sid.NewSid()
 
 	code := m.Run()